@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bytes of a password bcrypt hashes
+	MaxPasswordLength = 72
+
+	// MaxFailedAttempts is the number of consecutive failed logins or code
+	// verifications after which an account is locked for LockoutDuration.
+	MaxFailedAttempts = 5
+	LockoutDuration   = 15 * time.Minute
+
+	OTPLength     = 6
+	OTPTTL        = 10 * time.Minute
+	ResetTokenTTL = time.Hour
+)
+
+// HashPassword returns the bcrypt hash of a password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the bcrypt hash.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// GenerateOTP returns a random numeric one-time code.
+func GenerateOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < OTPLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", OTPLength, n), nil
+}
+
+// GenerateResetToken returns a random URL-safe password reset token.
+func GenerateResetToken() (string, error) {
+	return randomToken()
+}
+
+// GenerateCalendarToken returns a random URL-safe token authenticating a
+// user's calendar feeds.
+func GenerateCalendarToken() (string, error) {
+	return randomToken()
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashSecret hashes a secret (one-time code, reset or calendar token) for storage.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckSecret compares a secret against its stored hash in constant time.
+func CheckSecret(hash, secret string) bool {
+	if hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashSecret(secret))) == 1
+}
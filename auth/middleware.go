@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userLocalsKey = "user"
+
+// Middleware authenticates the request using the Bearer access token in the
+// Authorization header and stores the resolved user in c.Locals. Browsers
+// cannot set headers on WebSocket handshakes, so those may pass the token in
+// the access_token query parameter instead.
+func Middleware(users repository.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if header == "" && strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket") {
+			tokenString, found = c.Query("access_token"), true
+		}
+		if !found || tokenString == "" {
+			return problem.Unauthorized("Missing access token").WithCode(problem.CodeMissingToken)
+		}
+
+		claims, err := ParseToken(tokenString, TokenTypeAccess)
+		if err != nil {
+			return problem.Unauthorized("Invalid or expired access token").WithCode(problem.CodeInvalidToken)
+		}
+
+		userID, err := primitive.ObjectIDFromHex(claims.Subject)
+		if err != nil {
+			return problem.Unauthorized("Invalid or expired access token").WithCode(problem.CodeInvalidToken)
+		}
+
+		ctx, cancel := utils.DatabaseContext()
+		defer cancel()
+
+		user, err := users.FindByID(ctx, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return problem.Unauthorized("User no longer exists").WithCode(problem.CodeInvalidToken)
+		}
+		if err != nil {
+			return problem.Internal("Failed to fetch user", err)
+		}
+		if claims.Revoked(user) {
+			return problem.Unauthorized("Invalid or expired access token").WithCode(problem.CodeInvalidToken)
+		}
+
+		c.Locals(userLocalsKey, user)
+		return c.Next()
+	}
+}
+
+// CurrentUser returns the authenticated user stored by Middleware.
+// It returns nil when called on a route that is not behind the middleware.
+func CurrentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals(userLocalsKey).(*models.User)
+	return user
+}
+
+// RequirePermission rejects requests from users whose role lacks the permission.
+// It must be mounted after Middleware.
+func RequirePermission(perm policy.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !policy.Has(CurrentUser(c), perm) {
+			return problem.Forbidden("You do not have permission to perform this action")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the JWT claims carried by both access and refresh tokens.
+type Claims struct {
+	Email     string `json:"email"`
+	TokenType string `json:"typ"`
+	// Version is the token version of the user when the token was issued
+	Version int `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Revoked reports whether the token was issued before the user's tokens were
+// revoked, such as by a password reset
+func (c *Claims) Revoked(user *models.User) bool {
+	return c.Version != user.Credentials.TokenVersion
+}
+
+// TokenPair is returned to the client after a successful register or refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+var (
+	secret     []byte
+	secretOnce sync.Once
+)
+
+// SetSecret sets the HMAC key used to sign tokens. It must be called before
+// any token is issued or checked; an empty secret is ignored.
+func SetSecret(s string) {
+	if s == "" {
+		return
+	}
+	secretOnce.Do(func() {
+		secret = []byte(s)
+	})
+}
+
+// signingKey returns the HMAC key used to sign tokens. If no secret was set
+// a random key is generated, which means tokens do not survive a restart.
+func signingKey() []byte {
+	secretOnce.Do(func() {
+		log.Println("No JWT secret configured, using a random signing key")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal(err)
+		}
+	})
+	return secret
+}
+
+// IssueTokens creates a new access + refresh token pair for the user.
+func IssueTokens(user models.User) (TokenPair, error) {
+	access, err := sign(user, TokenTypeAccess, AccessTokenTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := sign(user, TokenTypeRefresh, RefreshTokenTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func sign(user models.User, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Email:     user.Email,
+		TokenType: tokenType,
+		Version:   user.Credentials.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey())
+}
+
+// ParseToken verifies the signature and expiry of a token and checks that it
+// is of the expected type (access or refresh).
+func ParseToken(tokenString, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return signingKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if claims.TokenType != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseToken(t *testing.T) {
+	SetSecret("test secret")
+	user := models.User{ID: primitive.NewObjectID(), Email: "tenant@example.com"}
+	tokens, err := IssueTokens(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+		wantErr   bool
+	}{
+		{"access token", tokens.AccessToken, TokenTypeAccess, false},
+		{"refresh token", tokens.RefreshToken, TokenTypeRefresh, false},
+		{"access token as refresh token", tokens.AccessToken, TokenTypeRefresh, true},
+		{"refresh token as access token", tokens.RefreshToken, TokenTypeAccess, true},
+		{"tampered signature", tokens.AccessToken[:len(tokens.AccessToken)-2] + "xx", TokenTypeAccess, true},
+		{"not a token", "not-a-token", TokenTypeAccess, true},
+		{"empty", "", TokenTypeAccess, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token, tt.tokenType)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject != user.ID.Hex() || claims.Email != user.Email {
+				t.Errorf("claims = %+v, want the user's ID and email", claims)
+			}
+		})
+	}
+}
+
+func TestClaimsRevoked(t *testing.T) {
+	tests := []struct {
+		name         string
+		issued       int
+		tokenVersion int
+		want         bool
+	}{
+		{"issued at the current version", 0, 0, false},
+		{"issued before a password reset", 0, 1, true},
+		{"issued after two resets", 2, 2, false},
+		{"issued before the latest reset", 1, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := Claims{Version: tt.issued}
+			user := models.User{Credentials: models.Credentials{TokenVersion: tt.tokenVersion}}
+			if got := claims.Revoked(&user); got != tt.want {
+				t.Errorf("Revoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
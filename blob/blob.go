@@ -0,0 +1,98 @@
+// Package blob stores uploaded files such as pictures and hands out the URLs
+// they are served from.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps files under slash separated keys. Only a local filesystem
+// store exists so far; an S3-compatible one can be added behind the same
+// interface.
+type Store interface {
+	// Put stores the content under the key, replacing any existing file
+	Put(ctx context.Context, key, contentType string, content io.Reader) error
+	// Delete removes the file under the key. Missing files are not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients fetch the file from
+	URL(key string) string
+}
+
+// ErrInvalidKey is returned for keys that would escape the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// New returns a LocalStore writing to dir whose files are served under
+// baseURL, LocalPath when empty.
+func New(dir, baseURL string) Store {
+	if baseURL == "" {
+		baseURL = LocalPath
+	}
+	return &LocalStore{Dir: dir, BaseURL: baseURL}
+}
+
+// LocalPath is the route the API serves LocalStore files from
+const LocalPath = "/media"
+
+// LocalStore keeps files in a directory. The API serves them under
+// LocalPath; BaseURL can point at a CDN or proxy in front of it instead.
+type LocalStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func (s *LocalStore) Put(_ context.Context, key, _ string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial file
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+}
+
+// path returns the file of the key, refusing keys outside the directory
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
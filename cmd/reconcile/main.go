@@ -0,0 +1,52 @@
+// Command reconcile reports drift between users and properties and, with
+// -fix, repairs it. Run it after an outage or a failed compensating action.
+package main
+
+import (
+	"context"
+	"dwello-api/config"
+	"dwello-api/reconcile"
+	"dwello-api/repository/mongodb"
+	"dwello-api/utils"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "repair the issues instead of only reporting them")
+	timeout := flag.Int("timeout", 300, "timeout in seconds")
+	configPath := flag.String("config", os.Getenv("DWELLO_CONFIG"), "path to a YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	config.ConnectDB(cfg.Mongo)
+	defer func() {
+		if err := config.DisconnectDB(context.Background()); err != nil {
+			log.Println("Failed to disconnect from MongoDB:", err)
+		}
+	}()
+
+	ctx, cancel := utils.CustomTimeout(*timeout)
+	defer cancel()
+
+	report, err := reconcile.Run(ctx, mongodb.NewStore(config.DB), *fix)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%d issues found\n", len(report.Issues))
+	if *fix {
+		fmt.Printf("%d repaired, %d failed\n", report.Repaired, report.Failed)
+	} else if len(report.Issues) > 0 {
+		fmt.Println("Run again with -fix to repair them")
+	}
+}
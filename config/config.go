@@ -0,0 +1,345 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"dwello-api/payments"
+	"dwello-api/push"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings of the API. They start from the defaults, are
+// overridden by the optional YAML file and then by environment variables.
+type Config struct {
+	// Store is where data is kept, mongodb or memory
+	Store    string   `yaml:"store" env:"DWELLO_STORE"`
+	Server   Server   `yaml:"server"`
+	Mongo    Mongo    `yaml:"mongo"`
+	Auth     Auth     `yaml:"auth"`
+	Media    Media    `yaml:"media"`
+	Uploads  Uploads  `yaml:"uploads"`
+	Mail     Mail     `yaml:"mail"`
+	Payments Payments `yaml:"payments"`
+	Push     Push     `yaml:"push"`
+	Features Features `yaml:"features"`
+}
+
+// Server configures the HTTP server
+type Server struct {
+	Addr string `yaml:"addr" env:"DWELLO_ADDR"`
+	// CORSOrigins are the origins browsers may call the API from. CORS is
+	// off when empty; "*" allows any origin.
+	CORSOrigins []string `yaml:"cors_origins" env:"DWELLO_CORS_ORIGINS"`
+	// Zero timeouts never expire
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"DWELLO_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"DWELLO_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"DWELLO_IDLE_TIMEOUT"`
+	// ShutdownTimeout bounds how long in-flight requests and background jobs
+	// are waited for on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"DWELLO_SHUTDOWN_TIMEOUT"`
+}
+
+// Mongo configures the database connection
+type Mongo struct {
+	// URI may carry credentials, Dump hides its password
+	URI            string        `yaml:"uri" env:"DWELLO_MONGO_URI"`
+	Database       string        `yaml:"database" env:"DWELLO_MONGO_DATABASE"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"DWELLO_MONGO_CONNECT_TIMEOUT"`
+	// QueryTimeout bounds the database work of a single request
+	QueryTimeout time.Duration `yaml:"query_timeout" env:"DWELLO_MONGO_QUERY_TIMEOUT"`
+	// MigrateTimeout bounds the migrations and index builds at startup
+	MigrateTimeout time.Duration `yaml:"migrate_timeout" env:"DWELLO_MONGO_MIGRATE_TIMEOUT"`
+}
+
+// Auth configures token signing
+type Auth struct {
+	// JWTSecret signs the tokens. A random one is generated when empty,
+	// which means tokens do not survive a restart.
+	JWTSecret string `yaml:"jwt_secret" env:"DWELLO_JWT_SECRET" secret:"true"`
+}
+
+// Media configures where uploaded files are stored and served from
+type Media struct {
+	Dir string `yaml:"dir" env:"DWELLO_MEDIA_DIR"`
+	// URL is the address files are served under, a path on the API or the
+	// URL of a CDN
+	URL string `yaml:"url" env:"DWELLO_MEDIA_URL"`
+}
+
+// Uploads bounds the pictures users upload
+type Uploads struct {
+	MaxSizeMB     int `yaml:"max_size_mb" env:"DWELLO_MAX_UPLOAD_MB"`
+	MaxMegapixels int `yaml:"max_megapixels" env:"DWELLO_MAX_UPLOAD_MEGAPIXELS"`
+}
+
+// Mail configures outgoing email
+type Mail struct {
+	// File receives the emails when set, otherwise they are printed to stdout
+	File string `yaml:"file" env:"DWELLO_MAIL_FILE"`
+}
+
+// Payments configures the provider tenants pay invoices through
+type Payments struct {
+	// Provider is the name of the provider. Online payments are off when
+	// empty; "fake" accepts every charge without moving money.
+	Provider string `yaml:"provider" env:"DWELLO_PAYMENTS_PROVIDER"`
+}
+
+// Push configures push notifications to mobile devices
+type Push struct {
+	// Sender is the name of the sender. Push notifications are off when
+	// empty; "fake" logs them instead of sending them.
+	Sender string `yaml:"sender" env:"DWELLO_PUSH_SENDER"`
+}
+
+// Features turns optional parts of the API on or off
+type Features struct {
+	// Swagger serves the API documentation under /swagger
+	Swagger bool `yaml:"swagger" env:"DWELLO_SWAGGER"`
+	// Workers runs the background jobs. Turn it off on all but one instance
+	// to keep the jobs out of the API servers.
+	Workers bool `yaml:"workers" env:"DWELLO_WORKERS"`
+}
+
+const (
+	StoreMongoDB = "mongodb"
+	StoreMemory  = "memory"
+)
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		Store: StoreMongoDB,
+		Server: Server{
+			Addr:            ":8080",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Mongo: Mongo{
+			URI:            "mongodb://localhost:27017/",
+			Database:       "dwello",
+			ConnectTimeout: 10 * time.Second,
+			QueryTimeout:   10 * time.Second,
+			MigrateTimeout: 60 * time.Second,
+		},
+		Media: Media{
+			Dir: "media",
+			URL: "/media",
+		},
+		Uploads: Uploads{
+			MaxSizeMB:     10,
+			MaxMegapixels: 40,
+		},
+		Features: Features{
+			Swagger: true,
+			Workers: true,
+		},
+	}
+}
+
+// Load reads the configuration from the YAML file at path, if any, and the
+// environment, and checks it
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return cfg, err
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// readFile overrides the settings found in the file. Unknown keys are an
+// error so that typos do not go unnoticed.
+func (c *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides the fields of v that have an env tag with the variables
+// that are set
+func applyEnv(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value); err != nil {
+				return err
+			}
+			continue
+		}
+		name := field.Tag.Get("env")
+		raw, ok := os.LookupEnv(name)
+		if name == "" || !ok {
+			continue
+		}
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses raw into v. Lists are comma separated.
+func setValue(v reflect.Value, raw string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Validate reports every setting that is invalid
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Store == StoreMongoDB || c.Store == StoreMemory, "store must be %s or %s", StoreMongoDB, StoreMemory)
+	check(c.Server.Addr != "", "server.addr is required")
+	for _, origin := range c.Server.CORSOrigins {
+		check(validOrigin(origin), "server.cors_origins: %q is not an origin such as https://app.example.com", origin)
+	}
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+
+	if c.Store == StoreMongoDB {
+		check(strings.HasPrefix(c.Mongo.URI, "mongodb://") || strings.HasPrefix(c.Mongo.URI, "mongodb+srv://"),
+			"mongo.uri must start with mongodb:// or mongodb+srv://")
+		check(c.Mongo.Database != "", "mongo.database is required")
+	}
+	check(c.Mongo.ConnectTimeout > 0, "mongo.connect_timeout must be positive")
+	check(c.Mongo.QueryTimeout > 0, "mongo.query_timeout must be positive")
+	check(c.Mongo.MigrateTimeout > 0, "mongo.migrate_timeout must be positive")
+
+	check(c.Media.Dir != "", "media.dir is required")
+	check(c.Media.URL != "", "media.url is required")
+	check(c.Uploads.MaxSizeMB > 0 && c.Uploads.MaxSizeMB <= 100, "uploads.max_size_mb must be between 1 and 100")
+	check(c.Uploads.MaxMegapixels > 0 && c.Uploads.MaxMegapixels <= 200, "uploads.max_megapixels must be between 1 and 200")
+	check(c.Payments.Provider == "" || c.Payments.Provider == payments.ProviderFake, "payments.provider must be empty or %s", payments.ProviderFake)
+	check(c.Push.Sender == "" || c.Push.Sender == push.SenderFake, "push.sender must be empty or %s", push.SenderFake)
+
+	return errors.Join(errs...)
+}
+
+// validOrigin reports whether origin is "*" or a scheme and host without a
+// path
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.User == nil
+}
+
+// MaxUploadBytes is the largest picture upload accepted, in bytes
+func (u Uploads) MaxUploadBytes() int {
+	return u.MaxSizeMB << 20
+}
+
+// MaxPixels bounds the decoded size of a picture upload
+func (u Uploads) MaxPixels() int {
+	return u.MaxMegapixels * 1_000_000
+}
+
+// redacted replaces the secrets found in the settings
+const redacted = "REDACTED"
+
+// Dump writes the configuration as YAML with its secrets redacted. Only the
+// password of the MongoDB URI is hidden so that the host stays visible.
+func (c Config) Dump(w io.Writer) error {
+	redact(reflect.ValueOf(&c).Elem())
+	c.Mongo.URI = redactURI(c.Mongo.URI)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// redact blanks the non-empty string fields of v tagged secret
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			redact(value)
+			continue
+		}
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString(redacted)
+		}
+	}
+}
+
+// redactURI hides the password of a connection string. URIs that cannot be
+// parsed are hidden entirely.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
@@ -3,7 +3,6 @@ package config
 import (
 	"context"
 	"log"
-	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -12,12 +11,11 @@ import (
 var DB *mongo.Database
 var client *mongo.Client // Store the client globally to manage its lifecycle
 
-func ConnectDB() {
-	uri := "mongodb://localhost:27017/"
+// ConnectDB connects to the configured MongoDB server and selects the database
+func ConnectDB(cfg Mongo) {
+	clientOptions := options.Client().ApplyURI(cfg.URI)
 
-	clientOptions := options.Client().ApplyURI(uri)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
 	var err error
@@ -31,17 +29,18 @@ func ConnectDB() {
 		log.Fatal(err)
 	}
 
-	DB = client.Database("dwello")
+	DB = client.Database(cfg.Database)
 
 	log.Println("Connected to MongoDB!")
 }
 
-// DisconnectDB disconnects the MongoDB client.
-func DisconnectDB() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// PingDB checks that the MongoDB server answers
+func PingDB(ctx context.Context) error {
+	return client.Ping(ctx, nil)
+}
 
-	if err := client.Disconnect(ctx); err != nil {
-		log.Fatal(err)
-	}
+// DisconnectDB disconnects the MongoDB client, waiting for the operations in
+// progress until ctx is done.
+func DisconnectDB(ctx context.Context) error {
+	return client.Disconnect(ctx)
 }
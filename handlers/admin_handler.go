@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"dwello-api/models"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminHandler serves the admin-only /api/admin routes
+type AdminHandler struct {
+	users repository.UserRepository
+}
+
+func NewAdminHandler(users repository.UserRepository) *AdminHandler {
+	return &AdminHandler{users: users}
+}
+
+// ListUsers lists all users, optionally filtered by role
+// @Summary List Users
+// @Description List all users, optionally filtered by role. Admin only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Role filter" Enums(tenant, owner, agent, admin)
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of users"
+// @Success 200 {object} models.PageSwagger[models.UserSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/admin/users [get]
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	filter := repository.UserFilter{Role: models.Role(c.Query("role"))}
+	return listPage(c, h.users, filter, func(u *models.User) primitive.ObjectID { return u.ID }, "Failed to fetch users")
+}
+
+// UpdateUserRole sets any user's role
+// @Summary Update User Role
+// @Description Set the role of any user. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param email path string true "User Email"
+// @Param role body map[string]string true "Role JSON"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/admin/users/{email}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *fiber.Ctx) error {
+	payload, err := bind[roleRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	err = h.users.SetRole(ctx, c.Params("email"), payload.Role)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("User not found")
+	}
+	if err != nil {
+		return problem.Internal("Update failed", err)
+	}
+	return c.JSON(fiber.Map{"message": "Role updated"})
+}
@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/mailer"
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuthHandler serves the public /api/auth routes
+type AuthHandler struct {
+	users  repository.UserRepository
+	mailer mailer.Mailer
+}
+
+func NewAuthHandler(users repository.UserRepository, m mailer.Mailer) *AuthHandler {
+	return &AuthHandler{users: users, mailer: m}
+}
+
+// AuthResponse is returned by the endpoints that issue tokens
+type AuthResponse struct {
+	User models.User `json:"user"`
+	auth.TokenPair
+}
+
+// registerRequest is the body of RegisterUser
+type registerRequest struct {
+	Email              string           `json:"email" validate:"required,email,max=254"`
+	Password           string           `json:"password" validate:"required"`
+	Name               string           `json:"name" validate:"max=100"`
+	Role               string           `json:"role"`
+	ProfilePic         string           `json:"profile_pic" validate:"max=2048"`
+	Location           string           `json:"location" validate:"max=100"`
+	Coordinates        *models.GeoPoint `json:"coordinates"`
+	PreferredLocations []string         `json:"preferred_locations" validate:"max=20,unique,dive,required,max=100"`
+}
+
+func (r *registerRequest) normalize() {
+	r.Email = normalizeEmail(r.Email)
+	r.Name = strings.TrimSpace(r.Name)
+	r.Location = strings.TrimSpace(r.Location)
+	r.PreferredLocations = trimAll(r.PreferredLocations)
+}
+
+type loginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (r *loginRequest) normalize() {
+	r.Email = normalizeEmail(r.Email)
+}
+
+// emailRequest is the body of the endpoints that email a code or a token
+type emailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (r *emailRequest) normalize() {
+	r.Email = normalizeEmail(r.Email)
+}
+
+type verifyOTPRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Code  string `json:"code" validate:"required"`
+}
+
+func (r *verifyOTPRequest) normalize() {
+	r.Email = normalizeEmail(r.Email)
+	r.Code = strings.TrimSpace(r.Code)
+}
+
+type resetPasswordRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (r *resetPasswordRequest) normalize() {
+	r.Email = normalizeEmail(r.Email)
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// checkPassword checks the length of a new password, which is at least
+// auth.MinPasswordLength characters and at most auth.MaxPasswordLength bytes
+func checkPassword(password string) error {
+	if utf8.RuneCountInString(password) < auth.MinPasswordLength {
+		return invalidField("password", fmt.Sprintf("must be at least %d characters", auth.MinPasswordLength))
+	}
+	if len(password) > auth.MaxPasswordLength {
+		return invalidField("password", fmt.Sprintf("must be at most %d bytes", auth.MaxPasswordLength))
+	}
+	return nil
+}
+
+// checkOTP checks that a one-time code is auth.OTPLength digits
+func checkOTP(code string) error {
+	if len(code) != auth.OTPLength || strings.Trim(code, "0123456789") != "" {
+		return invalidField("code", fmt.Sprintf("must be %d digits", auth.OTPLength))
+	}
+	return nil
+}
+
+// RegisterUser creates a new account with a password
+// @Summary Register User
+// @Description Create a new account with an email and password and return an access and refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param user body models.RegisterSwagger true "Registration JSON"
+// @Success 201 {object} models.AuthSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/auth/register [post]
+func (h *AuthHandler) RegisterUser(c *fiber.Ctx) error {
+	payload, err := bind[registerRequest](c)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(payload.Password); err != nil {
+		return err
+	}
+	if payload.Coordinates != nil && !payload.Coordinates.Valid() {
+		return invalidField("coordinates", invalidCoordinates)
+	}
+
+	role := models.RoleTenant
+	if payload.Role != "" {
+		role = models.Role(payload.Role)
+		if !policy.IsSelfAssignable(role) {
+			return invalidField("role", "must be tenant or owner")
+		}
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	hash, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		return problem.Internal("Failed to register user", err)
+	}
+
+	user := models.User{
+		ID:                 primitive.NewObjectID(),
+		Email:              payload.Email,
+		Name:               payload.Name,
+		Role:               role,
+		ProfilePic:         payload.ProfilePic,
+		Location:           payload.Location,
+		Coordinates:        payload.Coordinates,
+		PreferredLocations: payload.PreferredLocations,
+		PostedProperties:   []primitive.ObjectID{},
+		LikedProperties:    []primitive.ObjectID{},
+		Credentials:        models.Credentials{PasswordHash: hash},
+		CreatedAt:          primitive.NewDateTimeFromTime(utils.Now()),
+	}
+	user.UpdatedAt = user.CreatedAt
+
+	err = h.users.Create(ctx, &user)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return problem.Conflict("An account with this email already exists")
+	}
+	if err != nil {
+		return problem.Internal("Failed to register user", err)
+	}
+
+	return respondWithTokens(c, fiber.StatusCreated, user)
+}
+
+// LoginUser authenticates a user with email and password
+// @Summary Login
+// @Description Log in with email and password. The account is locked after repeated failures.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body map[string]string true "Email and password JSON"
+// @Success 200 {object} models.AuthSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 423 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/auth/login [post]
+func (h *AuthHandler) LoginUser(c *fiber.Ctx) error {
+	payload, err := bind[loginRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return problem.Unauthorized("Invalid email or password").WithCode(problem.CodeInvalidCredentials)
+	}
+
+	if isLocked(user) {
+		return problem.New(fiber.StatusLocked, "Account is temporarily locked, try again later").WithCode(problem.CodeAccountLocked)
+	}
+
+	if !auth.CheckPassword(user.Credentials.PasswordHash, payload.Password) {
+		h.recordFailedAttempt(ctx, user)
+		return problem.Unauthorized("Invalid email or password").WithCode(problem.CodeInvalidCredentials)
+	}
+
+	if err := h.users.ClearLockout(ctx, user.ID, false); err != nil {
+		return problem.Internal("Failed to log in", err)
+	}
+
+	return respondWithTokens(c, fiber.StatusOK, *user)
+}
+
+// RequestOTP sends a one-time login code to the user's email
+// @Summary Request Login Code
+// @Description Email a one-time login code. Always succeeds so that account existence is not revealed.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Email JSON"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Router /api/auth/otp/request [post]
+func (h *AuthHandler) RequestOTP(c *fiber.Ctx) error {
+	payload, err := bind[emailRequest](c)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{"message": "If an account exists for this email, a code has been sent"}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByEmail(ctx, payload.Email)
+	if err != nil || isLocked(user) {
+		return c.JSON(response)
+	}
+
+	code, err := auth.GenerateOTP()
+	if err != nil {
+		return problem.Internal("Failed to generate code", err)
+	}
+
+	if err := h.users.SetOTP(ctx, user.ID, auth.HashSecret(code), utils.Now().Add(auth.OTPTTL)); err != nil {
+		return problem.Internal("Failed to generate code", err)
+	}
+
+	body := fmt.Sprintf("Your Dwello login code is %s. It expires in %d minutes.", code, int(auth.OTPTTL.Minutes()))
+	if err := h.mailer.Send(ctx, user.Email, "Your Dwello login code", body); err != nil {
+		log.Println("Failed to send login code:", err)
+	}
+
+	return c.JSON(response)
+}
+
+// VerifyOTP logs a user in with a one-time code
+// @Summary Verify Login Code
+// @Description Exchange a one-time login code for an access and refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Email and code JSON"
+// @Success 200 {object} models.AuthSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 423 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/auth/otp/verify [post]
+func (h *AuthHandler) VerifyOTP(c *fiber.Ctx) error {
+	payload, err := bind[verifyOTPRequest](c)
+	if err != nil {
+		return err
+	}
+	if err := checkOTP(payload.Code); err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return problem.Unauthorized("Invalid or expired code").WithCode(problem.CodeInvalidCode)
+	}
+
+	if isLocked(user) {
+		return problem.New(fiber.StatusLocked, "Account is temporarily locked, try again later").WithCode(problem.CodeAccountLocked)
+	}
+
+	expired := user.Credentials.OTPExpiresAt.Time().Before(utils.Now())
+	if expired || !auth.CheckSecret(user.Credentials.OTPHash, payload.Code) {
+		h.recordFailedAttempt(ctx, user)
+		return problem.Unauthorized("Invalid or expired code").WithCode(problem.CodeInvalidCode)
+	}
+
+	// Codes are single use
+	if err := h.users.ClearLockout(ctx, user.ID, true); err != nil {
+		return problem.Internal("Failed to log in", err)
+	}
+
+	return respondWithTokens(c, fiber.StatusOK, *user)
+}
+
+// ForgotPassword emails a password reset token
+// @Summary Request Password Reset
+// @Description Email a password reset token. Always succeeds so that account existence is not revealed.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Email JSON"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Router /api/auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	payload, err := bind[emailRequest](c)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{"message": "If an account exists for this email, a reset link has been sent"}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return c.JSON(response)
+	}
+
+	token, err := auth.GenerateResetToken()
+	if err != nil {
+		return problem.Internal("Failed to generate reset token", err)
+	}
+
+	if err := h.users.SetResetToken(ctx, user.ID, auth.HashSecret(token), utils.Now().Add(auth.ResetTokenTTL)); err != nil {
+		return problem.Internal("Failed to generate reset token", err)
+	}
+
+	body := fmt.Sprintf("Use this token to reset your Dwello password: %s\nIt expires in %d minutes.", token, int(auth.ResetTokenTTL.Minutes()))
+	if err := h.mailer.Send(ctx, user.Email, "Reset your Dwello password", body); err != nil {
+		log.Println("Failed to send password reset email:", err)
+	}
+
+	return c.JSON(response)
+}
+
+// ResetPassword sets a new password using a reset token
+// @Summary Reset Password
+// @Description Set a new password using a token from the reset email. Also clears any lockout and signs the user out of every device.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Email, token and new password JSON"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	payload, err := bind[resetPasswordRequest](c)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(payload.Password); err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return problem.Unauthorized("Invalid or expired reset token").WithCode(problem.CodeInvalidResetToken)
+	}
+
+	expired := user.Credentials.ResetExpiresAt.Time().Before(utils.Now())
+	if expired || !auth.CheckSecret(user.Credentials.ResetTokenHash, payload.Token) {
+		return problem.Unauthorized("Invalid or expired reset token").WithCode(problem.CodeInvalidResetToken)
+	}
+
+	hash, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		return problem.Internal("Failed to reset password", err)
+	}
+
+	if err := h.users.SetPassword(ctx, user.ID, hash); err != nil {
+		return problem.Internal("Failed to reset password", err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Password updated"})
+}
+
+// RefreshToken exchanges a refresh token for a new token pair
+// @Summary Refresh Tokens
+// @Description Exchange a valid refresh token for a new access and refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Refresh token JSON"
+// @Success 200 {object} models.AuthSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
+	payload, err := bind[refreshRequest](c)
+	if err != nil {
+		return err
+	}
+
+	claims, err := auth.ParseToken(payload.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		return problem.Unauthorized("Invalid or expired refresh token").WithCode(problem.CodeInvalidRefreshToken)
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return problem.Unauthorized("Invalid or expired refresh token").WithCode(problem.CodeInvalidRefreshToken)
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Unauthorized("User no longer exists").WithCode(problem.CodeInvalidRefreshToken)
+	}
+	if err != nil {
+		return problem.Internal("Failed to fetch user", err)
+	}
+	if claims.Revoked(user) {
+		return problem.Unauthorized("Invalid or expired refresh token").WithCode(problem.CodeInvalidRefreshToken)
+	}
+
+	return respondWithTokens(c, fiber.StatusOK, *user)
+}
+
+// respondWithTokens issues a token pair for the user and writes it with the user document
+func respondWithTokens(c *fiber.Ctx, status int, user models.User) error {
+	tokens, err := auth.IssueTokens(user)
+	if err != nil {
+		return problem.Internal("Failed to issue tokens", err)
+	}
+	return c.Status(status).JSON(AuthResponse{User: user, TokenPair: tokens})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func isLocked(user *models.User) bool {
+	return user.Credentials.LockedUntil.Time().After(utils.Now())
+}
+
+// recordFailedAttempt increments the failure counter and locks the account
+// once it reaches auth.MaxFailedAttempts.
+func (h *AuthHandler) recordFailedAttempt(ctx context.Context, user *models.User) {
+	var err error
+	if user.Credentials.FailedAttempts+1 >= auth.MaxFailedAttempts {
+		err = h.users.Lock(ctx, user.ID, utils.Now().Add(auth.LockoutDuration))
+	} else {
+		err = h.users.IncrementFailedAttempts(ctx, user.ID)
+	}
+
+	if err != nil {
+		log.Println("Failed to record failed login attempt:", err)
+	}
+}
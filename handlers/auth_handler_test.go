@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+
+	"dwello-api/auth"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/repository/memory"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const testPassword = "Passw0rd!123"
+
+// inbox is a mailer keeping the last email sent to each address
+type inbox struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func (m *inbox) Send(_ context.Context, to, _, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.last == nil {
+		m.last = map[string]string{}
+	}
+	m.last[to] = body
+	return nil
+}
+
+func (m *inbox) lastTo(to string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last[to]
+}
+
+// newAuthApp serves the auth routes and GET /me behind the auth middleware
+func newAuthApp(users repository.UserRepository, m *inbox) *fiber.App {
+	app := newTestApp()
+	h := NewAuthHandler(users, m)
+	app.Post("/api/auth/register", h.RegisterUser)
+	app.Post("/api/auth/login", h.LoginUser)
+	app.Post("/api/auth/password/forgot", h.ForgotPassword)
+	app.Post("/api/auth/password/reset", h.ResetPassword)
+	app.Post("/api/auth/refresh", h.RefreshToken)
+	app.Get("/me", auth.Middleware(users), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"email": auth.CurrentUser(c).Email})
+	})
+	return app
+}
+
+// register creates an account and returns its tokens
+func register(t *testing.T, app *fiber.App, email string) (access, refresh string) {
+	t.Helper()
+	status, body := call(t, app, http.MethodPost, "/api/auth/register", "", fiber.Map{"email": email, "password": testPassword})
+	if status != http.StatusCreated {
+		t.Fatalf("register %s: status %d, body %v", email, status, body)
+	}
+	return body["access_token"].(string), body["refresh_token"].(string)
+}
+
+func TestLoginLockout(t *testing.T) {
+	app := newAuthApp(memory.NewStore().Users, &inbox{})
+	register(t, app, "tenant@example.com")
+
+	type attempt struct {
+		name     string
+		password string
+		status   int
+		code     string
+	}
+	// Each attempt runs after the previous ones
+	attempts := []attempt{
+		{"first failure", "wrong", http.StatusUnauthorized, problem.CodeInvalidCredentials},
+		{"success resets the count", testPassword, http.StatusOK, ""},
+	}
+	for range auth.MaxFailedAttempts - 1 {
+		attempts = append(attempts, attempt{"failure before the lock", "wrong", http.StatusUnauthorized, problem.CodeInvalidCredentials})
+	}
+	attempts = append(attempts,
+		attempt{"failure that locks", "wrong", http.StatusUnauthorized, problem.CodeInvalidCredentials},
+		attempt{"right password while locked", testPassword, http.StatusLocked, problem.CodeAccountLocked},
+		attempt{"wrong password while locked", "wrong", http.StatusLocked, problem.CodeAccountLocked},
+	)
+
+	for _, tt := range attempts {
+		status, body := call(t, app, http.MethodPost, "/api/auth/login", "", fiber.Map{"email": "tenant@example.com", "password": tt.password})
+		if status != tt.status {
+			t.Fatalf("%s: status %d, want %d (body %v)", tt.name, status, tt.status, body)
+		}
+		if tt.code != "" && body["code"] != tt.code {
+			t.Fatalf("%s: code %v, want %s", tt.name, body["code"], tt.code)
+		}
+	}
+}
+
+func TestPasswordResetRevokesTokens(t *testing.T) {
+	m := &inbox{}
+	app := newAuthApp(memory.NewStore().Users, m)
+	access, refresh := register(t, app, "tenant@example.com")
+
+	call(t, app, http.MethodPost, "/api/auth/password/forgot", "", fiber.Map{"email": "tenant@example.com"})
+	token := regexp.MustCompile(`[0-9a-f]{64}`).FindString(m.lastTo("tenant@example.com"))
+	if token == "" {
+		t.Fatal("no reset token was emailed")
+	}
+	status, body := call(t, app, http.MethodPost, "/api/auth/password/reset", "", fiber.Map{
+		"email": "tenant@example.com", "token": token, "password": "N3w-Passw0rd!",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("reset: status %d, body %v", status, body)
+	}
+
+	_, login := call(t, app, http.MethodPost, "/api/auth/login", "", fiber.Map{"email": "tenant@example.com", "password": "N3w-Passw0rd!"})
+	newAccess, _ := login["access_token"].(string)
+	newRefresh, _ := login["refresh_token"].(string)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		status int
+	}{
+		{"old access token", http.MethodGet, "/me", access, nil, http.StatusUnauthorized},
+		{"old refresh token", http.MethodPost, "/api/auth/refresh", "", fiber.Map{"refresh_token": refresh}, http.StatusUnauthorized},
+		{"access token used to refresh", http.MethodPost, "/api/auth/refresh", "", fiber.Map{"refresh_token": newAccess}, http.StatusUnauthorized},
+		{"malformed refresh token", http.MethodPost, "/api/auth/refresh", "", fiber.Map{"refresh_token": "not-a-token"}, http.StatusUnauthorized},
+		{"new access token", http.MethodGet, "/me", newAccess, nil, http.StatusOK},
+		{"new refresh token", http.MethodPost, "/api/auth/refresh", "", fiber.Map{"refresh_token": newRefresh}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, app, tt.method, tt.path, tt.token, tt.body)
+			if status != tt.status {
+				t.Errorf("status %d, want %d (body %v)", status, tt.status, body)
+			}
+		})
+	}
+}
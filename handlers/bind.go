@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"dwello-api/problem"
+	"dwello-api/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// normalizer is implemented by request bodies that clean up their values,
+// such as trimming spaces, before they are validated
+type normalizer interface {
+	normalize()
+}
+
+// bind parses the body of the request into a T and checks it against the
+// validate tags of T. A body that cannot be parsed is a bad request and one
+// that breaks a rule a validation problem listing every invalid field.
+func bind[T any](c *fiber.Ctx) (*T, error) {
+	var input T
+	if err := c.BodyParser(&input); err != nil {
+		return nil, problem.BadRequest("Invalid body")
+	}
+	if err := check(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// bindOptional is bind for endpoints whose body may be left out, in which
+// case T keeps its zero value
+func bindOptional[T any](c *fiber.Ctx) (*T, error) {
+	if len(c.Body()) == 0 {
+		var input T
+		if err := check(&input); err != nil {
+			return nil, err
+		}
+		return &input, nil
+	}
+	return bind[T](c)
+}
+
+// check normalizes input and validates it against its validate tags
+func check[T any](input *T) error {
+	if n, ok := any(input).(normalizer); ok {
+		n.normalize()
+	}
+	if err := utils.Validate.Struct(input); err != nil {
+		return validationError("Invalid request", err)
+	}
+	return nil
+}
+
+// invalidField is a validation problem with a single field that breaks a
+// rule the validate tags cannot express
+func invalidField(field, message string) error {
+	return problem.Validation("Invalid request", problem.FieldError{Field: field, Message: message})
+}
+
+// trimAll trims the spaces around every item of list
+func trimAll(list []string) []string {
+	for i, item := range list {
+		list[i] = strings.TrimSpace(item)
+	}
+	return list
+}
@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/realtime"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Length of the latest message kept on conversations
+	messagePreviewLength = 100
+
+	// Event types pushed over the WebSocket
+	eventMessage = "message"
+	eventRead    = "read"
+
+	// Keepalive of WebSocket connections
+	pingInterval = 30 * time.Second
+	pongTimeout  = 60 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// ConversationHandler serves the /api/conversations routes and the
+// WebSocket delivering new messages and read receipts
+type ConversationHandler struct {
+	users         repository.UserRepository
+	properties    repository.PropertyRepository
+	conversations repository.ConversationRepository
+	messages      repository.MessageRepository
+	hub           *realtime.Hub
+}
+
+func NewConversationHandler(users repository.UserRepository, properties repository.PropertyRepository, conversations repository.ConversationRepository, messages repository.MessageRepository, hub *realtime.Hub) *ConversationHandler {
+	return &ConversationHandler{users: users, properties: properties, conversations: conversations, messages: messages, hub: hub}
+}
+
+// StartConversation godoc
+// @Summary Message a property's owner
+// @Description Send a message to the owner of a property, starting a conversation about it or continuing the existing one
+// @Tags Conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param message body models.MessageInputSwagger true "Message"
+// @Success 201 {object} models.ConversationSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/conversations [post]
+func (h *ConversationHandler) StartConversation(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+	body, err := parseMessageBody(c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+	if !policy.CanStartConversation(user, property) {
+		return problem.Forbidden("You cannot message yourself about your own property")
+	}
+
+	conversation, err := h.conversations.FindByPropertyAndTenant(ctx, propertyID, user.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		conversation, err = h.startConversation(ctx, user, property)
+	}
+	if err != nil {
+		return problem.Internal("Failed to start conversation", err)
+	}
+
+	if _, err := h.send(ctx, conversation, user, body); err != nil {
+		return problem.Internal("Failed to send message", err)
+	}
+	if conversation, err = h.conversations.FindByID(ctx, conversation.ID); err != nil {
+		return problem.Internal("Failed to fetch conversation", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(conversation)
+}
+
+// startConversation creates the user's conversation with the property's
+// owner. When a concurrent request created it first, that one is returned.
+func (h *ConversationHandler) startConversation(ctx context.Context, user *models.User, property *models.Property) (*models.Conversation, error) {
+	owner, err := h.users.FindByEmail(ctx, property.OwnerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	conversation := models.Conversation{
+		ID:            primitive.NewObjectID(),
+		PropertyID:    property.ID,
+		PropertyTitle: property.Title,
+		OwnerID:       owner.ID,
+		OwnerEmail:    owner.Email,
+		OwnerName:     owner.Name,
+		TenantID:      user.ID,
+		TenantEmail:   user.Email,
+		TenantName:    user.Name,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := h.conversations.Create(ctx, &conversation); errors.Is(err, repository.ErrDuplicate) {
+		return h.conversations.FindByPropertyAndTenant(ctx, property.ID, user.ID)
+	} else if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// ListConversations godoc
+// @Summary List conversations
+// @Description List the conversations the authenticated user takes part in, as a tenant or as an owner, newest first, each with its number of unread messages
+// @Tags Conversations
+// @Produce json
+// @Security BearerAuth
+// @Param property_id query string false "Only conversations about this property"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of conversations"
+// @Success 200 {object} models.PageSwagger[models.ConversationSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/conversations [get]
+func (h *ConversationHandler) ListConversations(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	filter := repository.ConversationFilter{UserID: user.ID}
+	if id := c.Query("property_id"); id != "" {
+		propertyID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return problem.BadRequest("Invalid property ID")
+		}
+		filter.PropertyID = propertyID
+	}
+
+	page, err := parsePage(c, repository.SortCreatedAt)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	conversations, err := h.conversations.List(ctx, filter, peek(page))
+	if err != nil {
+		return problem.Internal("Failed to fetch conversations", err)
+	}
+	response := newPage(conversations, page, func(conversation *models.Conversation) (float64, primitive.ObjectID) {
+		return repository.ByCreation(conversation.ID)
+	})
+	for i := range response.Items {
+		if err := h.countUnread(ctx, &response.Items[i], user.ID); err != nil {
+			return problem.Internal("Failed to fetch conversations", err)
+		}
+	}
+	if err := countTotal(c, &response, func() (int64, error) { return h.conversations.Count(ctx, filter) }); err != nil {
+		return problem.Internal("Failed to fetch conversations", err)
+	}
+	return c.JSON(response)
+}
+
+// GetConversation godoc
+// @Summary Get a conversation
+// @Tags Conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} models.ConversationSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/conversations/{id} [get]
+func (h *ConversationHandler) GetConversation(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	conversation, err := h.findConversation(ctx, c)
+	if err != nil {
+		return err
+	}
+	if err := h.countUnread(ctx, conversation, auth.CurrentUser(c).ID); err != nil {
+		return problem.Internal("Failed to fetch conversation", err)
+	}
+	return c.JSON(conversation)
+}
+
+// ListMessages godoc
+// @Summary List messages
+// @Description List the messages of a conversation, newest first. Each message tells whether its recipient has read it.
+// @Tags Conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of messages"
+// @Success 200 {object} models.PageSwagger[models.MessageSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/conversations/{id}/messages [get]
+func (h *ConversationHandler) ListMessages(c *fiber.Ctx) error {
+	page, err := parsePage(c, repository.SortCreatedAt)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	conversation, err := h.findConversation(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	filter := repository.MessageFilter{ConversationID: conversation.ID}
+	messages, err := h.messages.List(ctx, filter, peek(page))
+	if err != nil {
+		return problem.Internal("Failed to fetch messages", err)
+	}
+	response := newPage(messages, page, func(message *models.Message) (float64, primitive.ObjectID) {
+		return repository.ByCreation(message.ID)
+	})
+	for i := range response.Items {
+		setRead(conversation, &response.Items[i])
+	}
+	if err := countTotal(c, &response, func() (int64, error) { return h.messages.Count(ctx, filter) }); err != nil {
+		return problem.Internal("Failed to fetch messages", err)
+	}
+	return c.JSON(response)
+}
+
+// SendMessage godoc
+// @Summary Send a message
+// @Description Send a message in a conversation. Both participants connected to the WebSocket receive it right away.
+// @Tags Conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param message body models.MessageInputSwagger true "Message"
+// @Success 201 {object} models.MessageSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/conversations/{id}/messages [post]
+func (h *ConversationHandler) SendMessage(c *fiber.Ctx) error {
+	body, err := parseMessageBody(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	conversation, err := h.findConversation(ctx, c)
+	if err != nil {
+		return err
+	}
+	message, err := h.send(ctx, conversation, auth.CurrentUser(c), body)
+	if err != nil {
+		return problem.Internal("Failed to send message", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(message)
+}
+
+// MarkConversationRead godoc
+// @Summary Mark a conversation as read
+// @Description Mark every message received so far as read. The other participant is told over the WebSocket.
+// @Tags Conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} models.ConversationSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/conversations/{id}/read [post]
+func (h *ConversationHandler) MarkConversationRead(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	conversation, err := h.findConversation(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	now := utils.Now()
+	if err := h.conversations.MarkRead(ctx, conversation.ID, user.ID, now); err != nil {
+		return problem.Internal("Failed to mark conversation as read", err)
+	}
+	conversation, err = h.conversations.FindByID(ctx, conversation.ID)
+	if err != nil {
+		return problem.Internal("Failed to fetch conversation", err)
+	}
+
+	event := realtime.Event{Type: eventRead, Data: fiber.Map{
+		"conversation_id": conversation.ID,
+		"user_id":         user.ID,
+		"read_at":         conversation.ReadAt(user.ID),
+	}}
+	h.hub.Publish(conversation.OwnerID, event)
+	h.hub.Publish(conversation.TenantID, event)
+	return c.JSON(conversation)
+}
+
+// Connect godoc
+// @Summary Receive messages in real time
+// @Description Upgrade to a WebSocket that receives the authenticated user's new messages as {"type": "message", "data": message}, read receipts as {"type": "read", "data": {"conversation_id", "user_id", "read_at"}} and in-app notifications as {"type": "notification", "data": notification}. Browsers may pass the access token in the access_token query parameter.
+// @Tags Conversations
+// @Security BearerAuth
+// @Param access_token query string false "Access token, when it cannot be sent in the Authorization header"
+// @Success 101
+// @Failure 401 {object} problem.Problem
+// @Failure 426 {object} problem.Problem
+// @Router /api/ws [get]
+func (h *ConversationHandler) Connect(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return problem.New(fiber.StatusUpgradeRequired, "Expected a WebSocket upgrade")
+	}
+	userID := auth.CurrentUser(c).ID
+	return websocket.New(func(conn *websocket.Conn) { h.stream(conn, userID) })(c)
+}
+
+// stream writes the user's events to the connection until either side
+// closes it. Clients are pinged to detect dead connections; anything they
+// send is ignored.
+func (h *ConversationHandler) stream(conn *websocket.Conn, userID primitive.ObjectID) {
+	sub := h.hub.Subscribe(userID)
+	defer h.hub.Unsubscribe(sub)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		conn.SetPongHandler(func(string) error { return conn.SetReadDeadline(time.Now().Add(pongTimeout)) })
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// send stores a message from the user, makes it the conversation's latest
+// and pushes it to both participants
+func (h *ConversationHandler) send(ctx context.Context, conversation *models.Conversation, user *models.User, body string) (*models.Message, error) {
+	message := models.Message{
+		ID:             primitive.NewObjectID(),
+		ConversationID: conversation.ID,
+		SenderID:       user.ID,
+		SenderEmail:    user.Email,
+		Body:           body,
+		CreatedAt:      primitive.NewDateTimeFromTime(utils.Now()),
+	}
+	if err := h.messages.Create(ctx, &message); err != nil {
+		return nil, err
+	}
+	if err := h.conversations.RecordMessage(ctx, &message, preview(body)); err != nil {
+		return nil, err
+	}
+
+	event := realtime.Event{Type: eventMessage, Data: message}
+	h.hub.Publish(conversation.OwnerID, event)
+	h.hub.Publish(conversation.TenantID, event)
+	return &message, nil
+}
+
+// countUnread sets how many messages of the conversation the user has not read
+func (h *ConversationHandler) countUnread(ctx context.Context, conversation *models.Conversation, userID primitive.ObjectID) error {
+	unread, err := h.messages.Count(ctx, repository.MessageFilter{
+		ConversationID: conversation.ID,
+		SentAfter:      conversation.ReadAt(userID).Time(),
+		NotFrom:        userID,
+	})
+	conversation.Unread = unread
+	return err
+}
+
+// setRead sets whether the recipient of the message has read it
+func setRead(conversation *models.Conversation, message *models.Message) {
+	recipient := conversation.OtherParticipant(message.SenderID)
+	message.Read = conversation.ReadAt(recipient) >= message.CreatedAt
+}
+
+// findConversation loads the conversation in the id path parameter
+func (h *ConversationHandler) findConversation(ctx context.Context, c *fiber.Ctx) (*models.Conversation, error) {
+	conversationID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid conversation ID")
+	}
+
+	// Other users' conversations are reported as missing
+	conversation, err := h.conversations.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, findError(err, "Conversation")
+	}
+	if !policy.CanViewConversation(auth.CurrentUser(c), conversation) {
+		return nil, problem.NotFound("Conversation not found")
+	}
+	return conversation, nil
+}
+
+// messageRequest is a new message
+type messageRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
+
+func (r *messageRequest) normalize() {
+	r.Body = strings.TrimSpace(r.Body)
+}
+
+// parseMessageBody reads the body of a new message
+func parseMessageBody(c *fiber.Ctx) (string, error) {
+	input, err := bind[messageRequest](c)
+	if err != nil {
+		return "", err
+	}
+	return input.Body, nil
+}
+
+// preview returns the start of a message body
+func preview(body string) string {
+	if utf8.RuneCountInString(body) <= messagePreviewLength {
+		return body
+	}
+	return string([]rune(body)[:messagePreviewLength-1]) + "…"
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// findError maps the error of loading a document named what, such as
+// "Property": a missing document is a 404 and any other failure a 500 that
+// keeps its cause
+func findError(err error, what string) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound(what + " not found")
+	}
+	return problem.Internal("Failed to fetch "+strings.ToLower(what), err)
+}
+
+// searchError maps the error of a property search: a search combining
+// filters that cannot be used together is a 400 and any other failure a 500
+// with the given detail
+func searchError(err error, detail string) error {
+	if errors.Is(err, repository.ErrInvalidSearch) {
+		return problem.Validation(err.Error())
+	}
+	return problem.Internal(detail, err)
+}
+
+// validationError turns the error of utils.Validate into a problem listing
+// the invalid fields
+func validationError(detail string, err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return problem.Internal("Failed to validate the request", err)
+	}
+
+	fields := make([]problem.FieldError, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, problem.FieldError{Field: fieldPath(e), Message: fieldMessage(e)})
+	}
+	return problem.Validation(detail, fields...)
+}
+
+// fieldPath is the dotted JSON path of the field, without the struct that
+// was validated
+func fieldPath(e validator.FieldError) string {
+	_, path, found := strings.Cut(e.Namespace(), ".")
+	if !found {
+		return e.Field()
+	}
+	return path
+}
+
+// fieldMessage explains the rule the field broke. Bounds are lengths for
+// text and lists and values for numbers.
+func fieldMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + e.Param() + boundUnit(e)
+	case "max", "lte":
+		return "must be at most " + e.Param() + boundUnit(e)
+	case "gt":
+		return "must be greater than " + e.Param()
+	case "len":
+		return "must be exactly " + e.Param() + boundUnit(e)
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(e.Param()), ", ")
+	case "unique":
+		return "must not contain duplicates"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "numeric":
+		return "must contain only digits"
+	case "objectid":
+		return "must be a valid ID"
+	case "rfc3339":
+		return "must be an RFC 3339 timestamp"
+	case "date":
+		return "must be YYYY-MM-DD or RFC 3339"
+	}
+	return fmt.Sprintf("is invalid (%s)", e.Tag())
+}
+
+// boundUnit is what the bound of a length rule counts
+func boundUnit(e validator.FieldError) string {
+	unit := ""
+	switch e.Kind() {
+	case reflect.String:
+		unit = " character"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " item"
+	}
+	if unit != "" && e.Param() != "1" {
+		unit += "s"
+	}
+	return unit
+}
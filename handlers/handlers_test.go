@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"dwello-api/problem"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp returns an app rendering errors as problem details, like the
+// one main sets up
+func newTestApp() *fiber.App {
+	return fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+}
+
+// call sends a request with an optional JSON body and bearer token and
+// returns the status and the decoded JSON response
+func call(t *testing.T, app *fiber.App, method, path, token string, body any) (int, map[string]any) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil && err != io.EOF {
+		t.Fatalf("%s %s: decoding the response: %v", method, path, err)
+	}
+	return resp.StatusCode, decoded
+}
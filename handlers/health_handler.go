@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"dwello-api/health"
+	"dwello-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// readinessTimeout bounds the readiness checks in seconds, below the probe
+// timeout of the orchestrator
+const readinessTimeout = 3
+
+// HealthHandler serves the public liveness and readiness probes
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live reports that the process is up
+// @Summary Liveness
+// @Description Report that the process is up and serving requests. Checks no dependency, so a failing database does not get the API restarted.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Ready reports whether the API can take traffic
+// @Summary Readiness
+// @Description Check the database connection, the index build run at startup and the background jobs. Responds 503 with the failing checks when any of them is not ready.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Result
+// @Failure 503 {object} health.Result
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	ctx, cancel := utils.CustomTimeout(readinessTimeout)
+	defer cancel()
+
+	result := h.checker.Run(ctx)
+	if !result.Ready {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(result)
+	}
+	return c.JSON(result)
+}
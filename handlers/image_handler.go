@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+
+	"dwello-api/auth"
+	"dwello-api/blob"
+	"dwello-api/images"
+	"dwello-api/models"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPropertyImages is how many pictures can be uploaded to a property
+const MaxPropertyImages = 20
+
+// ImageHandler serves the picture upload routes of properties and users
+type ImageHandler struct {
+	users      repository.UserRepository
+	properties repository.PropertyRepository
+	blobs      blob.Store
+}
+
+func NewImageHandler(users repository.UserRepository, properties repository.PropertyRepository, blobs blob.Store) *ImageHandler {
+	return &ImageHandler{users: users, properties: properties, blobs: blobs}
+}
+
+// UploadPropertyPicture godoc
+// @Summary Upload a property picture
+// @Description Upload a JPEG or PNG picture of at most 10 MB. Large, medium and thumbnail variants are stored with their metadata stripped; the large one is added to the pictures and the first thumbnail becomes the property's thumbnail.
+// @Tags Properties
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param picture formData file true "Picture"
+// @Success 201 {object} models.PropertySwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/pictures [post]
+func (h *ImageHandler) UploadPropertyPicture(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+	data, err := readPicture(c)
+	if err != nil {
+		return imageError(err)
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := findManagedProperty(ctx, c, h.properties, propertyID, "You cannot update a property that doesn't belong to you")
+	if err != nil {
+		return err
+	}
+	if len(property.Images) >= MaxPropertyImages {
+		return problem.Conflict(fmt.Sprintf("A property can have at most %d uploaded pictures", MaxPropertyImages))
+	}
+
+	img, err := images.Upload(ctx, h.blobs, path.Join("properties", propertyID.Hex()), data, images.PropertyVariants, utils.Now())
+	if err != nil {
+		return imageError(err)
+	}
+	if err := h.properties.AddImage(ctx, propertyID, img); err != nil {
+		images.Delete(ctx, h.blobs, img)
+		return problem.Internal("Failed to save picture", err)
+	}
+
+	if property, err = h.properties.FindByID(ctx, propertyID); err != nil {
+		return problem.Internal("Failed to fetch property", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(property)
+}
+
+// DeletePropertyPicture godoc
+// @Summary Delete a property picture
+// @Description Delete an uploaded picture and its variants. When it provided the thumbnail, the next picture's thumbnail takes over.
+// @Tags Properties
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {object} models.PropertySwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/pictures/{imageId} [delete]
+func (h *ImageHandler) DeletePropertyPicture(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+	imageID, err := primitive.ObjectIDFromHex(c.Params("imageId"))
+	if err != nil {
+		return problem.BadRequest("Invalid image ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := findManagedProperty(ctx, c, h.properties, propertyID, "You cannot update a property that doesn't belong to you")
+	if err != nil {
+		return err
+	}
+	img, found := property.RemoveImage(imageID)
+	if !found {
+		return problem.NotFound("Picture not found")
+	}
+
+	if err := h.properties.RemoveImage(ctx, propertyID, imageID); errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Picture not found")
+	} else if err != nil {
+		return problem.Internal("Failed to delete picture", err)
+	}
+	if err := images.Delete(ctx, h.blobs, &img); err != nil {
+		log.Println("Failed to delete picture files", img.ID.Hex(), err)
+	}
+
+	if property, err = h.properties.FindByID(ctx, propertyID); err != nil {
+		return problem.Internal("Failed to fetch property", err)
+	}
+	return c.JSON(property)
+}
+
+// UploadProfilePicture godoc
+// @Summary Upload a profile picture
+// @Description Upload a JPEG or PNG picture of at most 10 MB. It is cropped to a square, stored in medium and thumbnail sizes with its metadata stripped and replaces the previous profile picture, including on the user's properties.
+// @Tags Users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param email path string true "User Email"
+// @Param picture formData file true "Picture"
+// @Success 200 {object} models.UserSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/users/{email}/profile-pic [put]
+func (h *ImageHandler) UploadProfilePicture(c *fiber.Ctx) error {
+	if !isSelf(c, c.Params("email")) {
+		return problem.Forbidden("You can only access your own account")
+	}
+	data, err := readPicture(c)
+	if err != nil {
+		return imageError(err)
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	img, err := images.Upload(ctx, h.blobs, path.Join("users", user.ID.Hex()), data, images.ProfileVariants, utils.Now())
+	if err != nil {
+		return imageError(err)
+	}
+	if err := h.users.SetProfileImage(ctx, user.ID, img); err != nil {
+		images.Delete(ctx, h.blobs, img)
+		return problem.Internal("Failed to save profile picture", err)
+	}
+
+	// Properties keep a copy of their owner's picture
+	if err := h.properties.SetOwnerPic(ctx, user.Email, img.Variants[models.ImageMedium]); err != nil {
+		log.Println("Failed to update the owner picture of properties of", user.Email, err)
+	}
+	if previous := user.ProfileImage; previous != nil {
+		if err := images.Delete(ctx, h.blobs, previous); err != nil {
+			log.Println("Failed to delete picture files", previous.ID.Hex(), err)
+		}
+	}
+
+	updated, err := h.users.FindByID(ctx, user.ID)
+	if err != nil {
+		return problem.Internal("Failed to fetch user", err)
+	}
+	return c.JSON(updated)
+}
+
+// errNoPicture is returned by readPicture when the form has no picture file
+var errNoPicture = errors.New("a picture file is required")
+
+// readPicture reads the picture file of a multipart form
+func readPicture(c *fiber.Ctx) ([]byte, error) {
+	header, err := c.FormFile("picture")
+	if err != nil {
+		return nil, errNoPicture
+	}
+	if header.Size > int64(images.MaxUploadSize) {
+		return nil, images.ErrTooLarge
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(images.MaxUploadSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > images.MaxUploadSize {
+		return nil, images.ErrTooLarge
+	}
+	return data, nil
+}
+
+// imageError maps an upload error to a response
+func imageError(err error) error {
+	switch {
+	case errors.Is(err, errNoPicture), errors.Is(err, images.ErrInvalid):
+		return problem.Validation(err.Error())
+	case errors.Is(err, images.ErrTooLarge), errors.Is(err, images.ErrTooManyPixels):
+		return problem.New(fiber.StatusRequestEntityTooLarge, images.LimitError(err))
+	case errors.Is(err, images.ErrUnsupported):
+		return problem.New(fiber.StatusUnsupportedMediaType, err.Error())
+	}
+	return problem.Internal("Failed to store picture", err)
+}
@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LeaseHandler serves the /api/leases routes
+type LeaseHandler struct {
+	leases repository.LeaseRepository
+}
+
+func NewLeaseHandler(leases repository.LeaseRepository) *LeaseHandler {
+	return &LeaseHandler{leases: leases}
+}
+
+// ListLeases godoc
+// @Summary List leases
+// @Description List the leases of the authenticated user as a tenant (as=tenant) or for their properties (as=owner), newest first
+// @Tags Leases
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "tenant (default) or owner"
+// @Param status query string false "Filter by status" Enums(active, ended, terminated)
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of leases"
+// @Success 200 {object} models.PageSwagger[models.LeaseSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases [get]
+func (h *LeaseHandler) ListLeases(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.LeaseFilter
+	switch c.Query("as", "tenant") {
+	case "tenant":
+		filter.TenantID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be tenant or owner")
+	}
+
+	if status := models.LeaseStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			return problem.Validation("Invalid status")
+		}
+		filter.Status = status
+	}
+	return listPage(c, h.leases, filter, func(l *models.Lease) primitive.ObjectID { return l.ID }, "Failed to fetch leases")
+}
+
+// GetLease godoc
+// @Summary Get a lease
+// @Description Get a lease with its history. Visible to the tenant and the property owner.
+// @Tags Leases
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/leases/{id} [get]
+func (h *LeaseHandler) GetLease(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.findLease(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(lease)
+}
+
+// GetPropertyLease godoc
+// @Summary Get the current lease of a property
+// @Description Get the active lease of a property. Visible to the tenant and the property owner.
+// @Tags Leases
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/properties/{id}/lease [get]
+func (h *LeaseHandler) GetPropertyLease(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.leases.FindActiveByProperty(ctx, propertyID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !policy.CanViewLease(auth.CurrentUser(c), lease)) {
+		return problem.NotFound("No active lease for this property")
+	}
+	if err != nil {
+		return problem.Internal("Failed to fetch lease", err)
+	}
+	return c.JSON(lease)
+}
+
+// renewalRequest is the body of ProposeRenewal. The rent stays the same
+// when left out.
+type renewalRequest struct {
+	EndDate     string   `json:"end_date" validate:"required,date"`
+	MonthlyRent *float64 `json:"monthly_rent" validate:"omitempty,min=0"`
+}
+
+// ProposeRenewal godoc
+// @Summary Propose a lease renewal
+// @Description Offer the tenant an extension of an active lease. A new proposal replaces any pending one.
+// @Tags Leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param renewal body models.LeaseRenewalRequestSwagger true "Renewal terms"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases/{id}/renewal [post]
+func (h *LeaseHandler) ProposeRenewal(c *fiber.Ctx) error {
+	input, err := bind[renewalRequest](c)
+	if err != nil {
+		return err
+	}
+	end, _ := parseDate(input.EndDate)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.findLease(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+	if !policy.CanProposeRenewal(user, lease) {
+		return problem.Forbidden("Only the owner can propose a renewal")
+	}
+	if lease.Status != models.LeaseActive || lease.Termination != nil {
+		return problem.Conflict("Only active leases that are not being terminated can be renewed")
+	}
+	if !end.After(lease.EndDate.Time()) {
+		return invalidField("end_date", "must be after the current end date")
+	}
+
+	rent := lease.MonthlyRent
+	if input.MonthlyRent != nil {
+		rent = *input.MonthlyRent
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	lease.PendingRenewal = &models.LeaseRenewal{
+		EndDate:     primitive.NewDateTimeFromTime(end),
+		MonthlyRent: rent,
+		ProposedBy:  user.Email,
+		ProposedAt:  now,
+	}
+	recordLeaseEvent(lease, models.LeaseEvent{Type: models.LeaseRenewalProposed, By: user.Email, At: now})
+
+	return h.save(ctx, c, lease)
+}
+
+// AcceptRenewal godoc
+// @Summary Accept a lease renewal
+// @Description Accept the pending renewal, extending the lease with the proposed end date and rent. Only the tenant can accept.
+// @Tags Leases
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases/{id}/renewal/accept [post]
+func (h *LeaseHandler) AcceptRenewal(c *fiber.Ctx) error {
+	return h.respondToRenewal(c, true)
+}
+
+// DeclineRenewal godoc
+// @Summary Decline a lease renewal
+// @Description Decline the pending renewal. The lease keeps its current end date. Only the tenant can decline.
+// @Tags Leases
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases/{id}/renewal/decline [post]
+func (h *LeaseHandler) DeclineRenewal(c *fiber.Ctx) error {
+	return h.respondToRenewal(c, false)
+}
+
+func (h *LeaseHandler) respondToRenewal(c *fiber.Ctx, accept bool) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.findLease(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+	if !policy.CanRespondToRenewal(user, lease) {
+		return problem.Forbidden("Only the tenant can respond to a renewal")
+	}
+	if lease.Status != models.LeaseActive || lease.PendingRenewal == nil {
+		return problem.Conflict("There is no pending renewal")
+	}
+
+	event := models.LeaseEvent{Type: models.LeaseRenewalDeclined, By: user.Email, At: primitive.NewDateTimeFromTime(utils.Now())}
+	if accept {
+		lease.EndDate = lease.PendingRenewal.EndDate
+		lease.MonthlyRent = lease.PendingRenewal.MonthlyRent
+		event.Type = models.LeaseRenewed
+	}
+	lease.PendingRenewal = nil
+	recordLeaseEvent(lease, event)
+
+	return h.save(ctx, c, lease)
+}
+
+type terminationRequest struct {
+	EndDate string `json:"end_date" validate:"required,date"`
+	Reason  string `json:"reason" validate:"max=1000"`
+}
+
+func (r *terminationRequest) normalize() {
+	r.Reason = strings.TrimSpace(r.Reason)
+}
+
+// TerminateLease godoc
+// @Summary Terminate a lease early
+// @Description Bring the end date of an active lease forward. The tenant and the owner can terminate; the property becomes available once the new end date has passed.
+// @Tags Leases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Param termination body models.LeaseTerminationRequestSwagger true "Termination"
+// @Success 200 {object} models.LeaseSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases/{id}/terminate [post]
+func (h *LeaseHandler) TerminateLease(c *fiber.Ctx) error {
+	input, err := bind[terminationRequest](c)
+	if err != nil {
+		return err
+	}
+	end, _ := parseDate(input.EndDate)
+	if end.Before(utils.Now().Truncate(24 * time.Hour)) {
+		return invalidField("end_date", "cannot be in the past")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.findLease(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+	if !policy.CanTerminateLease(user, lease) {
+		return problem.Forbidden("You cannot terminate this lease")
+	}
+	if lease.Status != models.LeaseActive {
+		return problem.Conflict("Only active leases can be terminated")
+	}
+	if !end.Before(lease.EndDate.Time()) {
+		return invalidField("end_date", "must be before the current end date")
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	lease.EndDate = primitive.NewDateTimeFromTime(end)
+	lease.PendingRenewal = nil
+	lease.Termination = &models.LeaseTermination{By: user.Email, Reason: input.Reason, RequestedAt: now}
+	recordLeaseEvent(lease, models.LeaseEvent{Type: models.LeaseTerminationSet, By: user.Email, Note: input.Reason, At: now})
+
+	return h.save(ctx, c, lease)
+}
+
+// findLease loads the lease in the id path parameter
+func (h *LeaseHandler) findLease(ctx context.Context, c *fiber.Ctx) (*models.Lease, error) {
+	leaseID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid lease ID")
+	}
+
+	// Leases the user may not see are reported as missing
+	lease, err := h.leases.FindByID(ctx, leaseID)
+	if err != nil {
+		return nil, findError(err, "Lease")
+	}
+	if !policy.CanViewLease(auth.CurrentUser(c), lease) {
+		return nil, problem.NotFound("Lease not found")
+	}
+	return lease, nil
+}
+
+// save stores the changed lease and responds with it
+func (h *LeaseHandler) save(ctx context.Context, c *fiber.Ctx, lease *models.Lease) error {
+	err := h.leases.Update(ctx, lease)
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("The lease was changed by someone else, please retry").WithCode(problem.CodeConcurrentUpdate)
+	}
+	if err != nil {
+		return problem.Internal("Failed to update lease", err)
+	}
+	return c.JSON(lease)
+}
+
+// recordLeaseEvent appends event to the lease history
+func recordLeaseEvent(lease *models.Lease, event models.LeaseEvent) {
+	lease.History = append(lease.History, event)
+	lease.UpdatedAt = event.At
+}
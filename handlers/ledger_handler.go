@@ -0,0 +1,494 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/ledger"
+	"dwello-api/models"
+	"dwello-api/payments"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LedgerHandler serves the invoice, payment and statement routes
+type LedgerHandler struct {
+	transactor repository.Transactor
+	leases     repository.LeaseRepository
+	invoices   repository.InvoiceRepository
+	payments   repository.PaymentRepository
+	provider   payments.Provider
+}
+
+// NewLedgerHandler returns the handler of the ledger routes. A nil provider
+// turns online payments off.
+func NewLedgerHandler(transactor repository.Transactor, leases repository.LeaseRepository, invoices repository.InvoiceRepository, payments repository.PaymentRepository, provider payments.Provider) *LedgerHandler {
+	return &LedgerHandler{transactor: transactor, leases: leases, invoices: invoices, payments: payments, provider: provider}
+}
+
+// ListInvoices godoc
+// @Summary List invoices
+// @Description List the invoices the authenticated user owes as a tenant (as=tenant) or is owed for their properties (as=owner), newest first
+// @Tags Ledger
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "tenant (default) or owner"
+// @Param status query string false "Filter by status" Enums(open, paid)
+// @Param lease_id query string false "Filter by lease"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of invoices"
+// @Success 200 {object} models.PageSwagger[models.InvoiceSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/invoices [get]
+func (h *LedgerHandler) ListInvoices(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.InvoiceFilter
+	switch c.Query("as", "tenant") {
+	case "tenant":
+		filter.TenantID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be tenant or owner")
+	}
+
+	if status := models.InvoiceStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			return problem.Validation("Invalid status")
+		}
+		filter.Status = status
+	}
+
+	if leaseIDParam := c.Query("lease_id"); leaseIDParam != "" {
+		leaseID, err := primitive.ObjectIDFromHex(leaseIDParam)
+		if err != nil {
+			return problem.BadRequest("Invalid lease ID")
+		}
+		filter.LeaseID = leaseID
+	}
+
+	return listPage(c, h.invoices, filter, func(i *models.Invoice) primitive.ObjectID { return i.ID }, "Failed to fetch invoices")
+}
+
+// GetInvoice godoc
+// @Summary Get an invoice
+// @Description Get an invoice. Visible to the tenant and the property owner.
+// @Tags Ledger
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} models.InvoiceSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/invoices/{id} [get]
+func (h *LedgerHandler) GetInvoice(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	invoice, err := h.findInvoice(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(invoice)
+}
+
+// recordPaymentRequest is the body of RecordPayment. The amount defaults to
+// what is still owed and the date to now.
+type recordPaymentRequest struct {
+	Amount *float64 `json:"amount" validate:"omitempty,gt=0"`
+	PaidAt string   `json:"paid_at" validate:"omitempty,date"`
+	Note   string   `json:"note" validate:"max=500"`
+}
+
+func (r *recordPaymentRequest) normalize() {
+	r.Note = strings.TrimSpace(r.Note)
+}
+
+// payInvoiceRequest is the optional body of PayInvoice
+type payInvoiceRequest struct {
+	Amount *float64 `json:"amount" validate:"omitempty,gt=0"`
+}
+
+// RecordPayment godoc
+// @Summary Record a payment
+// @Description Record money received outside the app against an invoice, such as a bank transfer. The amount defaults to what is still owed and cannot exceed it. Only the owner can record payments.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param payment body models.PaymentRequestSwagger true "Payment"
+// @Success 201 {object} models.PaymentSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/invoices/{id}/payments [post]
+func (h *LedgerHandler) RecordPayment(c *fiber.Ctx) error {
+	input, err := bind[recordPaymentRequest](c)
+	if err != nil {
+		return err
+	}
+
+	paidAt := utils.Now()
+	if input.PaidAt != "" {
+		date, _ := parseDate(input.PaidAt)
+		if date.After(paidAt) {
+			return invalidField("paid_at", "cannot be in the future")
+		}
+		paidAt = date
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	invoice, err := h.findInvoice(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+	if !policy.CanRecordPayment(user, invoice) {
+		return problem.Forbidden("Only the owner can record payments")
+	}
+
+	amount, err := paymentAmount(invoice, input.Amount)
+	if err != nil {
+		return err
+	}
+
+	payment := newPayment(invoice, amount, user.Email)
+	payment.Method = "manual"
+	payment.Note = input.Note
+	payment.PaidAt = primitive.NewDateTimeFromTime(paidAt)
+
+	if err := h.record(ctx, invoice, &payment); err != nil {
+		return paymentError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(payment)
+}
+
+// PayInvoice godoc
+// @Summary Pay an invoice
+// @Description Pay an invoice through the payment provider. The amount defaults to what is still owed and cannot exceed it. Only the tenant can pay.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param payment body models.PaymentRequestSwagger false "Payment, only the amount is used"
+// @Success 201 {object} models.PaymentSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 402 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 503 {object} problem.Problem
+// @Router /api/invoices/{id}/pay [post]
+func (h *LedgerHandler) PayInvoice(c *fiber.Ctx) error {
+	if h.provider == nil {
+		return problem.New(fiber.StatusServiceUnavailable, "Online payments are not available").WithCode(problem.CodePaymentUnavailable)
+	}
+
+	input, err := bindOptional[payInvoiceRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	invoice, err := h.findInvoice(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+	if !policy.CanPayInvoice(user, invoice) {
+		return problem.Forbidden("Only the tenant can pay an invoice")
+	}
+
+	amount, err := paymentAmount(invoice, input.Amount)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := h.provider.Charge(ctx, payments.Charge{
+		Amount:      amount,
+		Description: string(invoice.Kind) + " invoice " + invoice.ID.Hex(),
+		Reference:   invoice.ID.Hex(),
+		PayerEmail:  user.Email,
+	})
+	if errors.Is(err, payments.ErrDeclined) {
+		return problem.New(fiber.StatusPaymentRequired, "The payment was declined").WithCode(problem.CodePaymentDeclined)
+	}
+	if err != nil {
+		return problem.New(fiber.StatusBadGateway, "The payment provider is unavailable").WithCode(problem.CodePaymentUnavailable)
+	}
+
+	payment := newPayment(invoice, amount, user.Email)
+	payment.Method = h.provider.Name()
+	payment.Reference = receipt.ID
+	payment.PaidAt = payment.CreatedAt
+
+	if err := h.record(ctx, invoice, &payment); err != nil {
+		// Give the money back, the tenant can retry
+		if refundErr := h.provider.Refund(context.WithoutCancel(ctx), receipt.ID); refundErr != nil {
+			log.Println("Failed to refund charge", receipt.ID, refundErr)
+		}
+		return paymentError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(payment)
+}
+
+// ListPayments godoc
+// @Summary List payments
+// @Description List the payments the authenticated user has made as a tenant (as=tenant) or received for their properties (as=owner), newest first
+// @Tags Ledger
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "tenant (default) or owner"
+// @Param lease_id query string false "Filter by lease"
+// @Param invoice_id query string false "Filter by invoice"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of payments"
+// @Success 200 {object} models.PageSwagger[models.PaymentSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/payments [get]
+func (h *LedgerHandler) ListPayments(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.PaymentFilter
+	switch c.Query("as", "tenant") {
+	case "tenant":
+		filter.TenantID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be tenant or owner")
+	}
+
+	if leaseIDParam := c.Query("lease_id"); leaseIDParam != "" {
+		leaseID, err := primitive.ObjectIDFromHex(leaseIDParam)
+		if err != nil {
+			return problem.BadRequest("Invalid lease ID")
+		}
+		filter.LeaseID = leaseID
+	}
+	if invoiceIDParam := c.Query("invoice_id"); invoiceIDParam != "" {
+		invoiceID, err := primitive.ObjectIDFromHex(invoiceIDParam)
+		if err != nil {
+			return problem.BadRequest("Invalid invoice ID")
+		}
+		filter.InvoiceID = invoiceID
+	}
+
+	return listPage(c, h.payments, filter, func(p *models.Payment) primitive.ObjectID { return p.ID }, "Failed to fetch payments")
+}
+
+// GetLeaseStatement godoc
+// @Summary Get the statement of a lease
+// @Description Get every invoice and payment of a lease with the totals, balance, overdue amount, late fees and deposit held. Visible to the tenant and the property owner.
+// @Tags Ledger
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lease ID"
+// @Success 200 {object} models.StatementSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/leases/{id}/statement [get]
+func (h *LedgerHandler) GetLeaseStatement(c *fiber.Ctx) error {
+	leaseID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid lease ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	lease, err := h.leases.FindByID(ctx, leaseID)
+	if err != nil {
+		return findError(err, "Lease")
+	}
+	if !policy.CanViewLease(auth.CurrentUser(c), lease) {
+		return problem.NotFound("Lease not found")
+	}
+
+	statement, err := h.statement(ctx, lease)
+	if err != nil {
+		return problem.Internal("Failed to build statement", err)
+	}
+	return c.JSON(statement)
+}
+
+// ListStatements godoc
+// @Summary List statements
+// @Description Get the statement of every lease of the authenticated user as a tenant (as=tenant) or for their properties (as=owner), newest lease first
+// @Tags Ledger
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "tenant (default) or owner"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of statements"
+// @Success 200 {object} models.PageSwagger[models.StatementSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/statements [get]
+func (h *LedgerHandler) ListStatements(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.LeaseFilter
+	switch c.Query("as", "tenant") {
+	case "tenant":
+		filter.TenantID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be tenant or owner")
+	}
+	page, err := parsePage(c, repository.SortCreatedAt)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	leases, err := h.leases.List(ctx, filter, peek(page))
+	if err != nil {
+		return problem.Internal("Failed to fetch leases", err)
+	}
+	leasePage := newPage(leases, page, func(l *models.Lease) (float64, primitive.ObjectID) { return repository.ByCreation(l.ID) })
+	if err := countTotal(c, &leasePage, func() (int64, error) { return h.leases.Count(ctx, filter) }); err != nil {
+		return problem.Internal("Failed to fetch leases", err)
+	}
+
+	// Statements are paged by their lease
+	response := models.Page[models.Statement]{Items: []models.Statement{}, NextCursor: leasePage.NextCursor, Total: leasePage.Total}
+	for i := range leasePage.Items {
+		statement, err := h.statement(ctx, &leasePage.Items[i])
+		if err != nil {
+			return problem.Internal("Failed to build statements", err)
+		}
+		response.Items = append(response.Items, statement)
+	}
+	return c.JSON(response)
+}
+
+// findInvoice loads the invoice in the id path parameter
+func (h *LedgerHandler) findInvoice(ctx context.Context, c *fiber.Ctx) (*models.Invoice, error) {
+	invoiceID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid invoice ID")
+	}
+
+	// Invoices the user may not see are reported as missing
+	invoice, err := h.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, findError(err, "Invoice")
+	}
+	if !policy.CanViewInvoice(auth.CurrentUser(c), invoice) {
+		return nil, problem.NotFound("Invoice not found")
+	}
+	return invoice, nil
+}
+
+// record applies the payment to the invoice and stores it
+func (h *LedgerHandler) record(ctx context.Context, invoice *models.Invoice, payment *models.Payment) error {
+	return h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if err := h.invoices.ApplyPayment(ctx, invoice, payment.Amount); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error {
+			return h.invoices.ApplyPayment(ctx, invoice, -payment.Amount)
+		})
+
+		return h.payments.Create(ctx, payment)
+	})
+}
+
+// statement loads the invoices and payments of the lease and totals them
+func (h *LedgerHandler) statement(ctx context.Context, lease *models.Lease) (models.Statement, error) {
+	invoices, err := h.invoices.List(ctx, repository.InvoiceFilter{LeaseID: lease.ID}, repository.Page{})
+	if err != nil {
+		return models.Statement{}, err
+	}
+	payments, err := h.payments.List(ctx, repository.PaymentFilter{LeaseID: lease.ID}, repository.Page{})
+	if err != nil {
+		return models.Statement{}, err
+	}
+	return ledger.Statement(lease, invoices, payments, utils.Now()), nil
+}
+
+// paymentAmount validates the requested amount, defaulting to what is still
+// owed
+func paymentAmount(invoice *models.Invoice, requested *float64) (float64, error) {
+	if invoice.Status == models.InvoicePaid {
+		return 0, problem.Conflict("The invoice is already paid")
+	}
+
+	outstanding := ledger.Round(invoice.Outstanding())
+	if requested == nil {
+		return outstanding, nil
+	}
+	amount := ledger.Round(*requested)
+	if amount <= 0 {
+		return 0, invalidField("amount", "must be at least 0.01")
+	}
+	if amount > outstanding {
+		return 0, invalidField("amount", "cannot exceed the outstanding balance")
+	}
+	return amount, nil
+}
+
+// newPayment returns a payment against the invoice without method or date
+func newPayment(invoice *models.Invoice, amount float64, by string) models.Payment {
+	return models.Payment{
+		ID:          primitive.NewObjectID(),
+		InvoiceID:   invoice.ID,
+		LeaseID:     invoice.LeaseID,
+		OwnerEmail:  invoice.OwnerEmail,
+		TenantID:    invoice.TenantID,
+		TenantEmail: invoice.TenantEmail,
+		Amount:      amount,
+		RecordedBy:  by,
+		CreatedAt:   primitive.NewDateTimeFromTime(utils.Now()),
+	}
+}
+
+// paymentError maps a failed payment to a response
+func paymentError(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("The invoice was changed by someone else, please retry").WithCode(problem.CodeConcurrentUpdate)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Invoice not found")
+	}
+	return problem.Internal("Failed to record payment", err)
+}
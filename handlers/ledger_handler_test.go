@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/payments"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/repository/memory"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// refundSpy is a FakeProvider remembering the charges it refunded
+type refundSpy struct {
+	*payments.FakeProvider
+	refunded []string
+}
+
+func (p *refundSpy) Refund(ctx context.Context, receiptID string) error {
+	if err := p.FakeProvider.Refund(ctx, receiptID); err != nil {
+		return err
+	}
+	p.refunded = append(p.refunded, receiptID)
+	return nil
+}
+
+// failingPayments cannot store payments
+type failingPayments struct {
+	repository.PaymentRepository
+}
+
+func (failingPayments) Create(context.Context, *models.Payment) error {
+	return errors.New("disk full")
+}
+
+func TestPayInvoice(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     bool
+		decline      bool
+		failRecord   bool
+		paid         bool
+		payer        string
+		status       int
+		code         string
+		wantPaid     float64
+		wantRefunded int
+	}{
+		{name: "paid in full", provider: true, payer: "tenant", status: http.StatusCreated, wantPaid: 1000},
+		{name: "declined", provider: true, decline: true, payer: "tenant", status: http.StatusPaymentRequired, code: problem.CodePaymentDeclined},
+		{name: "refunded when recording fails", provider: true, failRecord: true, payer: "tenant", status: http.StatusInternalServerError, wantRefunded: 1},
+		{name: "already paid", provider: true, paid: true, payer: "tenant", status: http.StatusConflict, wantPaid: 1000},
+		{name: "owner cannot pay", provider: true, payer: "owner", status: http.StatusForbidden},
+		{name: "stranger sees no invoice", provider: true, payer: "stranger", status: http.StatusNotFound},
+		{name: "no provider", payer: "tenant", status: http.StatusServiceUnavailable, code: problem.CodePaymentUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := memory.NewStore()
+			users := map[string]*models.User{
+				"tenant":   {Email: "tenant@example.com", Role: models.RoleTenant},
+				"owner":    {Email: "owner@example.com", Role: models.RoleOwner},
+				"stranger": {Email: "stranger@example.com", Role: models.RoleTenant},
+			}
+			for _, user := range users {
+				if err := store.Users.Create(ctx, user); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			invoice := &models.Invoice{
+				ID:         primitive.NewObjectID(),
+				LeaseID:    primitive.NewObjectID(),
+				OwnerEmail: users["owner"].Email,
+				TenantID:   users["tenant"].ID,
+				Key:        "rent:2025-01-01",
+				Kind:       models.InvoiceRent,
+				Amount:     1000,
+				Status:     models.InvoiceOpen,
+			}
+			if tt.paid {
+				invoice.AmountPaid, invoice.Status = invoice.Amount, models.InvoicePaid
+			}
+			if err := store.Invoices.Create(ctx, invoice); err != nil {
+				t.Fatal(err)
+			}
+
+			var provider payments.Provider
+			spy := &refundSpy{FakeProvider: payments.NewFakeProvider()}
+			spy.Decline = tt.decline
+			if tt.provider {
+				provider = spy
+			}
+			paymentStore := store.Payments
+			if tt.failRecord {
+				paymentStore = failingPayments{store.Payments}
+			}
+
+			app := newTestApp()
+			h := NewLedgerHandler(store.Transactor, store.Leases, store.Invoices, paymentStore, provider)
+			app.Post("/api/invoices/:id/pay", auth.Middleware(store.Users), h.PayInvoice)
+
+			tokens, err := auth.IssueTokens(*users[tt.payer])
+			if err != nil {
+				t.Fatal(err)
+			}
+			status, body := call(t, app, http.MethodPost, "/api/invoices/"+invoice.ID.Hex()+"/pay", tokens.AccessToken, nil)
+			if status != tt.status {
+				t.Fatalf("status %d, want %d (body %v)", status, tt.status, body)
+			}
+			if tt.code != "" && body["code"] != tt.code {
+				t.Errorf("code %v, want %s", body["code"], tt.code)
+			}
+
+			stored, err := store.Invoices.FindByID(ctx, invoice.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.AmountPaid != tt.wantPaid {
+				t.Errorf("amount paid %v, want %v", stored.AmountPaid, tt.wantPaid)
+			}
+			if len(spy.refunded) != tt.wantRefunded {
+				t.Errorf("refunded %v, want %d refunds", spy.refunded, tt.wantRefunded)
+			}
+			if tt.status == http.StatusCreated && body["method"] != spy.Name() {
+				t.Errorf("payment method %v, want %s", body["method"], spy.Name())
+			}
+		})
+	}
+}
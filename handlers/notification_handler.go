@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationHandler serves the /api/notifications routes
+type NotificationHandler struct {
+	users         repository.UserRepository
+	notifications repository.NotificationRepository
+}
+
+func NewNotificationHandler(users repository.UserRepository, notifications repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{users: users, notifications: notifications}
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description List the authenticated user's in-app notifications, newest first. New notifications are also pushed over the WebSocket as {"type": "notification", "data": notification}.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of notifications"
+// @Success 200 {object} models.PageSwagger[models.NotificationSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *fiber.Ctx) error {
+	filter := repository.NotificationFilter{UserID: auth.CurrentUser(c).ID, Unread: c.QueryBool("unread")}
+	return listPage(c, h.notifications, filter, func(n *models.Notification) primitive.ObjectID { return n.ID }, "Failed to fetch notifications")
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} models.NotificationSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	notificationID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid notification ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	// Notifications of other users are reported as missing
+	notification, err := h.notifications.FindByID(ctx, notificationID)
+	if err != nil {
+		return findError(err, "Notification")
+	}
+	if !policy.CanViewNotification(auth.CurrentUser(c), notification) {
+		return problem.NotFound("Notification not found")
+	}
+
+	if err := h.notifications.MarkRead(ctx, notification.ID, utils.Now()); err != nil {
+		return problem.Internal("Failed to mark notification as read", err)
+	}
+	if notification, err = h.notifications.FindByID(ctx, notification.ID); err != nil {
+		return problem.Internal("Failed to fetch notification", err)
+	}
+	return c.JSON(notification)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark every notification as read
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	marked, err := h.notifications.MarkAllRead(ctx, auth.CurrentUser(c).ID, utils.Now())
+	if err != nil {
+		return problem.Internal("Failed to mark notifications as read", err)
+	}
+	return c.JSON(fiber.Map{"marked": marked})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Get, for each type of notification, whether it is delivered in the app, by email and by push
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationPreferencesSwagger
+// @Failure 401 {object} problem.Problem
+// @Router /api/notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *fiber.Ctx) error {
+	return c.JSON(auth.CurrentUser(c).NotificationPreferences.Settings())
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Turn channels on or off for some types of notification, such as {"property_liked": {"email": false}}. Settings left out are unchanged. Turning in_app off also keeps the notifications out of the feed.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body models.NotificationPreferencesSwagger true "Settings to change"
+// @Success 200 {object} models.NotificationPreferencesSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	var settings map[models.NotificationType]map[models.NotificationChannel]bool
+	if err := c.BodyParser(&settings); err != nil {
+		return problem.BadRequest("Invalid body")
+	}
+	var fields []problem.FieldError
+	for t, channels := range settings {
+		if !t.Valid() {
+			fields = append(fields, problem.FieldError{Field: string(t), Message: "is not a notification type"})
+			continue
+		}
+		for channel := range channels {
+			if !channel.Valid() {
+				fields = append(fields, problem.FieldError{Field: string(t) + "." + string(channel), Message: "is not a notification channel"})
+			}
+		}
+	}
+	if len(fields) > 0 {
+		slices.SortFunc(fields, func(a, b problem.FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return problem.Validation("Invalid request", fields...)
+	}
+
+	user := auth.CurrentUser(c)
+	preferences := user.NotificationPreferences.Apply(settings)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	if err := h.users.SetNotificationPreferences(ctx, user.ID, preferences); err != nil {
+		return problem.Internal("Failed to update notification preferences", err)
+	}
+	return c.JSON(preferences.Settings())
+}
+
+// pushTokenRequest is the body of RegisterPushToken
+type pushTokenRequest struct {
+	Token string `json:"token" validate:"required,max=4096"`
+}
+
+func (r *pushTokenRequest) normalize() {
+	r.Token = strings.TrimSpace(r.Token)
+}
+
+// RegisterPushToken godoc
+// @Summary Register a device for push notifications
+// @Description Send push notifications to the device with this token. A token registered by another account is moved to this one.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param device body models.PushTokenSwagger true "Device token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications/devices [post]
+func (h *NotificationHandler) RegisterPushToken(c *fiber.Ctx) error {
+	input, err := bind[pushTokenRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	if err := h.users.AddPushToken(ctx, auth.CurrentUser(c).ID, input.Token); err != nil {
+		return problem.Internal("Failed to register device", err)
+	}
+	return c.JSON(fiber.Map{"message": "Device registered"})
+}
+
+// UnregisterPushToken godoc
+// @Summary Stop push notifications to a device
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Device token, URL-encoded"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/notifications/devices/{token} [delete]
+func (h *NotificationHandler) UnregisterPushToken(c *fiber.Ctx) error {
+	token, err := url.PathUnescape(c.Params("token"))
+	if err != nil || token == "" {
+		return problem.Validation("Invalid device token")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	if err := h.users.RemovePushToken(ctx, auth.CurrentUser(c).ID, token); err != nil {
+		return problem.Internal("Failed to unregister device", err)
+	}
+	return c.JSON(fiber.Map{"message": "Device unregistered"})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dwello-api/models"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Default and largest limit of list endpoints
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// parsePage reads the limit, sort, order and cursor query parameters. sorts
+// lists the fields the endpoint can sort by, the first being the default.
+// Prices and distances default to ascending order, everything else to
+// descending. A cursor brings back the sort and order it was issued for.
+func parsePage(c *fiber.Ctx, sorts ...repository.SortField) (repository.Page, error) {
+	page := repository.Page{Sort: sorts[0], Limit: int64(c.QueryInt("limit", defaultPageLimit))}
+	if page.Limit < 1 || page.Limit > maxPageLimit {
+		return page, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := repository.DecodeCursor(cursor)
+		if err != nil || !slices.Contains(sorts, after.Sort) {
+			return page, repository.ErrInvalidCursor
+		}
+		page.Sort, page.Desc, page.After = after.Sort, after.Desc, after
+		return page, nil
+	}
+
+	if sort := repository.SortField(c.Query("sort")); sort != "" {
+		if !slices.Contains(sorts, sort) {
+			return page, fmt.Errorf("sort must be one of %s", joinSorts(sorts))
+		}
+		page.Sort = sort
+	}
+	page.Desc = page.Sort != repository.SortPrice && page.Sort != repository.SortDistance
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		page.Desc = false
+	case "desc":
+		page.Desc = true
+	default:
+		return page, errors.New("order must be asc or desc")
+	}
+	return page, nil
+}
+
+func joinSorts(sorts []repository.SortField) string {
+	names := make([]string, len(sorts))
+	for i, sort := range sorts {
+		names[i] = string(sort)
+	}
+	return strings.Join(names, ", ")
+}
+
+// peek asks for one item past the page, which tells newPage whether another
+// page follows
+func peek(page repository.Page) repository.Page {
+	page.Limit++
+	return page
+}
+
+// newPage trims the items fetched with peek to the page and adds the cursor
+// of the next page when there is one. key returns an item's sort value and ID.
+func newPage[T any](items []T, page repository.Page, key func(*T) (float64, primitive.ObjectID)) models.Page[T] {
+	result := models.Page[T]{Items: items}
+	if int64(len(items)) > page.Limit {
+		result.Items = items[:page.Limit]
+		value, id := key(&result.Items[page.Limit-1])
+		result.NextCursor = repository.Cursor{Sort: page.Sort, Desc: page.Desc, Value: value, ID: id}.Encode()
+	}
+	return result
+}
+
+// countTotal sets the page's total when the client asked for it with total=true
+func countTotal[T any](c *fiber.Ctx, page *models.Page[T], count func() (int64, error)) error {
+	if !c.QueryBool("total") {
+		return nil
+	}
+	total, err := count()
+	if err != nil {
+		return err
+	}
+	page.Total = &total
+	return nil
+}
+
+// lister is implemented by the repositories whose lists only sort by
+// creation time
+type lister[F, T any] interface {
+	List(ctx context.Context, filter F, page repository.Page) ([]T, error)
+	Count(ctx context.Context, filter F) (int64, error)
+}
+
+// listPage responds with a page of the items matching the filter, newest
+// first unless order=asc. id returns an item's ID; failure is the error sent
+// when the items cannot be fetched.
+func listPage[F, T any](c *fiber.Ctx, repo lister[F, T], filter F, id func(*T) primitive.ObjectID, failure string) error {
+	page, err := parsePage(c, repository.SortCreatedAt)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	items, err := repo.List(ctx, filter, peek(page))
+	if err != nil {
+		return problem.Internal(failure, err)
+	}
+	response := newPage(items, page, func(item *T) (float64, primitive.ObjectID) { return repository.ByCreation(id(item)) })
+	if err := countTotal(c, &response, func() (int64, error) { return repo.Count(ctx, filter) }); err != nil {
+		return problem.Internal(failure, err)
+	}
+	return c.JSON(response)
+}
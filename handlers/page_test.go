@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dwello-api/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParsePage(t *testing.T) {
+	id := primitive.NewObjectID()
+	priceCursor := repository.Cursor{Sort: repository.SortPrice, Value: 100, ID: id}
+	relevanceCursor := repository.Cursor{Sort: repository.SortRelevance, Desc: true, Value: 2, ID: id}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    repository.Page
+		wantErr bool
+	}{
+		{"defaults", "", repository.Page{Sort: repository.SortCreatedAt, Desc: true, Limit: 20}, false},
+		{"limit", "?limit=5", repository.Page{Sort: repository.SortCreatedAt, Desc: true, Limit: 5}, false},
+		{"prices ascend by default", "?sort=price", repository.Page{Sort: repository.SortPrice, Limit: 20}, false},
+		{"explicit order", "?sort=price&order=desc", repository.Page{Sort: repository.SortPrice, Desc: true, Limit: 20}, false},
+		{"likes descend by default", "?sort=likes", repository.Page{Sort: repository.SortLikes, Desc: true, Limit: 20}, false},
+		{
+			"cursor brings back its sort", "?cursor=" + priceCursor.Encode() + "&sort=likes&order=desc",
+			repository.Page{Sort: repository.SortPrice, After: &priceCursor, Limit: 20}, false,
+		},
+		{"zero limit", "?limit=0", repository.Page{}, true},
+		{"limit over the maximum", "?limit=101", repository.Page{}, true},
+		{"unsupported sort", "?sort=relevance", repository.Page{}, true},
+		{"bad order", "?order=up", repository.Page{}, true},
+		{"bad cursor", "?cursor=abc", repository.Page{}, true},
+		{"cursor of an unsupported sort", "?cursor=" + relevanceCursor.Encode(), repository.Page{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got repository.Page
+			var err error
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got, err = parsePage(c, repository.SortCreatedAt, repository.SortPrice, repository.SortLikes)
+				return nil
+			})
+			if _, testErr := app.Test(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)); testErr != nil {
+				t.Fatal(testErr)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePage() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Sort != tt.want.Sort || got.Desc != tt.want.Desc || got.Limit != tt.want.Limit {
+				t.Errorf("parsePage() = %+v, want %+v", got, tt.want)
+			}
+			if (got.After == nil) != (tt.want.After == nil) || got.After != nil && *got.After != *tt.want.After {
+				t.Errorf("parsePage() cursor = %+v, want %+v", got.After, tt.want.After)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	page := repository.Page{Sort: repository.SortCreatedAt, Desc: true, Limit: 2}
+
+	tests := []struct {
+		name       string
+		fetched    []primitive.ObjectID
+		wantItems  int
+		wantCursor *repository.Cursor
+	}{
+		{"more items follow", ids, 2, &repository.Cursor{Sort: page.Sort, Desc: page.Desc, ID: ids[1]}},
+		{"last page", ids[:2], 2, nil},
+		{"empty page", nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPage(tt.fetched, page, func(id *primitive.ObjectID) (float64, primitive.ObjectID) {
+				return repository.ByCreation(*id)
+			})
+			if len(got.Items) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(got.Items), tt.wantItems)
+			}
+			if tt.wantCursor == nil {
+				if got.NextCursor != "" {
+					t.Errorf("NextCursor = %q, want none", got.NextCursor)
+				}
+				return
+			}
+			cursor, err := repository.DecodeCursor(got.NextCursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *cursor != *tt.wantCursor {
+				t.Errorf("NextCursor = %+v, want %+v", *cursor, *tt.wantCursor)
+			}
+		})
+	}
+}
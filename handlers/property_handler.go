@@ -1,67 +1,337 @@
 package handlers
 
 import (
-	"dwello-api/db"
+	"context"
+	"dwello-api/auth"
+	"dwello-api/blob"
+	"dwello-api/images"
+	"dwello-api/mergepatch"
 	"dwello-api/models"
+	"dwello-api/notify"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/ranking"
+	"dwello-api/repository"
+	"dwello-api/similarity"
+	"dwello-api/textsearch"
 	"dwello-api/utils"
+	"dwello-api/webhook"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	// Default and largest radius_km of NearbyProperties
+	defaultNearbyRadius = 5.0
+	maxNearbyRadius     = 100.0
+
+	// Default number of similar properties returned
+	defaultSimilarLimit = 10
+
+	invalidCoordinates = "must be a GeoJSON point with [longitude, latitude]"
+)
+
+// PropertyHandler serves the /api/properties routes
+type PropertyHandler struct {
+	transactor   repository.Transactor
+	users        repository.UserRepository
+	properties   repository.PropertyRepository
+	leases       repository.LeaseRepository
+	similarities repository.SimilarityRepository
+	changes      repository.PropertyChangeRepository
+	requests     repository.RentalRequestRepository
+	slots        repository.ViewingSlotRepository
+	viewings     repository.ViewingRepository
+	blobs        blob.Store
+	notifier     *notify.Dispatcher
+	webhooks     *webhook.Publisher
+}
+
+func NewPropertyHandler(transactor repository.Transactor, users repository.UserRepository, properties repository.PropertyRepository, leases repository.LeaseRepository, similarities repository.SimilarityRepository, changes repository.PropertyChangeRepository, requests repository.RentalRequestRepository, slots repository.ViewingSlotRepository, viewings repository.ViewingRepository, blobs blob.Store, notifier *notify.Dispatcher, webhooks *webhook.Publisher) *PropertyHandler {
+	return &PropertyHandler{transactor: transactor, users: users, properties: properties, leases: leases, similarities: similarities, changes: changes, requests: requests, slots: slots, viewings: viewings, blobs: blobs, notifier: notifier, webhooks: webhooks}
+}
+
 // GetHomescreenProperties godoc
 // @Summary Get properties for the homescreen
-// @Description Get properties based on user's preferred location
+// @Description Get the available properties ranked for the authenticated user by their preferred and current locations, the prices of the properties they liked, recency and likes. Users without locations or likes get trending properties. Rented properties and the user's own are left out.
 // @Tags Properties
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.PropertySwagger
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of properties"
+// @Success 200 {object} models.PageSwagger[models.PropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/homescreen [get]
-func GetHomescreenProperties(c *fiber.Ctx) error {
-	var user models.User
-	userEmail := c.Query("email")
-	fmt.Println("User email from query:", userEmail)
-	if userEmail == "" {
-		if err := c.BodyParser(&user); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+func (h *PropertyHandler) GetHomescreenProperties(c *fiber.Ctx) error {
+	page, err := parsePage(c, repository.SortRank)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	ranked, err := ranking.Homescreen(ctx, h.properties, auth.CurrentUser(c), utils.Now())
+	if err != nil {
+		return problem.Internal("Failed to fetch properties", err)
+	}
+	total := int64(len(ranked))
+
+	// Scores move as listings get liked, so a cursor resumes after the score
+	// and ID it was issued for rather than at a fixed offset
+	slices.SortFunc(ranked, func(a, b ranking.Ranked) int { return page.Compare(a.Score, a.ID, b.Score, b.ID) })
+	if after := page.After; after != nil {
+		start := 0
+		for start < len(ranked) && page.Compare(ranked[start].Score, ranked[start].ID, after.Value, after.ID) <= 0 {
+			start++
 		}
-		userEmail = user.Email
+		ranked = ranked[start:]
+	}
+	if limit := peek(page).Limit; int64(len(ranked)) > limit {
+		ranked = ranked[:limit]
+	}
+
+	response := newPage(ranked, page, func(r *ranking.Ranked) (float64, primitive.ObjectID) { return r.Score, r.ID })
+	for i := range response.Items {
+		hideUsers(&response.Items[i].Property, auth.CurrentUser(c))
+	}
+	countTotal(c, &response, func() (int64, error) { return total, nil })
+	return c.JSON(response)
+}
+
+// GetSimilarProperties godoc
+// @Summary Get similar properties
+// @Description Get available properties similar to a property by location, price, attributes and the users who liked both, best first. Lists are recomputed hourly; properties not reached yet are compared with the newest listings in their city.
+// @Tags Properties
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param limit query int false "Number of properties, 10 by default and at most 20"
+// @Success 200 {object} models.PageSwagger[models.SimilarPropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/similar [get]
+func (h *PropertyHandler) GetSimilarProperties(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+	limit := c.QueryInt("limit", defaultSimilarLimit)
+	if limit < 1 || limit > similarity.MaxSimilar {
+		return problem.Validation(fmt.Sprintf("limit must be between 1 and %d", similarity.MaxSimilar))
 	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	// Fetch user document
-	err := db.UserCollection().FindOne(ctx, bson.M{"email": userEmail}).Decode(&user)
+	property, err := h.properties.FindByID(ctx, propertyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch user details"})
+		return findError(err, "Property")
 	}
 
-	// Check for preferred locations
-	if len(user.PreferredLocations) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Preferred locations not set"})
+	var scores []models.SimilarityScore
+	if similar, err := h.similarities.Find(ctx, propertyID); err == nil {
+		scores = similar.Similar
+	} else if errors.Is(err, repository.ErrNotFound) {
+		if scores, err = similarity.Live(ctx, h.properties, property); err != nil {
+			return problem.Internal("Failed to fetch similar properties", err)
+		}
+	} else {
+		return problem.Internal("Failed to fetch similar properties", err)
 	}
 
-	// Use $in to filter properties in any of the preferred locations
-	filter := bson.M{"location": bson.M{"$in": user.PreferredLocations}}
+	response := models.Page[models.SimilarProperty]{Items: []models.SimilarProperty{}}
+	if len(scores) == 0 {
+		return c.JSON(response)
+	}
 
-	cursor, err := db.PropertyCollection().Find(ctx, filter)
+	// Listings rented or deleted since the list was computed are skipped
+	ids := make([]primitive.ObjectID, len(scores))
+	for i, score := range scores {
+		ids[i] = score.PropertyID
+	}
+	results, err := h.properties.Search(ctx, repository.PropertySearch{IDs: ids, Available: true}, repository.Page{})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch properties"})
+		return problem.Internal("Failed to fetch similar properties", err)
+	}
+	found := make(map[primitive.ObjectID]models.Property, len(results))
+	for _, result := range results {
+		found[result.ID] = result.Property
+	}
+	for _, score := range scores {
+		if p, ok := found[score.PropertyID]; ok && len(response.Items) < limit {
+			hideUsers(&p, auth.CurrentUser(c))
+			response.Items = append(response.Items, models.SimilarProperty{Property: p, Similarity: score.Score})
+		}
+	}
+	return c.JSON(response)
+}
+
+// listProperties responds with a page of the properties matching the search,
+// sorted by creation time, price or likes. failure is the error sent when
+// the properties cannot be fetched.
+func listProperties(c *fiber.Ctx, properties repository.PropertyRepository, search repository.PropertySearch, failure string) error {
+	page, err := parsePage(c, repository.SortCreatedAt, repository.SortPrice, repository.SortLikes)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	results, err := properties.Search(ctx, search, peek(page))
+	if err != nil {
+		return searchError(err, failure)
+	}
+	items := make([]models.Property, len(results))
+	for i := range results {
+		items[i] = results[i].Property
+	}
+
+	response := newPage(items, page, func(p *models.Property) (float64, primitive.ObjectID) {
+		return repository.PropertySortValue(&models.PropertySearchResult{Property: *p}, page.Sort), p.ID
+	})
+	for i := range response.Items {
+		hideUsers(&response.Items[i], auth.CurrentUser(c))
+	}
+	if err := countTotal(c, &response, func() (int64, error) { return properties.Count(ctx, search) }); err != nil {
+		return searchError(err, failure)
+	}
+	return c.JSON(response)
+}
+
+// propertyRequest is the listing a user writes when creating or replacing a
+// property. Owner details, likes and rental state are set by the server.
+type propertyRequest struct {
+	Title       string                    `json:"title" validate:"required,max=200"`
+	Description string                    `json:"description" validate:"max=5000"`
+	Price       float64                   `json:"price" validate:"gt=0"`
+	Location    string                    `json:"location" validate:"required,max=200"`
+	Coordinates *models.GeoPoint          `json:"coordinates,omitempty"`
+	Attributes  models.PropertyAttributes `json:"attributes"`
+	Thumbnail   string                    `json:"thumbnail,omitempty" validate:"max=2048"`
+	Pictures    []string                  `json:"pictures,omitempty" validate:"max=50,dive,required,max=2048"`
+}
+
+func (r *propertyRequest) normalize() {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Description = strings.TrimSpace(r.Description)
+	r.Location = strings.TrimSpace(r.Location)
+	r.Thumbnail = strings.TrimSpace(r.Thumbnail)
+}
+
+// bindProperty binds a propertyRequest, including its coordinates
+func bindProperty(c *fiber.Ctx) (*propertyRequest, error) {
+	input, err := bind[propertyRequest](c)
+	if err != nil {
+		return nil, err
+	}
+	if err := input.checkCoordinates(); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// checkProperty validates a listing, including its coordinates
+func checkProperty(input *propertyRequest) error {
+	if err := check(input); err != nil {
+		return err
+	}
+	return input.checkCoordinates()
+}
+
+func (r *propertyRequest) checkCoordinates() error {
+	if r.Coordinates != nil && !r.Coordinates.Valid() {
+		return invalidField("coordinates", invalidCoordinates)
+	}
+	return nil
+}
+
+// listingOf returns the listing of a property, the fields its owner writes
+func listingOf(p *models.Property) *propertyRequest {
+	return &propertyRequest{
+		Title:       p.Title,
+		Description: p.Description,
+		Price:       p.Price,
+		Location:    p.Location,
+		Coordinates: p.Coordinates,
+		Attributes:  p.Attributes,
+		Thumbnail:   p.Thumbnail,
+		Pictures:    p.Pictures,
 	}
+}
+
+// applyTo copies the listing to the property
+func (r *propertyRequest) applyTo(p *models.Property) {
+	p.Title = r.Title
+	p.Description = r.Description
+	p.Price = r.Price
+	p.Location = r.Location
+	p.Coordinates = r.Coordinates
+	p.Attributes = r.Attributes
+	p.Thumbnail = r.Thumbnail
+	p.Pictures = r.Pictures
+}
 
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to parse properties"})
+// mergePatchType is the media type of JSON Merge Patches
+const mergePatchType = "application/merge-patch+json"
+
+// serverFields are the fields of a property set by the server, which a
+// patch cannot change
+var serverFields = []string{"id", "owner_email", "owner_name", "owner_pic", "is_rented", "rented_by_email", "images", "liked_by", "created_at", "updated_at"}
+
+// patchError maps the error of applying a merge patch to the listing
+func patchError(err error) error {
+	var unknown *mergepatch.UnknownFieldsError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, mergepatch.ErrNotObject):
+		return problem.BadRequest("Invalid body, a JSON object is expected")
+	case errors.As(err, &unknown):
+		fields := make([]problem.FieldError, 0, len(unknown.Fields))
+		for _, field := range unknown.Fields {
+			message := "is not a property field"
+			if slices.Contains(serverFields, field) {
+				message = "cannot be changed"
+			}
+			fields = append(fields, problem.FieldError{Field: field, Message: message})
+		}
+		return problem.Validation("Invalid request", fields...)
+	case errors.As(err, &typeErr):
+		return invalidField(typeErr.Field, "must be "+jsonKind(typeErr.Type))
 	}
+	return problem.Internal("Failed to update property", err)
+}
 
-	return c.JSON(properties)
+// jsonKind names the kind of JSON value a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "a number"
+	case reflect.Slice:
+		return "a list"
+	}
+	return "an object"
 }
 
 // CreateProperty godoc
@@ -70,47 +340,37 @@ func GetHomescreenProperties(c *fiber.Ctx) error {
 // @Tags Properties
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param property body models.PropertySwagger true "Property data"
 // @Success 201 {object} models.PropertySwagger
-// @Failure 400 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties [post]
-func CreateProperty(c *fiber.Ctx) error {
-	// Parse only property-specific fields, not owner info
-	var input struct {
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Price       float64  `json:"price"`
-		Location    string   `json:"location"`
-		Thumbnail   string   `json:"thumbnail,omitempty"`
-		Pictures    []string `json:"pictures,omitempty"`
-	}
-	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+func (h *PropertyHandler) CreateProperty(c *fiber.Ctx) error {
+	input, err := bindProperty(c)
+	if err != nil {
+		return err
 	}
 
-	email := c.Query("email")
-	if email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+	user := auth.CurrentUser(c)
+	if !policy.CanCreateProperty(user) {
+		return problem.Forbidden("Only owners and agents can post properties")
 	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	// Find the user by email
-	var user models.User
-	if err := db.UserCollection().FindOne(ctx, bson.M{"email": email}).Decode(&user); err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
-	}
-
-	// Create a property with the fetched user info
+	// Create a property with the authenticated user's info
 	property := models.Property{
 		ID:          primitive.NewObjectID(),
 		Title:       input.Title,
 		Description: input.Description,
 		Price:       input.Price,
 		Location:    input.Location,
+		Coordinates: input.Coordinates,
+		Attributes:  input.Attributes,
 		OwnerEmail:  user.Email,
 		OwnerName:   user.Name,
 		OwnerPic:    user.ProfilePic,
@@ -121,124 +381,355 @@ func CreateProperty(c *fiber.Ctx) error {
 		UpdatedAt:   primitive.NewDateTimeFromTime(utils.Now()),
 	}
 
-	// Insert property into DB
-	if _, err := db.PropertyCollection().InsertOne(ctx, property); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create property"})
-	}
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		// Insert property into DB
+		if err := h.properties.Create(ctx, &property); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error { return h.properties.Delete(ctx, property.ID) })
 
-	// Add property ID to user's posted_properties
-	_, err := db.UserCollection().UpdateOne(
-		ctx,
-		bson.M{"email": email},
-		bson.M{"$push": bson.M{"posted_properties": property.ID}},
-	)
+		// Add property ID to user's posted_properties
+		return h.users.AddPostedProperty(ctx, user.ID, property.ID)
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Property created, but failed to update user's posted properties"})
+		return problem.Internal("Failed to create property", err)
 	}
 
+	h.webhooks.Publish(ctx, models.EventPropertyCreated, webhook.NewProperty(&property))
+
 	return c.Status(fiber.StatusCreated).JSON(property)
 }
 
-// UpdateProperty godoc
-// @Summary Update an existing property
-// @Description Update a property owned by the authenticated user
+// PatchProperty godoc
+// @Summary Update some fields of a property
+// @Description Change the listing with a JSON Merge Patch (RFC 7396): fields left out are kept, null removes a value and attributes are patched one by one. Owner details, likes, rental state and uploaded images cannot be changed. Every change is recorded with its old and new value.
 // @Tags Properties
 // @Accept json
+// @Accept application/merge-patch+json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Property ID"
-// @Param property body models.PropertySwagger true "Updated property data"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/properties/{id} [put]
-func UpdateProperty(c *fiber.Ctx) error {
+// @Param patch body models.PropertyPatchSwagger true "Fields to change"
+// @Success 200 {object} models.PropertySwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id} [patch]
+func (h *PropertyHandler) PatchProperty(c *fiber.Ctx) error {
 	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
+		return problem.BadRequest("Invalid property ID")
 	}
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	if mediaType = strings.TrimSpace(mediaType); mediaType != mergePatchType && mediaType != fiber.MIMEApplicationJSON {
+		return problem.New(fiber.StatusUnsupportedMediaType, "Send a JSON Merge Patch as "+mergePatchType)
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
 
-	var property models.Property
-	if err := c.BodyParser(&property); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+	property, err := findManagedProperty(ctx, c, h.properties, propertyID, "You cannot update a property that doesn't belong to you")
+	if err != nil {
+		return err
+	}
+
+	input := listingOf(property)
+	if err := mergepatch.Apply(input, c.Body()); err != nil {
+		return patchError(err)
+	}
+	if err := checkProperty(input); err != nil {
+		return err
 	}
 
-	// Get email from body or query
-	userEmail := property.OwnerEmail
-	if userEmail == "" {
-		userEmail = c.Query("email")
+	updated, err := h.updateListing(ctx, auth.CurrentUser(c), property, input)
+	if err != nil {
+		return err
 	}
+	return c.JSON(updated)
+}
 
-	if userEmail == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+// ListPropertyChanges godoc
+// @Summary List the changes of a property
+// @Description List the updates of a listing, newest first, with who made them and the old and new value of every field they changed. Only the owner and admins can see them.
+// @Tags Properties
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of changes"
+// @Success 200 {object} models.PageSwagger[models.PropertyChangeSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/changes [get]
+func (h *PropertyHandler) ListPropertyChanges(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
 	}
 
-	// Check if property exists and belongs to the user
-	var existingProperty models.Property
 	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
+	property, err := findManagedProperty(ctx, c, h.properties, propertyID, "Only the owner can see the changes of a property")
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	filter := repository.PropertyChangeFilter{PropertyID: property.ID}
+	return listPage(c, h.changes, filter, func(change *models.PropertyChange) primitive.ObjectID { return change.ID }, "Failed to fetch changes")
+}
+
+// findManagedProperty loads a property the current user may manage, denied
+// being the detail of the error when they may not
+func findManagedProperty(ctx context.Context, c *fiber.Ctx, properties repository.PropertyRepository, id primitive.ObjectID, denied string) (*models.Property, error) {
+	property, err := properties.FindByID(ctx, id)
+	if err != nil {
+		return nil, findError(err, "Property")
+	}
+	if !policy.CanManageProperty(auth.CurrentUser(c), property) {
+		return nil, problem.Forbidden(denied)
+	}
+	return property, nil
+}
 
-	err = db.PropertyCollection().FindOne(ctx, bson.M{"_id": propertyID}).Decode(&existingProperty)
-	if err != nil || existingProperty.OwnerEmail != userEmail {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "You cannot update a property that doesn't belong to you"})
+// rejectPendingRequests rejects the pending requests for a deleted property
+// and notifies their applicants. Failures are only logged: the property is
+// gone and whatever is left will expire.
+func (h *PropertyHandler) rejectPendingRequests(ctx context.Context, property *models.Property, by string) {
+	pending, err := h.requests.List(ctx, repository.RentalRequestFilter{
+		PropertyID: property.ID,
+		Status:     models.RentalRequestPending,
+	}, repository.Page{})
+	if err != nil {
+		log.Println("Failed to list pending rental requests:", err)
+		return
 	}
 
-	property.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+	change := models.StatusChange{
+		Status: models.RentalRequestRejected,
+		By:     by,
+		Note:   "The property has been removed",
+		At:     primitive.NewDateTimeFromTime(utils.Now()),
+	}
+	for _, request := range pending {
+		err := h.requests.Transition(ctx, request.ID, models.RentalRequestPending, change)
+		if err != nil {
+			if !errors.Is(err, repository.ErrConflict) {
+				log.Println("Failed to reject rental request", request.ID.Hex(), err)
+			}
+			continue
+		}
+		recordStatusChange(&request, change)
+		h.webhooks.Publish(ctx, models.EventRentalRequestRejected, webhook.NewRentalRequest(&request))
+		h.notifier.NotifyID(ctx, request.ApplicantID, notify.RentalRequestDecided(&request, property, change.Note))
+	}
+}
 
-	_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{"$set": property})
+// cancelViewings cancels the upcoming viewings of a deleted property,
+// notifies their visitors and removes its slots. Failures are only logged.
+func (h *PropertyHandler) cancelViewings(ctx context.Context, property *models.Property, by string) {
+	now := utils.Now()
+	viewings, err := h.viewings.List(ctx, repository.ViewingFilter{
+		PropertyID: property.ID,
+		Status:     models.ViewingScheduled,
+		From:       now,
+	}, repository.Page{})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update property"})
+		log.Println("Failed to list viewings:", err)
+		return
+	}
+
+	cancellation := models.ViewingCancellation{
+		By:     by,
+		Reason: "The property has been removed",
+		At:     primitive.NewDateTimeFromTime(now),
+	}
+	for _, viewing := range viewings {
+		if err := h.viewings.Cancel(ctx, viewing.ID, cancellation); err != nil {
+			if !errors.Is(err, repository.ErrConflict) {
+				log.Println("Failed to cancel viewing", viewing.ID.Hex(), err)
+			}
+			continue
+		}
+		if err := h.slots.Release(ctx, viewing.SlotID, viewing.ID); err != nil {
+			log.Println("Failed to release viewing slot", viewing.SlotID.Hex(), err)
+		}
+		viewing.Status = models.ViewingCancelled
+		viewing.Cancellation = &cancellation
+		viewing.UpdatedAt = cancellation.At
+		h.notifier.NotifyID(ctx, viewing.VisitorID, notify.ViewingCancelled(&viewing))
 	}
 
-	return c.JSON(fiber.Map{"message": "Property updated"})
+	slots, err := h.slots.List(ctx, repository.ViewingSlotFilter{PropertyID: property.ID, From: now}, repository.Page{})
+	if err != nil {
+		log.Println("Failed to list viewing slots:", err)
+		return
+	}
+	for _, slot := range slots {
+		if err := h.slots.Delete(ctx, slot.ID); err != nil && !errors.Is(err, repository.ErrConflict) {
+			log.Println("Failed to delete viewing slot", slot.ID.Hex(), err)
+		}
+	}
+}
+
+// updateListing writes the fields of the listing that differ from the
+// property and records the change. Server-owned fields are never written, so
+// likes or images added meanwhile are kept. It returns the updated property.
+func (h *PropertyHandler) updateListing(ctx context.Context, user *models.User, property *models.Property, input *propertyRequest) (*models.Property, error) {
+	diff, err := mergepatch.Diff(listingOf(property), input)
+	if err != nil {
+		return nil, problem.Internal("Failed to update property", err)
+	}
+	if len(diff) == 0 {
+		return property, nil
+	}
+
+	updated := *property
+	input.applyTo(&updated)
+	updated.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+
+	change := models.PropertyChange{
+		ID:         primitive.NewObjectID(),
+		PropertyID: property.ID,
+		By:         user.Email,
+		At:         updated.UpdatedAt,
+	}
+	var fields []string
+	for _, d := range diff {
+		change.Changes = append(change.Changes, models.FieldChange{Field: d.Field, From: d.From, To: d.To})
+		field, _, _ := strings.Cut(d.Field, ".")
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if err := h.changes.Create(ctx, &change); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error { return h.changes.Delete(ctx, change.ID) })
+
+		return h.properties.UpdateListing(ctx, &updated, fields, property.UpdatedAt)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, problem.Conflict("The property was changed by someone else, please retry").WithCode(problem.CodeConcurrentUpdate)
+	}
+	if err != nil {
+		return nil, findError(err, "Property")
+	}
+
+	// Read it back to include what changed meanwhile, such as likes
+	if fresh, err := h.properties.FindByID(ctx, property.ID); err == nil {
+		updated = *fresh
+	} else {
+		log.Println("Failed to fetch updated property", property.ID.Hex(), err)
+	}
+	h.webhooks.Publish(ctx, models.EventPropertyUpdated, webhook.NewProperty(&updated))
+	return &updated, nil
 }
 
 // DeleteProperty godoc
 // @Summary Delete a property
-// @Description Delete a property owned by the authenticated user
+// @Description Delete a property owned by the authenticated user. It is removed from the owner's posted properties and the liked properties of users, its pending rental requests are rejected and its upcoming viewings cancelled.
 // @Tags Properties
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Property ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/{id} [delete]
-func DeleteProperty(c *fiber.Ctx) error {
+func (h *PropertyHandler) DeleteProperty(c *fiber.Ctx) error {
 	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
-	}
-
-	// Get email from body or query
-	var property models.Property
-	userEmail := c.Query("email")
-	if userEmail == "" {
-		if err := c.BodyParser(&property); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
-		}
-		userEmail = property.OwnerEmail
+		return problem.BadRequest("Invalid property ID")
 	}
 
-	if userEmail == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
-	}
+	user := auth.CurrentUser(c)
 
-	// Check if the property exists and belongs to the user
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	err = db.PropertyCollection().FindOne(ctx, bson.M{"_id": propertyID}).Decode(&property)
-	if err != nil || property.OwnerEmail != userEmail {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "You cannot delete a property that doesn't belong to you"})
+	// Check if the property exists and the user may manage it
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+	if !policy.CanManageProperty(user, property) {
+		return problem.Forbidden("You cannot delete a property that doesn't belong to you")
 	}
 
-	_, err = db.PropertyCollection().DeleteOne(ctx, bson.M{"_id": propertyID})
+	// A rented property has to wait for its lease to end
+	if _, err := h.leases.FindActiveByProperty(ctx, propertyID); err == nil {
+		return problem.Conflict("Property has an active lease")
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return problem.Internal("Failed to delete property", err)
+	}
+
+	owner := user
+	if property.OwnerEmail != user.Email {
+		if owner, err = h.users.FindByEmail(ctx, property.OwnerEmail); errors.Is(err, repository.ErrNotFound) {
+			owner = nil
+		} else if err != nil {
+			return problem.Internal("Failed to delete property", err)
+		}
+	}
+
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if err := h.properties.Delete(ctx, propertyID); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error { return h.properties.Create(ctx, property) })
+
+		if owner != nil {
+			if err := h.users.RemovePostedProperty(ctx, owner.ID, propertyID); err != nil {
+				return err
+			}
+			tx.OnRollback(func(ctx context.Context) error { return h.users.AddPostedProperty(ctx, owner.ID, propertyID) })
+		}
+
+		for _, email := range property.LikedBy {
+			liker, err := h.users.FindByEmail(ctx, email)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := h.users.RemoveLike(ctx, liker.ID, propertyID); err != nil {
+				return err
+			}
+			tx.OnRollback(func(ctx context.Context) error { return h.users.AddLike(ctx, liker.ID, propertyID) })
+		}
+		return nil
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete property"})
+		return findError(err, "Property")
+	}
+
+	h.rejectPendingRequests(ctx, property, user.Email)
+	h.cancelViewings(ctx, property, user.Email)
+	for _, img := range property.Images {
+		if err := images.Delete(ctx, h.blobs, &img); err != nil {
+			log.Println("Failed to delete picture files", img.ID.Hex(), err)
+		}
 	}
 
+	h.webhooks.Publish(ctx, models.EventPropertyDeleted, webhook.NewProperty(property))
+
 	return c.JSON(fiber.Map{"message": "Property deleted"})
 }
 
@@ -248,42 +739,47 @@ func DeleteProperty(c *fiber.Ctx) error {
 // @Tags Properties
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Property ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/{id}/like [post]
-func LikeProperty(c *fiber.Ctx) error {
+func (h *PropertyHandler) LikeProperty(c *fiber.Ctx) error {
 	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
+		return problem.BadRequest("Invalid property ID")
 	}
 
-	// Get email from body or query
-	userEmail := c.Query("email")
-	if userEmail == "" {
-		var property models.Property
-		if err := c.BodyParser(&property); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
-		}
-		userEmail = property.OwnerEmail
-	}
-
-	if userEmail == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
-	}
+	user := auth.CurrentUser(c)
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{"$addToSet": bson.M{"liked_by": userEmail}})
+	property, err := h.properties.FindByID(ctx, propertyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to like property"})
+		return findError(err, "Property")
 	}
+	alreadyLiked := slices.Contains(property.LikedBy, user.Email)
+
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if err := h.properties.AddLike(ctx, propertyID, user.Email); err != nil {
+			return err
+		}
+		if !alreadyLiked {
+			tx.OnRollback(func(ctx context.Context) error { return h.properties.RemoveLike(ctx, propertyID, user.Email) })
+		}
 
-	_, err = db.UserCollection().UpdateOne(ctx, bson.M{"email": userEmail}, bson.M{"$addToSet": bson.M{"liked_properties": propertyID}})
+		return h.users.AddLike(ctx, user.ID, propertyID)
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user liked properties"})
+		return problem.Internal("Failed to like property", err)
+	}
+
+	if !alreadyLiked && property.OwnerEmail != user.Email {
+		h.notifier.NotifyEmail(ctx, property.OwnerEmail, notify.PropertyLiked(property, user))
 	}
 
 	return c.JSON(fiber.Map{"message": "Property liked"})
@@ -295,42 +791,44 @@ func LikeProperty(c *fiber.Ctx) error {
 // @Tags Properties
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Property ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/{id}/unlike [post]
-func UnlikeProperty(c *fiber.Ctx) error {
+func (h *PropertyHandler) UnlikeProperty(c *fiber.Ctx) error {
 	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
+		return problem.BadRequest("Invalid property ID")
 	}
 
-	// Get email from body or query
-	userEmail := c.Query("email")
-	if userEmail == "" {
-		var property models.Property
-		if err := c.BodyParser(&property); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
-		}
-		userEmail = property.OwnerEmail
-	}
-
-	if userEmail == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
-	}
+	user := auth.CurrentUser(c)
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{"$pull": bson.M{"liked_by": userEmail}})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to unlike property"})
+	// A deleted property can still be removed from the user's list
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return problem.Internal("Failed to unlike property", err)
 	}
 
-	_, err = db.UserCollection().UpdateOne(ctx, bson.M{"email": userEmail}, bson.M{"$pull": bson.M{"liked_properties": propertyID}})
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if property != nil {
+			if err := h.properties.RemoveLike(ctx, propertyID, user.Email); err != nil {
+				return err
+			}
+			if slices.Contains(property.LikedBy, user.Email) {
+				tx.OnRollback(func(ctx context.Context) error { return h.properties.AddLike(ctx, propertyID, user.Email) })
+			}
+		}
+
+		return h.users.RemoveLike(ctx, user.ID, propertyID)
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user liked properties"})
+		return problem.Internal("Failed to unlike property", err)
 	}
 
 	return c.JSON(fiber.Map{"message": "Property unliked"})
@@ -338,154 +836,379 @@ func UnlikeProperty(c *fiber.Ctx) error {
 
 // GetUserLikedProperties godoc
 // @Summary Get properties liked by the user
-// @Description Get properties liked by the user based on email
+// @Description Get properties liked by the authenticated user
 // @Tags Properties
 // @Accept json
 // @Produce json
-// @Param email query string true "User email"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Param sort query string false "Sort by" Enums(created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of properties"
+// @Success 200 {object} models.PageSwagger[models.PropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/liked-properties [get]
-func GetLikedPropertiesByUser(c *fiber.Ctx) error {
-	email := c.Query("email")
-	if email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
-	}
-
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
+func (h *PropertyHandler) GetLikedPropertiesByUser(c *fiber.Ctx) error {
+	return listPropertiesByID(c, h.properties, auth.CurrentUser(c).LikedProperties, "Failed to fetch liked properties")
+}
 
-	// Step 1: Find the user by email
-	var user models.User
-	if err := db.UserCollection().FindOne(ctx, bson.M{"email": email}).Decode(&user); err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+// hideUsers leaves out who liked or rented the property unless user manages
+// it, as other users' emails are not shared. User still sees their own like
+// and rental.
+func hideUsers(property *models.Property, user *models.User) {
+	if policy.CanManageProperty(user, property) {
+		return
 	}
-
-	// Step 2: If the user has no liked properties
-	if len(user.LikedProperties) == 0 {
-		return c.JSON([]models.Property{}) // return empty array
+	liked := slices.Contains(property.LikedBy, user.Email)
+	property.LikedBy = nil
+	if liked {
+		property.LikedBy = []string{user.Email}
 	}
-
-	// Step 3: Fetch the liked properties
-	cursor, err := db.PropertyCollection().Find(ctx, bson.M{
-		"_id": bson.M{"$in": user.LikedProperties},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch liked properties"})
+	if property.RentedByEmail != user.Email {
+		property.RentedByEmail = ""
 	}
+}
 
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode properties"})
+// listPropertiesByID is listProperties for the given properties
+func listPropertiesByID(c *fiber.Ctx, properties repository.PropertyRepository, ids []primitive.ObjectID, failure string) error {
+	if len(ids) == 0 {
+		// An empty IDs filter would match every property
+		return c.JSON(models.Page[models.Property]{Items: []models.Property{}})
 	}
-
-	return c.JSON(properties)
+	return listProperties(c, properties, repository.PropertySearch{IDs: ids}, failure)
 }
 
 // SearchProperties godoc
 // @Summary Search properties
-// @Description Search for properties by location, price, etc.
+// @Description Search for properties by keywords, location, price, listing attributes, etc.
+// @Description With q, properties matching any of its words in the title, description or location are returned by relevance, with the matched words highlighted.
 // @Tags Properties
 // @Accept json
 // @Produce json
-// @Param location query string false "Location"
-// @Param min_price query string false "Minimum price"
-// @Param max_price query string false "Maximum price"
-// @Param limit query int false "Limit"
-// @Param skip query int false "Skip"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 500 {object} map[string]string
+// @Param q query string false "Keywords"
+// @Param location query string false "Location, matches partially and ignoring case"
+// @Param bbox query string false "Only properties inside the box min_lng,min_lat,max_lng,max_lat, for map views"
+// @Param lat query number false "Latitude to measure the distance of each result from, defaults to the user's coordinates"
+// @Param lng query number false "Longitude to measure the distance of each result from, defaults to the user's coordinates"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param type query string false "Property types, comma separated" Enums(apartment, house, villa, studio, room)
+// @Param bedrooms query int false "Exact number of bedrooms"
+// @Param min_bedrooms query int false "Minimum bedrooms"
+// @Param max_bedrooms query int false "Maximum bedrooms"
+// @Param min_bathrooms query int false "Minimum bathrooms"
+// @Param min_area query number false "Minimum area in square feet"
+// @Param max_area query number false "Maximum area in square feet"
+// @Param furnishing query string false "Furnishing, comma separated" Enums(unfurnished, semi_furnished, furnished)
+// @Param pets_allowed query bool false "Pets allowed"
+// @Param amenities query string false "Required amenities, comma separated"
+// @Param facets query bool false "Include facet counts over all matching properties"
+// @Param sort query string false "Sort by, relevance by default with q" Enums(relevance, created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of matching properties"
+// @Security BearerAuth
+// @Success 200 {object} models.PropertySearchPageSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/properties/search [get]
-func SearchProperties(c *fiber.Ctx) error {
-	location := c.Query("location")
-	minPrice := c.Query("min_price")
-	maxPrice := c.Query("max_price")
+func (h *PropertyHandler) SearchProperties(c *fiber.Ctx) error {
+	search := repository.PropertySearch{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Location: strings.TrimSpace(c.Query("location")),
+	}
+	if err := parseListingFilters(c, &search); err != nil {
+		return problem.Validation(err.Error())
+	}
 
-	filter := bson.M{}
-	if location != "" {
-		filter["location"] = location
+	sorts := []repository.SortField{repository.SortCreatedAt, repository.SortPrice, repository.SortLikes}
+	if search.Query != "" {
+		sorts = slices.Insert(sorts, 0, repository.SortRelevance)
+	}
+	page, err := parsePage(c, sorts...)
+	if err != nil {
+		return problem.Validation(err.Error())
 	}
 
-	if minPrice != "" || maxPrice != "" {
-		priceFilter := bson.M{}
-		if minVal, err := strconv.Atoi(minPrice); err == nil {
-			priceFilter["$gte"] = minVal
-		}
-		if maxVal, err := strconv.Atoi(maxPrice); err == nil {
-			priceFilter["$lte"] = maxVal
-		}
-		if len(priceFilter) > 0 {
-			filter["price"] = priceFilter
+	if bbox := c.Query("bbox"); bbox != "" {
+		box, err := parseBox(bbox)
+		if err != nil {
+			return problem.Validation("bbox must be min_lng,min_lat,max_lng,max_lat")
 		}
+		search.Box = box
 	}
 
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	skip, _ := strconv.Atoi(c.Query("skip", "0"))
+	origin, err := queryPoint(c)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	cursor, err := db.PropertyCollection().Find(ctx, filter, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	results, err := h.properties.Search(ctx, search, peek(page))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch properties"})
+		return searchError(err, "Failed to fetch properties")
 	}
 
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to parse properties"})
+	if origin != nil {
+		for i := range results {
+			if point := results[i].Coordinates; point != nil {
+				distance := origin.DistanceTo(point)
+				results[i].Distance = &distance
+			}
+		}
 	}
-	return c.JSON(properties)
+	if terms := textsearch.Terms(search.Query); len(terms) > 0 {
+		for i := range results {
+			results[i].Highlights = highlight(&results[i].Property, terms)
+		}
+	}
+	return h.respondWithPage(ctx, c, search, page, results)
 }
 
-// RequestToRentProperty godoc
-// @Summary Request to rent a property
-// @Description Send a rental request for a property
+// NearbyProperties godoc
+// @Summary Find properties nearby
+// @Description Find properties within a radius of a point, nearest first, with their distance in meters. The point defaults to the authenticated user's coordinates.
 // @Tags Properties
-// @Accept json
 // @Produce json
-// @Param id path string true "Property ID"
-// @Param email query string true "User email"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/properties/{id}/request [post]
-func RequestToRentProperty(c *fiber.Ctx) error {
-	propertyIDParam := c.Params("id")
-	userIDParam := c.Query("user_id")
-
-	if propertyIDParam == "" || userIDParam == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Property ID and user ID are required"})
+// @Security BearerAuth
+// @Param lat query number false "Latitude"
+// @Param lng query number false "Longitude"
+// @Param radius_km query number false "Radius in kilometers, 5 by default and at most 100"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param type query string false "Property types, comma separated" Enums(apartment, house, villa, studio, room)
+// @Param bedrooms query int false "Exact number of bedrooms"
+// @Param min_bedrooms query int false "Minimum bedrooms"
+// @Param max_bedrooms query int false "Maximum bedrooms"
+// @Param min_bathrooms query int false "Minimum bathrooms"
+// @Param min_area query number false "Minimum area in square feet"
+// @Param max_area query number false "Maximum area in square feet"
+// @Param furnishing query string false "Furnishing, comma separated" Enums(unfurnished, semi_furnished, furnished)
+// @Param pets_allowed query bool false "Pets allowed"
+// @Param amenities query string false "Required amenities, comma separated"
+// @Param facets query bool false "Include facet counts over all matching properties"
+// @Param sort query string false "Sort by" Enums(distance, created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and distance and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of matching properties"
+// @Success 200 {object} models.PropertySearchPageSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/nearby [get]
+func (h *PropertyHandler) NearbyProperties(c *fiber.Ctx) error {
+	near, err := queryPoint(c)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+	if near == nil {
+		return problem.Validation("lat and lng are required when your coordinates are not set")
 	}
 
-	propertyID, err := primitive.ObjectIDFromHex(propertyIDParam)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
+	radius := defaultNearbyRadius
+	if value := c.Query("radius_km"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadius {
+			return problem.Validation(fmt.Sprintf("radius_km must be between 0 and %g", maxNearbyRadius))
+		}
 	}
 
-	userID, err := primitive.ObjectIDFromHex(userIDParam)
+	search := repository.PropertySearch{
+		Near:        near,
+		MaxDistance: radius * 1000,
+	}
+	if err := parseListingFilters(c, &search); err != nil {
+		return problem.Validation(err.Error())
+	}
+	page, err := parsePage(c, repository.SortDistance, repository.SortCreatedAt, repository.SortPrice, repository.SortLikes)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return problem.Validation(err.Error())
 	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	// Add user ID to property's rental_requests
-	_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{
-		"$addToSet": bson.M{"rental_requests": userID},
-	})
+	results, err := h.properties.Search(ctx, search, peek(page))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to add rental request"})
+		return searchError(err, "Failed to fetch properties")
 	}
+	return h.respondWithPage(ctx, c, search, page, results)
+}
 
-	// Add property ID to user's rental_requests
-	_, err = db.UserCollection().UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
-		"$addToSet": bson.M{"rental_requests": propertyID},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user's request list"})
+// respondWithPage sends the results fetched with peek(page), with the total
+// and the facets of the whole search when requested
+func (h *PropertyHandler) respondWithPage(ctx context.Context, c *fiber.Ctx, search repository.PropertySearch, page repository.Page, results []models.PropertySearchResult) error {
+	response := models.PropertySearchPage{
+		Page: newPage(results, page, func(r *models.PropertySearchResult) (float64, primitive.ObjectID) {
+			return repository.PropertySortValue(r, page.Sort), r.ID
+		}),
+	}
+	for i := range response.Items {
+		hideUsers(&response.Items[i].Property, auth.CurrentUser(c))
+	}
+	if err := countTotal(c, &response.Page, func() (int64, error) { return h.properties.Count(ctx, search) }); err != nil {
+		return searchError(err, "Failed to count properties")
+	}
+	if c.QueryBool("facets") {
+		facets, err := h.properties.Facets(ctx, search)
+		if err != nil {
+			return searchError(err, "Failed to count facets")
+		}
+		response.Facets = facets
+	}
+	return c.JSON(response)
+}
+
+// parseListingFilters reads the price and attribute filters shared by the search endpoints
+func parseListingFilters(c *fiber.Ctx, search *repository.PropertySearch) error {
+	floats := []struct {
+		param string
+		into  **float64
+	}{
+		{"min_price", &search.MinPrice},
+		{"max_price", &search.MaxPrice},
+		{"min_area", &search.MinArea},
+		{"max_area", &search.MaxArea},
+	}
+	for _, p := range floats {
+		if value := c.Query(p.param); value != "" {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				return fmt.Errorf("%s must be a number", p.param)
+			}
+			*p.into = &n
+		}
+	}
+
+	for _, t := range splitList(c.Query("type")) {
+		if !models.PropertyType(t).Valid() {
+			return fmt.Errorf("unknown type %q", t)
+		}
+		search.Types = append(search.Types, models.PropertyType(t))
 	}
+	for _, f := range splitList(c.Query("furnishing")) {
+		if !models.Furnishing(f).Valid() {
+			return fmt.Errorf("unknown furnishing %q", f)
+		}
+		search.Furnishing = append(search.Furnishing, models.Furnishing(f))
+	}
+	search.Amenities = splitList(c.Query("amenities"))
+
+	ints := []struct {
+		param string
+		into  **int
+	}{
+		{"min_bedrooms", &search.MinBedrooms},
+		{"max_bedrooms", &search.MaxBedrooms},
+		{"min_bathrooms", &search.MinBathrooms},
+	}
+	for _, p := range ints {
+		if value := c.Query(p.param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a whole number", p.param)
+			}
+			*p.into = &n
+		}
+	}
+	if value := c.Query("bedrooms"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("bedrooms must be a whole number")
+		}
+		search.MinBedrooms, search.MaxBedrooms = &n, &n
+	}
+
+	if value := c.Query("pets_allowed"); value != "" {
+		allowed, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("pets_allowed must be true or false")
+		}
+		search.PetsAllowed = &allowed
+	}
+
+	switch {
+	case search.MinPrice != nil && search.MaxPrice != nil && *search.MinPrice > *search.MaxPrice:
+		return errors.New("min_price must not be greater than max_price")
+	case search.MinArea != nil && search.MaxArea != nil && *search.MinArea > *search.MaxArea:
+		return errors.New("min_area must not be greater than max_area")
+	case search.MinBedrooms != nil && search.MaxBedrooms != nil && *search.MinBedrooms > *search.MaxBedrooms:
+		return errors.New("min_bedrooms must not be greater than max_bedrooms")
+	}
+	return nil
+}
+
+// splitList splits a comma separated query parameter, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// queryPoint reads the lat and lng query parameters, falling back to the
+// user's coordinates. It returns nil when neither is available.
+func queryPoint(c *fiber.Ctx) (*models.GeoPoint, error) {
+	lat, lng := c.Query("lat"), c.Query("lng")
+	if lat == "" && lng == "" {
+		return auth.CurrentUser(c).Coordinates, nil
+	}
+
+	latVal, latErr := strconv.ParseFloat(lat, 64)
+	lngVal, lngErr := strconv.ParseFloat(lng, 64)
+	point := models.NewGeoPoint(lngVal, latVal)
+	if latErr != nil || lngErr != nil || !point.Valid() {
+		return nil, errors.New("lat and lng must both be valid coordinates")
+	}
+	return point, nil
+}
+
+// parseBox parses a min_lng,min_lat,max_lng,max_lat bounding box
+func parseBox(value string) (*models.GeoBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("expected four numbers")
+	}
+	var corners [4]float64
+	for i, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		corners[i] = n
+	}
+
+	box := &models.GeoBox{MinLng: corners[0], MinLat: corners[1], MaxLng: corners[2], MaxLat: corners[3]}
+	if !box.Valid() {
+		return nil, errors.New("corners out of range or out of order")
+	}
+	return box, nil
+}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Rental request sent"})
+// highlight returns the fields of the property that match the terms with the
+// matching words marked
+func highlight(property *models.Property, terms []string) map[string]string {
+	highlights := map[string]string{}
+	fields := map[string]string{
+		"title":       property.Title,
+		"description": property.Description,
+		"location":    property.Location,
+	}
+	for name, text := range fields {
+		if marked := textsearch.Highlight(text, terms); marked != "" {
+			highlights[name] = marked
+		}
+	}
+	return highlights
 }
@@ -0,0 +1,516 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/ledger"
+	"dwello-api/models"
+	"dwello-api/notify"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"dwello-api/webhook"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RentalRequestHandler serves the /api/rental-requests routes
+type RentalRequestHandler struct {
+	transactor repository.Transactor
+	users      repository.UserRepository
+	properties repository.PropertyRepository
+	requests   repository.RentalRequestRepository
+	leases     repository.LeaseRepository
+	invoices   repository.InvoiceRepository
+	notifier   *notify.Dispatcher
+	webhooks   *webhook.Publisher
+}
+
+func NewRentalRequestHandler(transactor repository.Transactor, users repository.UserRepository, properties repository.PropertyRepository, requests repository.RentalRequestRepository, leases repository.LeaseRepository, invoices repository.InvoiceRepository, notifier *notify.Dispatcher, webhooks *webhook.Publisher) *RentalRequestHandler {
+	return &RentalRequestHandler{transactor: transactor, users: users, properties: properties, requests: requests, leases: leases, invoices: invoices, notifier: notifier, webhooks: webhooks}
+}
+
+type rentalRequestRequest struct {
+	PropertyID string `json:"property_id" validate:"required,objectid"`
+	Message    string `json:"message" validate:"max=1000"`
+	MoveInDate string `json:"move_in_date" validate:"omitempty,date"`
+}
+
+func (r *rentalRequestRequest) normalize() {
+	r.Message = strings.TrimSpace(r.Message)
+}
+
+// CreateRentalRequest godoc
+// @Summary Request to rent a property
+// @Description Send a rental request for a property. An applicant can have one pending request per property.
+// @Tags Rental Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RentalRequestCreateSwagger true "Rental request"
+// @Success 201 {object} models.RentalRequestSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/rental-requests [post]
+func (h *RentalRequestHandler) CreateRentalRequest(c *fiber.Ctx) error {
+	input, err := bind[rentalRequestRequest](c)
+	if err != nil {
+		return err
+	}
+	propertyID, _ := primitive.ObjectIDFromHex(input.PropertyID)
+
+	var moveInDate primitive.DateTime
+	if input.MoveInDate != "" {
+		date, _ := parseDate(input.MoveInDate)
+		if date.Before(utils.Now().Truncate(24 * time.Hour)) {
+			return invalidField("move_in_date", "cannot be in the past")
+		}
+		moveInDate = primitive.NewDateTimeFromTime(date)
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+
+	if !policy.CanRequestRental(user, property) {
+		return problem.Forbidden("You cannot request to rent this property")
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	request := models.RentalRequest{
+		ID:             primitive.NewObjectID(),
+		PropertyID:     property.ID,
+		OwnerEmail:     property.OwnerEmail,
+		ApplicantID:    user.ID,
+		ApplicantEmail: user.Email,
+		ApplicantName:  user.Name,
+		Message:        input.Message,
+		MoveInDate:     moveInDate,
+		Status:         models.RentalRequestPending,
+		History:        []models.StatusChange{{Status: models.RentalRequestPending, By: user.Email, At: now}},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = h.requests.Create(ctx, &request)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return problem.Conflict("You already have a pending request for this property")
+	}
+	if err != nil {
+		return problem.Internal("Failed to create rental request", err)
+	}
+
+	h.notifier.NotifyEmail(ctx, property.OwnerEmail, notify.RentalRequested(&request, property))
+	h.webhooks.Publish(ctx, models.EventRentalRequestCreated, webhook.NewRentalRequest(&request))
+
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// ListRentalRequests godoc
+// @Summary List rental requests
+// @Description List the rental requests the authenticated user has sent (as=applicant) or received for their properties (as=owner), newest first
+// @Tags Rental Requests
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "applicant (default) or owner"
+// @Param status query string false "Filter by status" Enums(pending, accepted, rejected, withdrawn, expired)
+// @Param property_id query string false "Filter by property"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of rental requests"
+// @Success 200 {object} models.PageSwagger[models.RentalRequestSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/rental-requests [get]
+func (h *RentalRequestHandler) ListRentalRequests(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.RentalRequestFilter
+	switch c.Query("as", "applicant") {
+	case "applicant":
+		filter.ApplicantID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be applicant or owner")
+	}
+
+	if status := models.RentalRequestStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			return problem.Validation("Invalid status")
+		}
+		filter.Status = status
+	}
+
+	if propertyIDParam := c.Query("property_id"); propertyIDParam != "" {
+		propertyID, err := primitive.ObjectIDFromHex(propertyIDParam)
+		if err != nil {
+			return problem.BadRequest("Invalid property ID")
+		}
+		filter.PropertyID = propertyID
+	}
+
+	return listPage(c, h.requests, filter, func(r *models.RentalRequest) primitive.ObjectID { return r.ID }, "Failed to fetch rental requests")
+}
+
+// GetRentalRequest godoc
+// @Summary Get a rental request
+// @Description Get a rental request with its full status history. Visible to the applicant and the property owner.
+// @Tags Rental Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rental request ID"
+// @Success 200 {object} models.RentalRequestSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/rental-requests/{id} [get]
+func (h *RentalRequestHandler) GetRentalRequest(c *fiber.Ctx) error {
+	requestID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid rental request ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	// Requests the user may not see are reported as missing
+	request, err := h.requests.FindByID(ctx, requestID)
+	if err != nil {
+		return findError(err, "Rental request")
+	}
+	if !policy.CanViewRentalRequest(auth.CurrentUser(c), request) {
+		return problem.NotFound("Rental request not found")
+	}
+	return c.JSON(request)
+}
+
+// WithdrawRentalRequest godoc
+// @Summary Withdraw a rental request
+// @Description Withdraw a pending rental request. Only the applicant can withdraw.
+// @Tags Rental Requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rental request ID"
+// @Success 200 {object} models.RentalRequestSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/rental-requests/{id}/withdraw [post]
+func (h *RentalRequestHandler) WithdrawRentalRequest(c *fiber.Ctx) error {
+	requestID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid rental request ID")
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	request, err := h.requests.FindByID(ctx, requestID)
+	if err != nil {
+		return findError(err, "Rental request")
+	}
+	if !policy.CanViewRentalRequest(user, request) {
+		return problem.NotFound("Rental request not found")
+	}
+	if !policy.CanWithdrawRentalRequest(user, request) {
+		return problem.Forbidden("Only the applicant can withdraw a rental request")
+	}
+
+	change := models.StatusChange{
+		Status: models.RentalRequestWithdrawn,
+		By:     user.Email,
+		At:     primitive.NewDateTimeFromTime(utils.Now()),
+	}
+	if err := h.transition(ctx, request, change); err != nil {
+		return transitionError(err)
+	}
+
+	return c.JSON(request)
+}
+
+// decisionRequest is the body of DecideRentalRequest. The lease terms are
+// only used when accepting.
+type decisionRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=accept reject"`
+	Note     string `json:"note" validate:"max=1000"`
+
+	StartDate   string   `json:"start_date" validate:"omitempty,date"`
+	EndDate     string   `json:"end_date" validate:"omitempty,date"`
+	MonthlyRent *float64 `json:"monthly_rent" validate:"omitempty,min=0"`
+	Deposit     float64  `json:"deposit" validate:"min=0"`
+}
+
+func (r *decisionRequest) normalize() {
+	r.Note = strings.TrimSpace(r.Note)
+}
+
+// errAlreadyRented tells a property rented meanwhile from a request decided
+// meanwhile, both of which are conflicts
+var errAlreadyRented = errors.New("property already rented")
+
+// DecideRentalRequest godoc
+// @Summary Accept or reject a rental request
+// @Description Accept or reject a pending rental request for one of your properties. Accepting creates a lease, issues its deposit and rent invoices, marks the property as rented and rejects the other pending requests for it.
+// @Description Lease terms default to the requested move-in date (or today), a one year term, the listed price and no deposit. The start date cannot be in the past.
+// @Tags Rental Requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rental request ID"
+// @Param decision body models.RentalRequestDecisionSwagger true "Decision"
+// @Success 200 {object} models.RentalRequestSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/rental-requests/{id}/decision [post]
+func (h *RentalRequestHandler) DecideRentalRequest(c *fiber.Ctx) error {
+	requestID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid rental request ID")
+	}
+
+	input, err := bind[decisionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	status := models.RentalRequestRejected
+	if input.Decision == "accept" {
+		status = models.RentalRequestAccepted
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	request, err := h.requests.FindByID(ctx, requestID)
+	if err != nil {
+		return findError(err, "Rental request")
+	}
+	if !policy.CanViewRentalRequest(user, request) {
+		return problem.NotFound("Rental request not found")
+	}
+
+	property, err := h.properties.FindByID(ctx, request.PropertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+	if !policy.CanDecideRentalRequest(user, property) {
+		return problem.Forbidden("You cannot handle rental requests for this property")
+	}
+
+	change := models.StatusChange{
+		Status: status,
+		By:     user.Email,
+		Note:   input.Note,
+		At:     primitive.NewDateTimeFromTime(utils.Now()),
+	}
+
+	if status == models.RentalRequestRejected {
+		if err := h.transition(ctx, request, change); err != nil {
+			return transitionError(err)
+		}
+		h.notifier.NotifyID(ctx, request.ApplicantID, notify.RentalRequestDecided(request, property, input.Note))
+		return c.JSON(request)
+	}
+
+	if !request.Status.CanTransitionTo(status) {
+		return transitionError(repository.ErrConflict)
+	}
+	if property.IsRented {
+		return problem.Conflict("Property is already rented")
+	}
+
+	// Work out the lease terms
+	today := utils.Now().Truncate(24 * time.Hour)
+	start := today
+	if request.MoveInDate != 0 && request.MoveInDate.Time().After(today) {
+		start = request.MoveInDate.Time()
+	}
+	if input.StartDate != "" {
+		start, _ = parseDate(input.StartDate)
+		// Invoices for the periods already started would be issued right away
+		if start.Before(today) {
+			return invalidField("start_date", "must not be in the past")
+		}
+	}
+	end := start.AddDate(1, 0, 0)
+	if input.EndDate != "" {
+		end, _ = parseDate(input.EndDate)
+	}
+	if !end.After(start) {
+		return invalidField("end_date", "must be after the start date")
+	}
+	rent := property.Price
+	if input.MonthlyRent != nil {
+		rent = *input.MonthlyRent
+	}
+
+	lease := models.Lease{
+		ID:              primitive.NewObjectID(),
+		PropertyID:      property.ID,
+		RentalRequestID: request.ID,
+		OwnerEmail:      property.OwnerEmail,
+		TenantID:        request.ApplicantID,
+		TenantEmail:     request.ApplicantEmail,
+		TenantName:      request.ApplicantName,
+		StartDate:       primitive.NewDateTimeFromTime(start),
+		EndDate:         primitive.NewDateTimeFromTime(end),
+		MonthlyRent:     rent,
+		Deposit:         input.Deposit,
+		Status:          models.LeaseActive,
+		History:         []models.LeaseEvent{{Type: models.LeaseCreated, By: user.Email, At: change.At}},
+		CreatedAt:       change.At,
+		UpdatedAt:       change.At,
+	}
+
+	err = h.transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		if err := h.leases.Create(ctx, &lease); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error { return h.leases.Delete(ctx, lease.ID) })
+
+		// Mark the property as rented, unless another request got it first
+		if err := h.properties.MarkRented(ctx, property.ID, request.ApplicantEmail); errors.Is(err, repository.ErrConflict) {
+			return errAlreadyRented
+		} else if err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error { return h.properties.MarkAvailable(ctx, property.ID) })
+
+		// Add to the applicant's rented properties
+		if err := h.users.AddRentedProperty(ctx, request.ApplicantID, property.ID); err != nil {
+			return err
+		}
+		tx.OnRollback(func(ctx context.Context) error {
+			return h.users.RemoveRentedProperty(ctx, request.ApplicantID, property.ID)
+		})
+
+		// Last, so nothing has to undo a recorded decision
+		return h.requests.Transition(ctx, request.ID, models.RentalRequestPending, change)
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return problem.Conflict("Property already has an active lease")
+	}
+	if errors.Is(err, errAlreadyRented) {
+		return problem.Conflict("Property is already rented")
+	}
+	if err != nil {
+		return transitionError(err)
+	}
+	recordStatusChange(request, change)
+	h.webhooks.Publish(ctx, models.EventRentalRequestAccepted, webhook.NewRentalRequest(request))
+	h.notifier.NotifyID(ctx, request.ApplicantID, notify.RentalRequestDecided(request, property, input.Note))
+
+	h.rejectOtherRequests(ctx, request, property, user.Email)
+
+	// Issue the deposit and first rent invoices now rather than on the next
+	// run of the invoicing job, which will pick up anything that fails here
+	if _, err := ledger.Generate(ctx, h.invoices, &lease, utils.Now()); err != nil {
+		log.Println("Failed to issue invoices for lease", lease.ID.Hex(), err)
+	}
+
+	return c.JSON(request)
+}
+
+// transition moves request to change.Status, updates the copy in memory and
+// publishes the change on success
+func (h *RentalRequestHandler) transition(ctx context.Context, request *models.RentalRequest, change models.StatusChange) error {
+	if !request.Status.CanTransitionTo(change.Status) {
+		return repository.ErrConflict
+	}
+	if err := h.requests.Transition(ctx, request.ID, request.Status, change); err != nil {
+		return err
+	}
+	recordStatusChange(request, change)
+	h.webhooks.Publish(ctx, models.RentalRequestEvent(change.Status), webhook.NewRentalRequest(request))
+	return nil
+}
+
+// recordStatusChange applies a stored status change to the copy in memory
+func recordStatusChange(request *models.RentalRequest, change models.StatusChange) {
+	request.Status = change.Status
+	request.UpdatedAt = change.At
+	request.History = append(request.History, change)
+}
+
+// rejectOtherRequests rejects the remaining pending requests for a property
+// that has just been rented, publishes the changes and notifies their
+// applicants. Failures are only logged: the property no longer takes
+// requests and whatever is left will expire.
+func (h *RentalRequestHandler) rejectOtherRequests(ctx context.Context, accepted *models.RentalRequest, property *models.Property, by string) {
+	pending, err := h.requests.List(ctx, repository.RentalRequestFilter{
+		PropertyID: accepted.PropertyID,
+		Status:     models.RentalRequestPending,
+	}, repository.Page{})
+	if err != nil {
+		log.Println("Failed to list pending rental requests:", err)
+		return
+	}
+
+	change := models.StatusChange{
+		Status: models.RentalRequestRejected,
+		By:     by,
+		Note:   "The property has been rented to another applicant",
+		At:     primitive.NewDateTimeFromTime(utils.Now()),
+	}
+	for _, request := range pending {
+		err := h.requests.Transition(ctx, request.ID, models.RentalRequestPending, change)
+		if err != nil {
+			if !errors.Is(err, repository.ErrConflict) {
+				log.Println("Failed to reject rental request", request.ID.Hex(), err)
+			}
+			continue
+		}
+		recordStatusChange(&request, change)
+		h.webhooks.Publish(ctx, models.EventRentalRequestRejected, webhook.NewRentalRequest(&request))
+		h.notifier.NotifyID(ctx, request.ApplicantID, notify.RentalRequestDecided(&request, property, change.Note))
+	}
+}
+
+// transitionError maps a failed status change to a response
+func transitionError(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("Only pending rental requests can be changed")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Rental request not found")
+	}
+	return problem.Internal("Failed to update rental request", err)
+}
+
+// parseDate accepts a calendar date (2006-01-02) or a full RFC 3339 timestamp
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
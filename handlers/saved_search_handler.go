@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/savedsearch"
+	"dwello-api/utils"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SavedSearchHandler serves the /api/saved-searches and /api/alerts routes
+type SavedSearchHandler struct {
+	searches repository.SavedSearchRepository
+	alerts   repository.AlertRepository
+}
+
+func NewSavedSearchHandler(searches repository.SavedSearchRepository, alerts repository.AlertRepository) *SavedSearchHandler {
+	return &SavedSearchHandler{searches: searches, alerts: alerts}
+}
+
+type savedSearchRequest struct {
+	Name        string               `json:"name" validate:"required,max=100"`
+	Filters     models.SearchFilters `json:"filters"`
+	EmailAlerts bool                 `json:"email_alerts"`
+}
+
+func (r *savedSearchRequest) normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+}
+
+// CreateSavedSearch godoc
+// @Summary Save a search
+// @Description Save property search filters to be alerted of new listings matching them. Only listings created from now on are matched.
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search body models.SavedSearchInputSwagger true "Saved search"
+// @Success 201 {object} models.SavedSearchSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *fiber.Ctx) error {
+	input, err := bind[savedSearchRequest](c)
+	if err != nil {
+		return err
+	}
+	if box := input.Filters.Box; box != nil && !box.Valid() {
+		return invalidField("filters.bbox", "corners are out of range or out of order")
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	saved, err := h.searches.Count(ctx, repository.SavedSearchFilter{UserID: user.ID})
+	if err != nil {
+		return problem.Internal("Failed to save search", err)
+	}
+	if saved >= savedsearch.MaxPerUser {
+		return problem.Conflict(fmt.Sprintf("You can save at most %d searches", savedsearch.MaxPerUser))
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	search := models.SavedSearch{
+		ID:           primitive.NewObjectID(),
+		UserID:       user.ID,
+		UserEmail:    user.Email,
+		Name:         input.Name,
+		Filters:      input.Filters,
+		EmailAlerts:  input.EmailAlerts,
+		CheckedUntil: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := h.searches.Create(ctx, &search); err != nil {
+		return problem.Internal("Failed to save search", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(search)
+}
+
+// ListSavedSearches godoc
+// @Summary List saved searches
+// @Description List the searches the authenticated user has saved, newest first
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of saved searches"
+// @Success 200 {object} models.PageSwagger[models.SavedSearchSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/saved-searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c *fiber.Ctx) error {
+	filter := repository.SavedSearchFilter{UserID: auth.CurrentUser(c).ID}
+	return listPage(c, h.searches, filter, func(s *models.SavedSearch) primitive.ObjectID { return s.ID }, "Failed to fetch saved searches")
+}
+
+// GetSavedSearch godoc
+// @Summary Get a saved search
+// @Description Get one of the authenticated user's saved searches
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} models.SavedSearchSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/saved-searches/{id} [get]
+func (h *SavedSearchHandler) GetSavedSearch(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	search, err := h.findSavedSearch(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(search)
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete a saved search
+// @Description Delete one of the authenticated user's saved searches. Alerts it already sent are kept.
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	search, err := h.findSavedSearch(ctx, c)
+	if err != nil {
+		return err
+	}
+	if err := h.searches.Delete(ctx, search.ID); err != nil {
+		return problem.Internal("Failed to delete saved search", err)
+	}
+	return c.JSON(fiber.Map{"message": "Saved search deleted"})
+}
+
+// ListAlerts godoc
+// @Summary List alerts
+// @Description List the authenticated user's alerts of new listings matching their saved searches, newest first
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread alerts"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of alerts"
+// @Success 200 {object} models.PageSwagger[models.AlertSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/alerts [get]
+func (h *SavedSearchHandler) ListAlerts(c *fiber.Ctx) error {
+	filter := repository.AlertFilter{UserID: auth.CurrentUser(c).ID, Unread: c.QueryBool("unread")}
+	return listPage(c, h.alerts, filter, func(a *models.Alert) primitive.ObjectID { return a.ID }, "Failed to fetch alerts")
+}
+
+// MarkAlertRead godoc
+// @Summary Mark an alert as read
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Success 200 {object} models.AlertSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/alerts/{id}/read [post]
+func (h *SavedSearchHandler) MarkAlertRead(c *fiber.Ctx) error {
+	alertID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid alert ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	// Alerts of other users are reported as missing
+	alert, err := h.alerts.FindByID(ctx, alertID)
+	if err != nil {
+		return findError(err, "Alert")
+	}
+	if !policy.CanViewAlert(auth.CurrentUser(c), alert) {
+		return problem.NotFound("Alert not found")
+	}
+
+	if err := h.alerts.MarkRead(ctx, alert.ID, utils.Now()); err != nil {
+		return problem.Internal("Failed to mark alert as read", err)
+	}
+	if alert, err = h.alerts.FindByID(ctx, alert.ID); err != nil {
+		return problem.Internal("Failed to fetch alert", err)
+	}
+	return c.JSON(alert)
+}
+
+// MarkAllAlertsRead godoc
+// @Summary Mark every alert as read
+// @Tags Saved Searches
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/alerts/read-all [post]
+func (h *SavedSearchHandler) MarkAllAlertsRead(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	marked, err := h.alerts.MarkAllRead(ctx, auth.CurrentUser(c).ID, utils.Now())
+	if err != nil {
+		return problem.Internal("Failed to mark alerts as read", err)
+	}
+	return c.JSON(fiber.Map{"marked": marked})
+}
+
+// findSavedSearch loads the saved search in the id path parameter
+func (h *SavedSearchHandler) findSavedSearch(ctx context.Context, c *fiber.Ctx) (*models.SavedSearch, error) {
+	searchID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid saved search ID")
+	}
+
+	// Searches saved by other users are reported as missing
+	search, err := h.searches.FindByID(ctx, searchID)
+	if err != nil {
+		return nil, findError(err, "Saved search")
+	}
+	if !policy.CanManageSavedSearch(auth.CurrentUser(c), search) {
+		return nil, problem.NotFound("Saved search not found")
+	}
+	return search, nil
+}
@@ -1,160 +1,199 @@
 package handlers
 
 import (
-	"dwello-api/db"
+	"dwello-api/auth"
 	"dwello-api/models"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
 	"dwello-api/utils"
-	"fmt"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// RegisterUser registers a new user or logs in if the user already exists
-// @Summary Register or Login User
-// @Description Register a new user or return existing user if already registered
+// UserHandler serves the /api/users routes
+type UserHandler struct {
+	users      repository.UserRepository
+	properties repository.PropertyRepository
+}
+
+func NewUserHandler(users repository.UserRepository, properties repository.PropertyRepository) *UserHandler {
+	return &UserHandler{users: users, properties: properties}
+}
+
+// GetCurrentUser returns the authenticated user
+// @Summary Get Current User
+// @Description Get the user document of the authenticated user
 // @Tags Users
-// @Accept json
 // @Produce json
-// @Param user body models.UserSwagger true "User JSON"
+// @Security BearerAuth
 // @Success 200 {object} models.UserSwagger
-// @Success 201 {object} models.UserSwagger
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/users/register [post]
-func RegisterUser(c *fiber.Ctx) error {
-	fmt.Println("RegisterUser called")
-	var user models.User
-	if err := c.BodyParser(&user); err != nil {
-		// return what is missing in the body
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body", "details": err.Error()})
-	}
+// @Failure 401 {object} problem.Problem
+// @Router /api/users/me [get]
+func (h *UserHandler) GetCurrentUser(c *fiber.Ctx) error {
+	return c.JSON(auth.CurrentUser(c))
+}
 
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
+// roleRequest is the body of the endpoints that change the role of a user
+type roleRequest struct {
+	Role models.Role `json:"role" validate:"required,oneof=tenant owner agent admin"`
+}
 
-	collection := db.UserCollection()
+// UpdateCurrentUserRole lets a user switch between the tenant and owner roles
+// @Summary Update Own Role
+// @Description Switch the authenticated user's role between tenant and owner. Agent and admin roles are granted by an admin.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role body map[string]string true "Role JSON"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/users/me/role [put]
+func (h *UserHandler) UpdateCurrentUserRole(c *fiber.Ctx) error {
+	payload, err := bind[roleRequest](c)
+	if err != nil {
+		return err
+	}
+	if !policy.IsSelfAssignable(payload.Role) {
+		return invalidField("role", "must be tenant or owner")
+	}
 
-	// Check if user already exists
-	var existing models.User
-	err := collection.FindOne(ctx, bson.M{"email": user.Email}).Decode(&existing)
-	if err == nil {
-		return c.Status(fiber.StatusOK).JSON(existing) // User already exists, return it
+	user := auth.CurrentUser(c)
+	if current := policy.RoleOf(user); !policy.IsSelfAssignable(current) {
+		return problem.Forbidden("Your role can only be changed by an admin")
 	}
 
-	// New user
-	user.ID = primitive.NewObjectID()
-	user.PostedProperties = []primitive.ObjectID{}
-	user.LikedProperties = []primitive.ObjectID{}
-	user.CreatedAt = primitive.NewDateTimeFromTime(utils.Now())
-	user.UpdatedAt = user.CreatedAt
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
 
-	_, err = collection.InsertOne(ctx, user)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register user"})
+	if err := h.users.SetRole(ctx, user.Email, payload.Role); err != nil {
+		return problem.Internal("Update failed", err)
 	}
+	return c.JSON(fiber.Map{"message": "Role updated"})
+}
 
-	return c.Status(fiber.StatusCreated).JSON(user)
+// isSelf reports whether the email in the path belongs to the authenticated user
+func isSelf(c *fiber.Ctx, email string) bool {
+	return auth.CurrentUser(c).Email == email
 }
 
 // GetUserByEmail fetches a user by their email
 // @Summary Get User by Email
-// @Description Get a user document based on email address
+// @Description Get a user by email address. The user themselves and admins get the whole document; others only get the public profile: name, role, profile picture and posted properties.
 // @Tags Users
 // @Produce json
+// @Security BearerAuth
 // @Param email path string true "User Email"
 // @Success 200 {object} models.UserSwagger
-// @Failure 404 {object} map[string]string
+// @Failure 404 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
 // @Router /api/users/{email} [get]
-func GetUserByEmail(c *fiber.Ctx) error {
+func (h *UserHandler) GetUserByEmail(c *fiber.Ctx) error {
 	email := c.Params("email")
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	var user models.User
-	err := db.UserCollection().FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	user, err := h.users.FindByEmail(ctx, email)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		return findError(err, "User")
+	}
+	if !isSelf(c, email) && !policy.CanManageUsers(auth.CurrentUser(c)) {
+		return c.JSON(user.Public())
 	}
 	return c.JSON(user)
 }
 
+type locationRequest struct {
+	Location    string           `json:"location" validate:"max=100"`
+	Coordinates *models.GeoPoint `json:"coordinates"`
+}
+
+func (r *locationRequest) normalize() {
+	r.Location = strings.TrimSpace(r.Location)
+}
+
 // UpdateUserLocation updates the current location of a user
 // @Summary Update User Location
-// @Description Update the location field of a user
+// @Description Update the location of a user. Coordinates are optional and cleared when omitted.
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param email path string true "User Email"
-// @Param location body map[string]string true "Location JSON"
+// @Param location body models.LocationUpdateSwagger true "Location JSON"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
 // @Router /api/users/{email}/location [put]
-func UpdateUserLocation(c *fiber.Ctx) error {
+func (h *UserHandler) UpdateUserLocation(c *fiber.Ctx) error {
 	email := c.Params("email")
-	var payload struct {
-		Location string `json:"location"`
+	if !isSelf(c, email) {
+		return problem.Forbidden("You can only access your own account")
 	}
-	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+	payload, err := bind[locationRequest](c)
+	if err != nil {
+		return err
+	}
+	if payload.Coordinates != nil && !payload.Coordinates.Valid() {
+		return invalidField("coordinates", invalidCoordinates)
 	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	_, err := db.UserCollection().UpdateOne(ctx,
-		bson.M{"email": email},
-		bson.M{
-			"$set": bson.M{
-				"location":   payload.Location,
-				"updated_at": primitive.NewDateTimeFromTime(utils.Now()),
-			},
-		},
-	)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Update failed"})
+	if err := h.users.UpdateLocation(ctx, email, payload.Location, payload.Coordinates); err != nil {
+		return problem.Internal("Update failed", err)
 	}
 	return c.JSON(fiber.Map{"message": "Location updated"})
 }
 
+// preferredLocationsRequest replaces the preferred locations, an empty list
+// clears them
+type preferredLocationsRequest struct {
+	PreferredLocations []string `json:"preferred_locations" validate:"required,max=20,unique,dive,required,max=100"`
+}
+
+func (r *preferredLocationsRequest) normalize() {
+	r.PreferredLocations = trimAll(r.PreferredLocations)
+}
+
 // UpdatePreferredLocations updates the user's preferred locations
 // @Summary Update Preferred Locations
 // @Description Update the preferred_locations field of a user (can be multiple)
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param email path string true "User Email"
 // @Param preferred_locations body map[string][]string true "Preferred Locations JSON"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
 // @Router /api/users/{email}/preferred-locations [put]
-func UpdatePreferredLocations(c *fiber.Ctx) error {
+func (h *UserHandler) UpdatePreferredLocations(c *fiber.Ctx) error {
 	email := c.Params("email")
-	var payload struct {
-		PreferredLocations []string `json:"preferred_locations"`
+	if !isSelf(c, email) {
+		return problem.Forbidden("You can only access your own account")
 	}
-	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid body"})
+	payload, err := bind[preferredLocationsRequest](c)
+	if err != nil {
+		return err
 	}
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
-	_, err := db.UserCollection().UpdateOne(ctx,
-		bson.M{"email": email},
-		bson.M{
-			"$set": bson.M{
-				"preferred_locations": payload.PreferredLocations,
-				"updated_at":          primitive.NewDateTimeFromTime(utils.Now()),
-			},
-		},
-	)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Update failed"})
+	if err := h.users.UpdatePreferredLocations(ctx, email, payload.PreferredLocations); err != nil {
+		return problem.Internal("Update failed", err)
 	}
 	return c.JSON(fiber.Map{"message": "Preferred locations updated"})
 }
@@ -164,37 +203,27 @@ func UpdatePreferredLocations(c *fiber.Ctx) error {
 // @Description Get a list of properties the user has liked
 // @Tags Users
 // @Produce json
+// @Security BearerAuth
 // @Param email path string true "User Email"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param sort query string false "Sort by" Enums(created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of properties"
+// @Success 200 {object} models.PageSwagger[models.PropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
 // @Router /api/users/{email}/liked-properties [get]
-func GetLikedProperties(c *fiber.Ctx) error {
+func (h *UserHandler) GetLikedProperties(c *fiber.Ctx) error {
 	email := c.Params("email")
-
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
-
-	// Get user
-	var user models.User
-	err := db.UserCollection().FindOne(ctx, bson.M{"email": email}).Decode(&user)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
-	}
-
-	// Get properties by IDs
-	cursor, err := db.PropertyCollection().Find(ctx, bson.M{
-		"_id": bson.M{"$in": user.LikedProperties},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch properties"})
+	if !isSelf(c, email) {
+		return problem.Forbidden("You can only access your own account")
 	}
 
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to parse properties"})
-	}
-	return c.JSON(properties)
+	return listPropertiesByID(c, h.properties, auth.CurrentUser(c).LikedProperties, "Failed to fetch properties")
 }
 
 // GetPostedProperties retrieves the properties posted by a user
@@ -202,194 +231,57 @@ func GetLikedProperties(c *fiber.Ctx) error {
 // @Description Get a list of properties the user has posted
 // @Tags Users
 // @Produce json
+// @Security BearerAuth
 // @Param email path string true "User Email"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param sort query string false "Sort by" Enums(created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of properties"
+// @Success 200 {object} models.PageSwagger[models.PropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
 // @Router /api/users/{email}/posted-properties [get]
-func GetPostedProperties(c *fiber.Ctx) error {
+func (h *UserHandler) GetPostedProperties(c *fiber.Ctx) error {
 	email := c.Params("email")
 
 	ctx, cancel := utils.DatabaseContext()
 	defer cancel()
 
 	// Get user
-	var user models.User
-	err := db.UserCollection().FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	user, err := h.users.FindByEmail(ctx, email)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		return findError(err, "User")
 	}
 
-	// Get properties by IDs
-	cursor, err := db.PropertyCollection().Find(ctx, bson.M{
-		"_id": bson.M{"$in": user.PostedProperties},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch properties"})
-	}
-
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to parse properties"})
-	}
-	return c.JSON(properties)
-}
-
-// HandleRentalRequest handles rental requests from users
-// @Summary Handle Rental Request
-// @Description Accept or reject a rental request for a property
-// @Tags Users
-// @Accept json
-// @Produce json
-// @Param id path string true "Property ID"
-// @Param renter query string true "Renter Email"
-// @Param action query string true "Action (accept/reject)"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/users/rental-request/{id} [post]
-func HandleRentalRequest(c *fiber.Ctx) error {
-	propertyIDParam := c.Params("id")
-	renterIDParam := c.Query("renter_id")
-	action := c.Query("action") // "accept" or "reject"
-
-	if propertyIDParam == "" || renterIDParam == "" || action == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Property ID, renter ID, and action are required"})
-	}
-
-	propertyID, err := primitive.ObjectIDFromHex(propertyIDParam)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid property ID"})
-	}
-
-	renterID, err := primitive.ObjectIDFromHex(renterIDParam)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid renter ID"})
-	}
-
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
-
-	// Always remove the request from both sides
-	_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{
-		"$pull": bson.M{"rental_requests": renterID},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update property"})
-	}
-
-	_, err = db.UserCollection().UpdateOne(ctx, bson.M{"_id": renterID}, bson.M{
-		"$pull": bson.M{"rental_requests": propertyID},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user"})
-	}
-
-	if action == "accept" {
-		// Mark the property as rented
-		_, err = db.PropertyCollection().UpdateOne(ctx, bson.M{"_id": propertyID}, bson.M{
-			"$set": bson.M{
-				"is_rented":    true,
-				"rented_by_id": renterID,
-			},
-		})
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark property as rented"})
-		}
-
-		// Add to user's rented properties
-		_, err = db.UserCollection().UpdateOne(ctx, bson.M{"_id": renterID}, bson.M{
-			"$addToSet": bson.M{"rented_properties": propertyID},
-		})
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user rented properties"})
-		}
-
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Rental request accepted"})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Rental request rejected"})
-}
-
-// GetRentalRequestsForUserProperties retrieves rental requests for properties owned by a user
-// @Summary Get Rental Requests for User Properties
-// @Description Get rental requests for properties owned by a user
-// @Tags Users
-// @Produce json
-// @Param email query string true "User Email"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/users/rental-requests [get]
-func GetRentalRequestsForUserProperties(c *fiber.Ctx) error {
-	userEmail := c.Query("email")
-	if userEmail == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
-	}
-
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{
-			"owner_email":     userEmail,
-			"rental_requests": bson.M{"$ne": bson.A{}},
-		}}},
-		{{Key: "$lookup", Value: bson.M{
-			"from":         "users",
-			"localField":   "rental_requests",
-			"foreignField": "_id",
-			"as":           "requesting_users",
-		}}},
-	}
-
-	cursor, err := db.PropertyCollection().Aggregate(ctx, pipeline)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch rental requests"})
-	}
-
-	var properties []bson.M
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode rental requests"})
-	}
-
-	return c.JSON(properties)
+	return listPropertiesByID(c, h.properties, user.PostedProperties, "Failed to fetch properties")
 }
 
 // GetRentedPropertiesByUser retrieves properties rented by a user
 // @Summary Get Rented Properties by User
-// @Description Get properties rented by a user
+// @Description Get properties rented by the authenticated user
 // @Tags Users
 // @Produce json
-// @Param email query string true "User Email"
-// @Success 200 {array} models.PropertySwagger
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/users/rented-properties [get]
-func GetRentedPropertiesByUser(c *fiber.Ctx) error {
-	userID := c.Query("user_id")
-	if userID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID required"})
-	}
-
-	objectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
-	}
-
-	ctx, cancel := utils.DatabaseContext()
-	defer cancel()
-
-	cursor, err := db.PropertyCollection().Find(ctx, bson.M{"rented_by_id": objectID})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch rented properties"})
-	}
-
-	var properties []models.Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode properties"})
+// @Security BearerAuth
+// @Param email path string true "User Email"
+// @Param sort query string false "Sort by" Enums(created_at, price, likes)
+// @Param order query string false "Sort order, ascending by default for price and descending otherwise" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of properties"
+// @Success 200 {object} models.PageSwagger[models.PropertySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/users/{email}/rented-properties [get]
+func (h *UserHandler) GetRentedPropertiesByUser(c *fiber.Ctx) error {
+	if !isSelf(c, c.Params("email")) {
+		return problem.Forbidden("You can only access your own account")
 	}
 
-	return c.JSON(properties)
+	search := repository.PropertySearch{RentedBy: auth.CurrentUser(c).Email}
+	return listProperties(c, h.properties, search, "Failed to fetch rented properties")
 }
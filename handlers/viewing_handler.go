@@ -0,0 +1,749 @@
+package handlers
+
+import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/ical"
+	"dwello-api/models"
+	"dwello-api/notify"
+	"dwello-api/policy"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Shortest and longest viewing slot
+	minSlotLength = 15 * time.Minute
+	maxSlotLength = 4 * time.Hour
+	// How far ahead slots can be published
+	maxSlotAdvance = 90 * 24 * time.Hour
+	// Most upcoming slots a property can have
+	maxUpcomingSlots = 100
+
+	// How far back calendar feeds go
+	calendarFeedHistory = 30 * 24 * time.Hour
+)
+
+// ViewingHandler serves the viewing slot routes of properties, the
+// /api/viewings routes and the calendar feeds
+type ViewingHandler struct {
+	users      repository.UserRepository
+	properties repository.PropertyRepository
+	slots      repository.ViewingSlotRepository
+	viewings   repository.ViewingRepository
+	notifier   *notify.Dispatcher
+}
+
+func NewViewingHandler(users repository.UserRepository, properties repository.PropertyRepository, slots repository.ViewingSlotRepository, viewings repository.ViewingRepository, notifier *notify.Dispatcher) *ViewingHandler {
+	return &ViewingHandler{users: users, properties: properties, slots: slots, viewings: viewings, notifier: notifier}
+}
+
+// slotRequest is the body of CreateViewingSlot
+type slotRequest struct {
+	Start string `json:"start" validate:"required,rfc3339"`
+	End   string `json:"end" validate:"required,rfc3339"`
+}
+
+// CreateViewingSlot godoc
+// @Summary Publish a viewing slot
+// @Description Publish a time the property can be visited. Slots last from 15 minutes to 4 hours, start at most 90 days ahead and cannot overlap the owner's other slots. Each slot takes a single viewing.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param slot body models.ViewingSlotInputSwagger true "Slot"
+// @Success 201 {object} models.ViewingSlotSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/viewing-slots [post]
+func (h *ViewingHandler) CreateViewingSlot(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+
+	input, err := bind[slotRequest](c)
+	if err != nil {
+		return err
+	}
+	start, _ := time.Parse(time.RFC3339, input.Start)
+	end, _ := time.Parse(time.RFC3339, input.End)
+	now := utils.Now()
+	if !start.After(now) {
+		return invalidField("start", "must be in the future")
+	}
+	if start.After(now.Add(maxSlotAdvance)) {
+		return invalidField("start", fmt.Sprintf("must be at most %d days ahead", int(maxSlotAdvance.Hours()/24)))
+	}
+	if length := end.Sub(start); length < minSlotLength || length > maxSlotLength {
+		return invalidField("end", fmt.Sprintf("must be from %d minutes to %d hours after the start", int(minSlotLength.Minutes()), int(maxSlotLength.Hours())))
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+	if !policy.CanManageViewingSlots(auth.CurrentUser(c), property) {
+		return problem.Forbidden("You cannot manage viewings of this property")
+	}
+
+	upcoming, err := h.slots.Count(ctx, repository.ViewingSlotFilter{PropertyID: property.ID, From: now})
+	if err != nil {
+		return problem.Internal("Failed to create viewing slot", err)
+	}
+	if upcoming >= maxUpcomingSlots {
+		return problem.Conflict(fmt.Sprintf("A property can have at most %d upcoming slots", maxUpcomingSlots))
+	}
+
+	// The owner cannot be in two places at once
+	overlapping, err := h.slots.Count(ctx, repository.ViewingSlotFilter{OwnerEmail: property.OwnerEmail, From: start, To: end})
+	if err != nil {
+		return problem.Internal("Failed to create viewing slot", err)
+	}
+	if overlapping > 0 {
+		return problem.Conflict("The slot overlaps another viewing slot of the owner")
+	}
+
+	slot := models.ViewingSlot{
+		ID:         primitive.NewObjectID(),
+		PropertyID: property.ID,
+		OwnerEmail: property.OwnerEmail,
+		Start:      primitive.NewDateTimeFromTime(start),
+		End:        primitive.NewDateTimeFromTime(end),
+		CreatedAt:  primitive.NewDateTimeFromTime(now),
+	}
+	if err := h.slots.Create(ctx, &slot); err != nil {
+		return problem.Internal("Failed to create viewing slot", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(slot)
+}
+
+// ListViewingSlots godoc
+// @Summary List viewing slots
+// @Description List the upcoming viewing slots of a property, earliest first. Only the owner sees booked slots; everyone else gets the available ones.
+// @Tags Viewings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param available query bool false "Only list the slots nobody has booked"
+// @Success 200 {object} models.PageSwagger[models.ViewingSlotSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/viewing-slots [get]
+func (h *ViewingHandler) ListViewingSlots(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+
+	filter := repository.ViewingSlotFilter{
+		PropertyID: property.ID,
+		From:       utils.Now(),
+		Available:  c.QueryBool("available") || !policy.CanManageViewingSlots(auth.CurrentUser(c), property),
+	}
+	slots, err := h.slots.List(ctx, filter, repository.Page{})
+	if err != nil {
+		return problem.Internal("Failed to fetch viewing slots", err)
+	}
+	return c.JSON(models.Page[models.ViewingSlot]{Items: slots})
+}
+
+// DeleteViewingSlot godoc
+// @Summary Remove a viewing slot
+// @Description Remove a viewing slot nobody has booked. Cancel the viewing booked in a slot before removing it.
+// @Tags Viewings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Property ID"
+// @Param slotId path string true "Slot ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/properties/{id}/viewing-slots/{slotId} [delete]
+func (h *ViewingHandler) DeleteViewingSlot(c *fiber.Ctx) error {
+	propertyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return problem.BadRequest("Invalid property ID")
+	}
+	slotID, err := primitive.ObjectIDFromHex(c.Params("slotId"))
+	if err != nil {
+		return problem.BadRequest("Invalid slot ID")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	property, err := h.properties.FindByID(ctx, propertyID)
+	if err != nil {
+		return findError(err, "Property")
+	}
+	if !policy.CanManageViewingSlots(auth.CurrentUser(c), property) {
+		return problem.Forbidden("You cannot manage viewings of this property")
+	}
+	slot, err := h.slots.FindByID(ctx, slotID)
+	if err != nil {
+		return findError(err, "Viewing slot")
+	}
+	if slot.PropertyID != property.ID {
+		return problem.NotFound("Viewing slot not found")
+	}
+
+	err = h.slots.Delete(ctx, slot.ID)
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("Cancel the viewing booked in this slot first")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Viewing slot not found")
+	}
+	if err != nil {
+		return problem.Internal("Failed to delete viewing slot", err)
+	}
+	return c.JSON(fiber.Map{"message": "Viewing slot deleted"})
+}
+
+type bookingRequest struct {
+	SlotID string `json:"slot_id" validate:"required,objectid"`
+	Note   string `json:"note" validate:"max=500"`
+}
+
+func (r *bookingRequest) normalize() {
+	r.Note = strings.TrimSpace(r.Note)
+}
+
+// BookViewing godoc
+// @Summary Book a viewing
+// @Description Book a viewing in an available slot. Visitors can have one scheduled viewing per property and none overlapping. The owner is notified.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param viewing body models.ViewingInputSwagger true "Viewing"
+// @Success 201 {object} models.ViewingSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/viewings [post]
+func (h *ViewingHandler) BookViewing(c *fiber.Ctx) error {
+	input, err := bind[bookingRequest](c)
+	if err != nil {
+		return err
+	}
+	slotID, _ := primitive.ObjectIDFromHex(input.SlotID)
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	slot, property, err := h.findBookableSlot(ctx, slotID)
+	if err != nil {
+		return err
+	}
+	if !policy.CanBookViewing(user, property) {
+		return problem.Forbidden("You cannot book a viewing of this property")
+	}
+
+	scheduled, err := h.viewings.Count(ctx, repository.ViewingFilter{
+		VisitorID:  user.ID,
+		PropertyID: property.ID,
+		Status:     models.ViewingScheduled,
+		From:       utils.Now(),
+	})
+	if err != nil {
+		return problem.Internal("Failed to book viewing", err)
+	}
+	if scheduled > 0 {
+		return problem.Conflict("You already have a viewing of this property scheduled, reschedule it instead")
+	}
+	if err := h.checkVisitorFree(ctx, user.ID, slot, primitive.NilObjectID); err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	viewing := models.Viewing{
+		ID:               primitive.NewObjectID(),
+		PropertyID:       property.ID,
+		PropertyTitle:    property.Title,
+		PropertyLocation: property.Location,
+		OwnerEmail:       property.OwnerEmail,
+		VisitorID:        user.ID,
+		VisitorEmail:     user.Email,
+		VisitorName:      user.Name,
+		SlotID:           slot.ID,
+		Start:            slot.Start,
+		End:              slot.End,
+		Note:             input.Note,
+		Status:           models.ViewingScheduled,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	// Claim the slot first so two visitors cannot both book it
+	if err := h.bookSlot(ctx, slot.ID, viewing.ID); err != nil {
+		return err
+	}
+	if err := h.viewings.Create(ctx, &viewing); err != nil {
+		h.releaseSlot(ctx, slot.ID, viewing.ID)
+		return problem.Internal("Failed to book viewing", err)
+	}
+
+	h.notifier.NotifyEmail(ctx, viewing.OwnerEmail, notify.ViewingBooked(&viewing, false))
+
+	return c.Status(fiber.StatusCreated).JSON(viewing)
+}
+
+// ListViewings godoc
+// @Summary List viewings
+// @Description List the viewings the authenticated user has booked (as=visitor) or that are booked at their properties (as=owner), most recently booked first
+// @Tags Viewings
+// @Produce json
+// @Security BearerAuth
+// @Param as query string false "visitor (default) or owner"
+// @Param status query string false "Filter by status" Enums(scheduled, cancelled)
+// @Param property_id query string false "Filter by property"
+// @Param upcoming query bool false "Only list the viewings that have not ended yet"
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of viewings"
+// @Success 200 {object} models.PageSwagger[models.ViewingSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/viewings [get]
+func (h *ViewingHandler) ListViewings(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+
+	var filter repository.ViewingFilter
+	switch c.Query("as", "visitor") {
+	case "visitor":
+		filter.VisitorID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+	default:
+		return problem.Validation("as must be visitor or owner")
+	}
+
+	if status := models.ViewingStatus(c.Query("status")); status != "" {
+		if !status.Valid() {
+			return problem.Validation("Invalid status")
+		}
+		filter.Status = status
+	}
+
+	if propertyIDParam := c.Query("property_id"); propertyIDParam != "" {
+		propertyID, err := primitive.ObjectIDFromHex(propertyIDParam)
+		if err != nil {
+			return problem.BadRequest("Invalid property ID")
+		}
+		filter.PropertyID = propertyID
+	}
+
+	if c.QueryBool("upcoming") {
+		filter.From = utils.Now()
+	}
+
+	return listPage(c, h.viewings, filter, func(v *models.Viewing) primitive.ObjectID { return v.ID }, "Failed to fetch viewings")
+}
+
+// GetViewing godoc
+// @Summary Get a viewing
+// @Description Get a viewing. Visible to the visitor and the property owner.
+// @Tags Viewings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Viewing ID"
+// @Success 200 {object} models.ViewingSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/viewings/{id} [get]
+func (h *ViewingHandler) GetViewing(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	viewing, err := h.findViewing(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(viewing)
+}
+
+type rescheduleRequest struct {
+	SlotID string `json:"slot_id" validate:"required,objectid"`
+}
+
+// RescheduleViewing godoc
+// @Summary Reschedule a viewing
+// @Description Move a scheduled viewing to another available slot of the same property. Only the visitor can reschedule; the owner is notified.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Viewing ID"
+// @Param slot body models.ViewingRescheduleSwagger true "New slot"
+// @Success 200 {object} models.ViewingSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/viewings/{id}/reschedule [post]
+func (h *ViewingHandler) RescheduleViewing(c *fiber.Ctx) error {
+	input, err := bind[rescheduleRequest](c)
+	if err != nil {
+		return err
+	}
+	slotID, _ := primitive.ObjectIDFromHex(input.SlotID)
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	viewing, err := h.findViewing(ctx, c)
+	if err != nil {
+		return err
+	}
+	if !policy.CanRescheduleViewing(user, viewing) {
+		return problem.Forbidden("Only the visitor can reschedule a viewing")
+	}
+	if viewing.Status != models.ViewingScheduled {
+		return problem.Conflict("Only scheduled viewings can be changed")
+	}
+	if slotID == viewing.SlotID {
+		return invalidField("slot_id", "is the slot the viewing is already booked in")
+	}
+
+	slot, _, err := h.findBookableSlot(ctx, slotID)
+	if err != nil {
+		return err
+	}
+	if slot.PropertyID != viewing.PropertyID {
+		return invalidField("slot_id", "must be a slot of the same property")
+	}
+	if err := h.checkVisitorFree(ctx, user.ID, slot, viewing.ID); err != nil {
+		return err
+	}
+
+	if err := h.bookSlot(ctx, slot.ID, viewing.ID); err != nil {
+		return err
+	}
+	previous := viewing.SlotID
+	viewing.SlotID = slot.ID
+	viewing.Start = slot.Start
+	viewing.End = slot.End
+	viewing.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+	if err := h.viewings.Reschedule(ctx, viewing); err != nil {
+		h.releaseSlot(ctx, slot.ID, viewing.ID)
+		return viewingError(err)
+	}
+	h.releaseSlot(ctx, previous, viewing.ID)
+
+	h.notifier.NotifyEmail(ctx, viewing.OwnerEmail, notify.ViewingBooked(viewing, true))
+
+	return c.JSON(viewing)
+}
+
+// cancelViewingRequest is the optional body of CancelViewing
+type cancelViewingRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+func (r *cancelViewingRequest) normalize() {
+	r.Reason = strings.TrimSpace(r.Reason)
+}
+
+// CancelViewing godoc
+// @Summary Cancel a viewing
+// @Description Cancel a scheduled viewing that has not started yet, freeing its slot. Either party can cancel; the other one is notified.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Viewing ID"
+// @Param cancellation body models.ViewingCancelSwagger false "Reason"
+// @Success 200 {object} models.ViewingSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/viewings/{id}/cancel [post]
+func (h *ViewingHandler) CancelViewing(c *fiber.Ctx) error {
+	input, err := bindOptional[cancelViewingRequest](c)
+	if err != nil {
+		return err
+	}
+
+	user := auth.CurrentUser(c)
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	viewing, err := h.findViewing(ctx, c)
+	if err != nil {
+		return err
+	}
+	if !policy.CanCancelViewing(user, viewing) {
+		return problem.Forbidden("You cannot cancel this viewing")
+	}
+	if viewing.Status != models.ViewingScheduled {
+		return problem.Conflict("Only scheduled viewings can be changed")
+	}
+	now := utils.Now()
+	if !viewing.Start.Time().After(now) {
+		return problem.Conflict("Viewings cannot be cancelled once they have started")
+	}
+
+	cancellation := models.ViewingCancellation{
+		By:     user.Email,
+		Reason: input.Reason,
+		At:     primitive.NewDateTimeFromTime(now),
+	}
+	if err := h.viewings.Cancel(ctx, viewing.ID, cancellation); err != nil {
+		return viewingError(err)
+	}
+	viewing.Status = models.ViewingCancelled
+	viewing.Cancellation = &cancellation
+	viewing.UpdatedAt = cancellation.At
+
+	// Let someone else book the slot
+	h.releaseSlot(ctx, viewing.SlotID, viewing.ID)
+
+	if user.ID == viewing.VisitorID {
+		h.notifier.NotifyEmail(ctx, viewing.OwnerEmail, notify.ViewingCancelled(viewing))
+	} else {
+		h.notifier.NotifyID(ctx, viewing.VisitorID, notify.ViewingCancelled(viewing))
+	}
+
+	return c.JSON(viewing)
+}
+
+// CreateCalendarFeeds godoc
+// @Summary Get calendar feed URLs
+// @Description Issue the URLs of the authenticated user's iCalendar feeds: viewings they booked and viewings of their properties. Calendar apps can subscribe to them without an access token. Calling this again revokes the previous URLs.
+// @Tags Viewings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CalendarFeedsSwagger
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/viewings/calendar [post]
+func (h *ViewingHandler) CreateCalendarFeeds(c *fiber.Ctx) error {
+	token, err := auth.GenerateCalendarToken()
+	if err != nil {
+		return problem.Internal("Failed to create calendar feeds", err)
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	if err := h.users.SetCalendarToken(ctx, auth.CurrentUser(c).ID, auth.HashSecret(token)); err != nil {
+		return problem.Internal("Failed to create calendar feeds", err)
+	}
+
+	feed := c.BaseURL() + "/api/calendar/" + token + "/viewings.ics"
+	return c.JSON(fiber.Map{"visitor_url": feed + "?as=visitor", "owner_url": feed + "?as=owner"})
+}
+
+// GetCalendarFeed godoc
+// @Summary Calendar feed of viewings
+// @Description iCalendar feed of the viewings a user booked (as=visitor) or that are booked at their properties (as=owner), from 30 days ago onwards. Cancelled viewings are included with a cancelled status. The token comes from POST /api/viewings/calendar.
+// @Tags Viewings
+// @Produce text/calendar
+// @Param token path string true "Calendar token"
+// @Param as query string false "visitor (default) or owner"
+// @Success 200 {string} string
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/calendar/{token}/viewings.ics [get]
+func (h *ViewingHandler) GetCalendarFeed(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	user, err := h.users.FindByCalendarToken(ctx, auth.HashSecret(c.Params("token")))
+	if err != nil {
+		return findError(err, "Calendar")
+	}
+
+	filter := repository.ViewingFilter{From: utils.Now().Add(-calendarFeedHistory)}
+	asOwner := false
+	switch c.Query("as", "visitor") {
+	case "visitor":
+		filter.VisitorID = user.ID
+	case "owner":
+		filter.OwnerEmail = user.Email
+		asOwner = true
+	default:
+		return problem.Validation("as must be visitor or owner")
+	}
+
+	viewings, err := h.viewings.List(ctx, filter, repository.Page{})
+	if err != nil {
+		return problem.Internal("Failed to fetch viewings", err)
+	}
+
+	calendar := ical.Calendar{Name: "Dwello viewings"}
+	if asOwner {
+		calendar.Name = "Dwello viewings of my properties"
+	}
+	for i := range viewings {
+		calendar.Events = append(calendar.Events, viewingEvent(&viewings[i], asOwner))
+	}
+
+	c.Set(fiber.HeaderContentType, ical.ContentType)
+	return c.Send(calendar.Encode())
+}
+
+// viewingEvent describes a viewing in a calendar feed, as the owner sees it
+// when asOwner is set and as the visitor does otherwise
+func viewingEvent(viewing *models.Viewing, asOwner bool) ical.Event {
+	event := ical.Event{
+		UID:         viewing.ID.Hex() + "@dwello",
+		Start:       viewing.Start.Time(),
+		End:         viewing.End.Time(),
+		Summary:     "Viewing of " + viewing.PropertyTitle,
+		Description: "Hosted by " + viewing.OwnerEmail,
+		Location:    viewing.PropertyLocation,
+		Cancelled:   viewing.Status == models.ViewingCancelled,
+		Modified:    viewing.UpdatedAt.Time(),
+	}
+	if asOwner {
+		event.Summary = fmt.Sprintf("Viewing of %s with %s", viewing.PropertyTitle, viewing.VisitorName)
+		event.Description = "Visitor: " + viewing.VisitorEmail
+	}
+	if viewing.Note != "" {
+		event.Description += "\n" + viewing.Note
+	}
+	return event
+}
+
+// findViewing loads the viewing in the id path parameter
+func (h *ViewingHandler) findViewing(ctx context.Context, c *fiber.Ctx) (*models.Viewing, error) {
+	viewingID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid viewing ID")
+	}
+
+	// Viewings the user may not see are reported as missing
+	viewing, err := h.viewings.FindByID(ctx, viewingID)
+	if err != nil {
+		return nil, findError(err, "Viewing")
+	}
+	if !policy.CanViewViewing(auth.CurrentUser(c), viewing) {
+		return nil, problem.NotFound("Viewing not found")
+	}
+	return viewing, nil
+}
+
+// findBookableSlot loads an upcoming slot nobody has booked and its property
+func (h *ViewingHandler) findBookableSlot(ctx context.Context, slotID primitive.ObjectID) (*models.ViewingSlot, *models.Property, error) {
+	slot, err := h.slots.FindByID(ctx, slotID)
+	if err != nil {
+		return nil, nil, findError(err, "Viewing slot")
+	}
+	if !slot.Start.Time().After(utils.Now()) {
+		return nil, nil, problem.Conflict("The slot has already started")
+	}
+	if slot.Booked() {
+		return nil, nil, problem.Conflict("The slot is already booked")
+	}
+
+	property, err := h.properties.FindByID(ctx, slot.PropertyID)
+	if err != nil {
+		return nil, nil, findError(err, "Property")
+	}
+	return slot, property, nil
+}
+
+// checkVisitorFree makes sure the visitor has no other scheduled viewing
+// overlapping the slot, leaving out the viewing being rescheduled
+func (h *ViewingHandler) checkVisitorFree(ctx context.Context, visitorID primitive.ObjectID, slot *models.ViewingSlot, except primitive.ObjectID) error {
+	overlapping, err := h.viewings.List(ctx, repository.ViewingFilter{
+		VisitorID: visitorID,
+		Status:    models.ViewingScheduled,
+		From:      slot.Start.Time(),
+		To:        slot.End.Time(),
+	}, repository.Page{})
+	if err != nil {
+		return problem.Internal("Failed to check your other viewings", err)
+	}
+	for _, viewing := range overlapping {
+		if viewing.ID != except {
+			return problem.Conflict("You have another viewing at that time")
+		}
+	}
+	return nil
+}
+
+// bookSlot claims the slot for the viewing
+func (h *ViewingHandler) bookSlot(ctx context.Context, slotID, viewingID primitive.ObjectID) error {
+	err := h.slots.Book(ctx, slotID, viewingID)
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("The slot is already booked")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Viewing slot not found")
+	}
+	if err != nil {
+		return problem.Internal("Failed to book viewing slot", err)
+	}
+	return nil
+}
+
+// releaseSlot frees a slot the viewing no longer uses. Failures are only
+// logged: the slot stays booked by a viewing that is not in it.
+func (h *ViewingHandler) releaseSlot(ctx context.Context, slotID, viewingID primitive.ObjectID) {
+	if err := h.slots.Release(ctx, slotID, viewingID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("Failed to release viewing slot", slotID.Hex(), err)
+	}
+}
+
+// viewingError maps a failed viewing change to a response
+func viewingError(err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return problem.Conflict("Only scheduled viewings can be changed")
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.NotFound("Viewing not found")
+	}
+	return problem.Internal("Failed to update viewing", err)
+}
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dwello-api/auth"
+	"dwello-api/models"
+	"dwello-api/problem"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"dwello-api/webhook"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookHandler serves the admin-only /api/webhooks routes
+type WebhookHandler struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+}
+
+func NewWebhookHandler(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks, deliveries: deliveries}
+}
+
+// webhookInput is the body creating or updating a webhook
+type webhookInput struct {
+	URL         string                `json:"url" validate:"required,http_url,max=2048"`
+	Description string                `json:"description" validate:"max=200"`
+	Events      []models.WebhookEvent `json:"events" validate:"min=1"`
+	Active      *bool                 `json:"active"`
+}
+
+func (w *webhookInput) normalize() {
+	w.URL = strings.TrimSpace(w.URL)
+	w.Description = strings.TrimSpace(w.Description)
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to events. Each event is POSTed as {"id", "type", "created_at", "data"} with the X-Dwello-Event, X-Dwello-Delivery and X-Dwello-Signature headers. The signing secret is only returned here. Admin only.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body models.WebhookInputSwagger true "Webhook"
+// @Success 201 {object} models.WebhookSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	input, err := parseWebhookInput(c)
+	if err != nil {
+		return err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return problem.Internal("Failed to create webhook", err)
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	hook := models.Webhook{
+		ID:          primitive.NewObjectID(),
+		URL:         input.URL,
+		Description: input.Description,
+		Events:      input.Events,
+		Active:      input.Active == nil || *input.Active,
+		Secret:      secret,
+		CreatedBy:   auth.CurrentUser(c).Email,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	if err := h.webhooks.Create(ctx, &hook); err != nil {
+		return problem.Internal("Failed to create webhook", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(hook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List every webhook, newest first. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of webhooks"
+// @Success 200 {object} models.PageSwagger[models.WebhookSwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	page, err := parsePage(c, repository.SortCreatedAt)
+	if err != nil {
+		return problem.Validation(err.Error())
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	var filter repository.WebhookFilter
+	webhooks, err := h.webhooks.List(ctx, filter, peek(page))
+	if err != nil {
+		return problem.Internal("Failed to fetch webhooks", err)
+	}
+	response := newPage(webhooks, page, func(w *models.Webhook) (float64, primitive.ObjectID) {
+		return repository.ByCreation(w.ID)
+	})
+	for i := range response.Items {
+		response.Items[i].Secret = ""
+	}
+	if err := countTotal(c, &response, func() (int64, error) { return h.webhooks.Count(ctx, filter) }); err != nil {
+		return problem.Internal("Failed to fetch webhooks", err)
+	}
+	return c.JSON(response)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Description Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.WebhookSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	hook, err := h.findWebhook(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(hook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Change the URL, description, events or active flag of a webhook. Deliveries already queued keep their payload but go to the new URL. Admin only.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param webhook body models.WebhookInputSwagger true "Webhook"
+// @Success 200 {object} models.WebhookSwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	input, err := parseWebhookInput(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	hook, err := h.findWebhook(ctx, c)
+	if err != nil {
+		return err
+	}
+	hook.URL = input.URL
+	hook.Description = input.Description
+	hook.Events = input.Events
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+	hook.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+
+	if err := h.webhooks.Update(ctx, hook); err != nil {
+		return problem.Internal("Failed to update webhook", err)
+	}
+	return c.JSON(hook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook along with its delivery log. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	hook, err := h.findWebhook(ctx, c)
+	if err != nil {
+		return err
+	}
+	if err := h.webhooks.Delete(ctx, hook.ID); err != nil {
+		return problem.Internal("Failed to delete webhook", err)
+	}
+	if _, err := h.deliveries.DeleteByWebhook(ctx, hook.ID); err != nil {
+		return problem.Internal("Failed to delete webhook deliveries", err)
+	}
+	return c.JSON(fiber.Map{"message": "Webhook deleted"})
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook with every attempt, newest first. Failed attempts are retried with exponential backoff, starting after a minute, up to 10 attempts. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Filter by status" Enums(pending, succeeded, failed)
+// @Param order query string false "Sort order, newest first by default" Enums(asc, desc)
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param total query bool false "Include the total number of deliveries"
+// @Success 200 {object} models.PageSwagger[models.WebhookDeliverySwagger]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	filter := repository.WebhookDeliveryFilter{Status: models.WebhookDeliveryStatus(c.Query("status"))}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return problem.Validation("Invalid status")
+	}
+
+	ctx, cancel := utils.DatabaseContext()
+	hook, err := h.findWebhook(ctx, c)
+	cancel()
+	if err != nil {
+		return err
+	}
+	filter.WebhookID = hook.ID
+
+	return listPage(c, h.deliveries, filter, func(d *models.WebhookDelivery) primitive.ObjectID { return d.ID }, "Failed to fetch deliveries")
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDeliverySwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /api/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	delivery, err := h.findDelivery(ctx, c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(delivery)
+}
+
+// RedeliverDelivery godoc
+// @Summary Redeliver a webhook event
+// @Description Queue the payload of a delivery again, right away. The new delivery keeps the event ID and gets a new delivery ID. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} models.WebhookDeliverySwagger
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(c *fiber.Ctx) error {
+	ctx, cancel := utils.DatabaseContext()
+	defer cancel()
+
+	delivery, err := h.findDelivery(ctx, c)
+	if err != nil {
+		return err
+	}
+	if delivery.Status == models.DeliveryPending {
+		return problem.Conflict("Delivery is still pending")
+	}
+
+	redelivery := webhook.Redelivery(delivery, utils.Now())
+	if err := h.deliveries.Create(ctx, &redelivery); err != nil {
+		return problem.Internal("Failed to queue redelivery", err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(redelivery)
+}
+
+// findWebhook loads the webhook in the id path parameter, without its
+// secret
+func (h *WebhookHandler) findWebhook(ctx context.Context, c *fiber.Ctx) (*models.Webhook, error) {
+	webhookID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid webhook ID")
+	}
+
+	hook, err := h.webhooks.FindByID(ctx, webhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, problem.NotFound("Webhook not found")
+	}
+	if err != nil {
+		return nil, problem.Internal("Failed to fetch webhook", err)
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+// findDelivery loads the delivery in the deliveryId path parameter, which
+// must belong to the webhook in the id path parameter
+func (h *WebhookHandler) findDelivery(ctx context.Context, c *fiber.Ctx) (*models.WebhookDelivery, error) {
+	webhookID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid webhook ID")
+	}
+	deliveryID, err := primitive.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return nil, problem.BadRequest("Invalid delivery ID")
+	}
+
+	delivery, err := h.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, findError(err, "Delivery")
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, problem.NotFound("Delivery not found")
+	}
+	return delivery, nil
+}
+
+// parseWebhookInput reads and checks the body creating or updating a
+// webhook
+func parseWebhookInput(c *fiber.Ctx) (*webhookInput, error) {
+	input, err := bind[webhookInput](c)
+	if err != nil {
+		return nil, err
+	}
+	for i, event := range input.Events {
+		if !event.Valid() {
+			return nil, invalidField(fmt.Sprintf("events[%d]", i), "is not a webhook event")
+		}
+	}
+	slices.Sort(input.Events)
+	input.Events = slices.Compact(input.Events)
+	return input, nil
+}
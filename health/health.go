@@ -0,0 +1,89 @@
+// Package health reports whether the API is ready to take traffic, for the
+// liveness and readiness probes of the orchestrator.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Check returns nil when the part of the API it looks at is ready
+type Check func(ctx context.Context) error
+
+// Result is the outcome of the readiness checks, "ok" or the error of each
+type Result struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Checker runs the readiness checks. The checks run concurrently so a slow
+// dependency does not hold up the others.
+type Checker struct {
+	names  []string
+	checks []Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add registers a check under name. Checks must be added before Run is
+// first called.
+func (c *Checker) Add(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+}
+
+// Run runs every check and reports ready when they all pass
+func (c *Checker) Run(ctx context.Context) Result {
+	errs := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	result := Result{Ready: true, Checks: map[string]string{}}
+	for i, name := range c.names {
+		if errs[i] != nil {
+			result.Ready = false
+			result.Checks[name] = errs[i].Error()
+			continue
+		}
+		result.Checks[name] = "ok"
+	}
+	return result
+}
+
+// ErrPending is returned by the check of a Step that has not finished yet
+var ErrPending = errors.New("in progress")
+
+// Step tracks a startup task such as the index build, which the API is not
+// ready before
+type Step struct {
+	mu   sync.Mutex
+	done bool
+	err  error
+}
+
+// Finish records the outcome of the step
+func (s *Step) Finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done, s.err = true, err
+}
+
+// Check returns ErrPending until the step finished and then its error
+func (s *Step) Check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.done {
+		return ErrPending
+	}
+	return s.err
+}
@@ -0,0 +1,110 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can
+// subscribe to.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar feeds
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the longest a content line may be, in bytes, before it
+// has to be folded
+const maxLineLength = 75
+
+// Event is a single entry of a calendar
+type Event struct {
+	// UID identifies the event across updates of the feed
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Cancelled   bool
+	// Modified is when the event last changed
+	Modified time.Time
+}
+
+// Calendar is a feed of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode returns the calendar in iCalendar format
+func (c *Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//Dwello//Dwello API//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escape(c.Name))
+	}
+	for _, event := range c.Events {
+		writeEvent(&buf, &event)
+	}
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func writeEvent(buf *bytes.Buffer, event *Event) {
+	status := "CONFIRMED"
+	if event.Cancelled {
+		status = "CANCELLED"
+	}
+
+	writeLine(buf, "BEGIN:VEVENT")
+	writeLine(buf, "UID:"+escape(event.UID))
+	writeLine(buf, "DTSTAMP:"+formatTime(event.Modified))
+	writeLine(buf, "LAST-MODIFIED:"+formatTime(event.Modified))
+	writeLine(buf, "DTSTART:"+formatTime(event.Start))
+	writeLine(buf, "DTEND:"+formatTime(event.End))
+	writeLine(buf, "SUMMARY:"+escape(event.Summary))
+	if event.Description != "" {
+		writeLine(buf, "DESCRIPTION:"+escape(event.Description))
+	}
+	if event.Location != "" {
+		writeLine(buf, "LOCATION:"+escape(event.Location))
+	}
+	writeLine(buf, "STATUS:"+status)
+	writeLine(buf, "END:VEVENT")
+}
+
+// formatTime formats t as a UTC date-time
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape escapes the characters with a meaning in text values
+func escape(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// writeLine writes a content line, folding it into continuation lines that
+// start with a space when it is too long. Lines are never split inside a
+// UTF-8 sequence.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of continuation lines counts towards the limit
+		limit = maxLineLength - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
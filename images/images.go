@@ -0,0 +1,165 @@
+// Package images checks uploaded pictures and turns them into resized
+// variants kept in a blob store. Variants are decoded and encoded again, so
+// EXIF and any other metadata of the upload is dropped; the EXIF
+// orientation is applied to the pixels first so pictures stay upright.
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"path"
+	"time"
+
+	"dwello-api/blob"
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const jpegQuality = 85
+
+// The upload limits are set from the configuration at startup
+var (
+	// MaxUploadSize is the largest upload accepted, in bytes
+	MaxUploadSize = 10 << 20
+	// MaxPixels bounds the decoded size of an upload, which a small file
+	// can otherwise blow up to
+	MaxPixels = 40_000_000
+)
+
+var (
+	ErrTooLarge      = errors.New("the image is too large")
+	ErrUnsupported   = errors.New("only JPEG and PNG images are supported")
+	ErrTooManyPixels = errors.New("the image has too many pixels")
+	ErrInvalid       = errors.New("the image could not be decoded")
+)
+
+// LimitError explains which limit an upload rejected with ErrTooLarge or
+// ErrTooManyPixels exceeded
+func LimitError(err error) string {
+	if errors.Is(err, ErrTooManyPixels) {
+		return fmt.Sprintf("images must be at most %d megapixels", MaxPixels/1_000_000)
+	}
+	return fmt.Sprintf("images must be at most %d MB", MaxUploadSize>>20)
+}
+
+// Variant is a resized copy of an upload. Images are scaled down to fit
+// within Size by Size pixels, after cropping them to a centered square when
+// Square is set. Smaller images are never scaled up.
+type Variant struct {
+	Name   string
+	Size   int
+	Square bool
+}
+
+var (
+	// PropertyVariants are made of every property picture
+	PropertyVariants = []Variant{
+		{Name: models.ImageLarge, Size: 2048},
+		{Name: models.ImageMedium, Size: 1024},
+		{Name: models.ImageThumbnail, Size: 320},
+	}
+	// ProfileVariants are made of profile pictures
+	ProfileVariants = []Variant{
+		{Name: models.ImageMedium, Size: 512, Square: true},
+		{Name: models.ImageThumbnail, Size: 128, Square: true},
+	}
+)
+
+// Upload checks the image, makes its variants and stores them under
+// prefix. Variants that were stored are deleted again when a later one
+// fails. Variants must be listed from largest to smallest.
+func Upload(ctx context.Context, store blob.Store, prefix string, data []byte, variants []Variant, now time.Time) (*models.Image, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	format, ok := formats[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	src := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		src = orient(src, jpegOrientation(data))
+	}
+
+	img := &models.Image{
+		ID:          primitive.NewObjectID(),
+		ContentType: contentType,
+		Width:       src.Bounds().Dx(),
+		Height:      src.Bounds().Dy(),
+		Variants:    map[string]string{},
+		CreatedAt:   primitive.NewDateTimeFromTime(now),
+	}
+	for _, variant := range variants {
+		// Each variant is scaled from the previous, larger one
+		src = fit(src, variant)
+
+		var buf bytes.Buffer
+		if err := format.encode(&buf, src); err != nil {
+			Delete(ctx, store, img)
+			return nil, err
+		}
+		key := path.Join(prefix, fmt.Sprintf("%s-%s%s", img.ID.Hex(), variant.Name, format.ext))
+		if err := store.Put(ctx, key, contentType, &buf); err != nil {
+			Delete(ctx, store, img)
+			return nil, err
+		}
+		img.Keys = append(img.Keys, key)
+		img.Variants[variant.Name] = store.URL(key)
+	}
+	return img, nil
+}
+
+// Delete removes every variant of the image from the store
+func Delete(ctx context.Context, store blob.Store, img *models.Image) error {
+	var errs []error
+	for _, key := range img.Keys {
+		errs = append(errs, store.Delete(ctx, key))
+	}
+	return errors.Join(errs...)
+}
+
+type format struct {
+	ext    string
+	encode func(*bytes.Buffer, image.Image) error
+}
+
+// formats are the accepted content types. Variants keep the format of the
+// upload so transparent PNGs stay transparent.
+var formats = map[string]format{
+	"image/jpeg": {ext: ".jpg", encode: func(buf *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+	}},
+	"image/png": {ext: ".png", encode: func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	}},
+}
+
+// toRGBA copies the image into an RGBA image whose bounds start at zero
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
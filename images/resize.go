@@ -0,0 +1,60 @@
+package images
+
+import "image"
+
+// fit crops and scales the image down to the variant's size
+func fit(src *image.RGBA, variant Variant) *image.RGBA {
+	b := src.Bounds()
+	if variant.Square && b.Dx() != b.Dy() {
+		side := min(b.Dx(), b.Dy())
+		x, y := b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2
+		src = src.SubImage(image.Rect(x, y, x+side, y+side)).(*image.RGBA)
+		b = src.Bounds()
+	}
+
+	w, h := b.Dx(), b.Dy()
+	if w <= variant.Size && h <= variant.Size {
+		return src
+	}
+	if w >= h {
+		w, h = variant.Size, max(1, h*variant.Size/w)
+	} else {
+		w, h = max(1, w*variant.Size/h), variant.Size
+	}
+	return resize(src, w, h)
+}
+
+// resize scales the image down to w by h pixels, averaging the source
+// pixels each destination pixel covers
+func resize(src *image.RGBA, w, h int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := range h {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+		for x := range w {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[src.PixOffset(b.Min.X+x0, b.Min.Y+sy):src.PixOffset(b.Min.X+x1, b.Min.Y+sy)]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			d := dst.Pix[dst.PixOffset(x, y):]
+			for c := range sum {
+				d[c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
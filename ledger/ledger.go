@@ -0,0 +1,149 @@
+// Package ledger turns leases into invoices and summarizes what a tenant has
+// paid. Invoices are keyed per lease so generating them again is harmless.
+package ledger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// LateFeeGracePeriod is how long a rent invoice may stay open after its due date before a late fee is charged
+	LateFeeGracePeriod = 5 * 24 * time.Hour
+	// LateFeeRate is the late fee as a fraction of the overdue invoice
+	LateFeeRate = 0.05
+)
+
+// Round rounds an amount to cents
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// DueInvoices returns the invoices the lease should have by now: the deposit
+// and one rent invoice for every monthly period that has started. Rent is
+// due at the start of each period; a final period shorter than a month is
+// prorated by day.
+func DueInvoices(lease *models.Lease, now time.Time) []models.Invoice {
+	at := primitive.NewDateTimeFromTime(now)
+	invoice := func(kind models.InvoiceKind, key string, amount float64, due primitive.DateTime) models.Invoice {
+		return models.Invoice{
+			LeaseID:     lease.ID,
+			PropertyID:  lease.PropertyID,
+			OwnerEmail:  lease.OwnerEmail,
+			TenantID:    lease.TenantID,
+			TenantEmail: lease.TenantEmail,
+			Key:         key,
+			Kind:        kind,
+			DueDate:     due,
+			Amount:      Round(amount),
+			Status:      models.InvoiceOpen,
+			CreatedAt:   at,
+			UpdatedAt:   at,
+		}
+	}
+
+	var invoices []models.Invoice
+	if lease.Deposit > 0 {
+		invoices = append(invoices, invoice(models.InvoiceDeposit, "deposit", lease.Deposit, lease.StartDate))
+	}
+
+	start, end := lease.StartDate.Time().UTC(), lease.EndDate.Time().UTC()
+	for i := 0; ; i++ {
+		periodStart := start.AddDate(0, i, 0)
+		if !periodStart.Before(end) || periodStart.After(now) {
+			break
+		}
+		periodEnd := start.AddDate(0, i+1, 0)
+		amount := lease.MonthlyRent
+		if periodEnd.After(end) {
+			amount *= end.Sub(periodStart).Hours() / periodEnd.Sub(periodStart).Hours()
+			periodEnd = end
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		rent := invoice(models.InvoiceRent, "rent:"+periodStart.Format(time.DateOnly), amount, primitive.NewDateTimeFromTime(periodStart))
+		rent.PeriodStart = primitive.NewDateTimeFromTime(periodStart)
+		rent.PeriodEnd = primitive.NewDateTimeFromTime(periodEnd)
+		invoices = append(invoices, rent)
+	}
+	return invoices
+}
+
+// Generate stores the invoices returned by DueInvoices that do not exist yet
+// and returns how many were created.
+func Generate(ctx context.Context, invoices repository.InvoiceRepository, lease *models.Lease, now time.Time) (int, error) {
+	created := 0
+	for _, invoice := range DueInvoices(lease, now) {
+		err := invoices.Create(ctx, &invoice)
+		if errors.Is(err, repository.ErrDuplicate) {
+			continue
+		}
+		if err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// LateFee returns the late fee invoice charged for an overdue rent invoice
+func LateFee(overdue *models.Invoice, now time.Time) models.Invoice {
+	at := primitive.NewDateTimeFromTime(now)
+	return models.Invoice{
+		LeaseID:     overdue.LeaseID,
+		PropertyID:  overdue.PropertyID,
+		OwnerEmail:  overdue.OwnerEmail,
+		TenantID:    overdue.TenantID,
+		TenantEmail: overdue.TenantEmail,
+		Key:         "late_fee:" + overdue.ID.Hex(),
+		Kind:        models.InvoiceLateFee,
+		DueDate:     at,
+		Amount:      Round(overdue.Amount * LateFeeRate),
+		Status:      models.InvoiceOpen,
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+}
+
+// Statement totals the invoices and payments of a lease as of now
+func Statement(lease *models.Lease, invoices []models.Invoice, payments []models.Payment, now time.Time) models.Statement {
+	statement := models.Statement{
+		LeaseID:    lease.ID,
+		PropertyID: lease.PropertyID,
+		Invoices:   invoices,
+		Payments:   payments,
+	}
+
+	at := primitive.NewDateTimeFromTime(now)
+	for _, invoice := range invoices {
+		statement.TotalInvoiced += invoice.Amount
+		statement.TotalPaid += invoice.AmountPaid
+		statement.Balance += invoice.Outstanding()
+		if invoice.Status == models.InvoiceOpen && invoice.DueDate < at {
+			statement.Overdue += invoice.Outstanding()
+		}
+		switch invoice.Kind {
+		case models.InvoiceLateFee:
+			statement.LateFees += invoice.Amount
+		case models.InvoiceDeposit:
+			statement.DepositHeld += invoice.AmountPaid
+		}
+	}
+
+	statement.TotalInvoiced = Round(statement.TotalInvoiced)
+	statement.TotalPaid = Round(statement.TotalPaid)
+	statement.Balance = Round(statement.Balance)
+	statement.Overdue = Round(statement.Overdue)
+	statement.LateFees = Round(statement.LateFees)
+	statement.DepositHeld = Round(statement.DepositHeld)
+	return statement
+}
@@ -0,0 +1,164 @@
+package ledger
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository/memory"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// newLease runs from January 15 to April 1, 2025: two full months and a
+// prorated 17 days of a 31-day period
+func newLease(deposit float64) *models.Lease {
+	return &models.Lease{
+		ID:          primitive.NewObjectID(),
+		StartDate:   primitive.NewDateTimeFromTime(date(2025, time.January, 15)),
+		EndDate:     primitive.NewDateTimeFromTime(date(2025, time.April, 1)),
+		MonthlyRent: 1000,
+		Deposit:     deposit,
+		Status:      models.LeaseActive,
+	}
+}
+
+func TestDueInvoices(t *testing.T) {
+	type due struct {
+		key    string
+		amount float64
+	}
+	tests := []struct {
+		name    string
+		deposit float64
+		now     time.Time
+		want    []due
+	}{
+		{"before the start only the deposit", 500, date(2025, time.January, 1), []due{{"deposit", 500}}},
+		{"no deposit", 0, date(2025, time.January, 1), nil},
+		{
+			"first period starts on the start date", 500, date(2025, time.January, 15),
+			[]due{{"deposit", 500}, {"rent:2025-01-15", 1000}},
+		},
+		{
+			"one invoice per started period", 0, date(2025, time.February, 20),
+			[]due{{"rent:2025-01-15", 1000}, {"rent:2025-02-15", 1000}},
+		},
+		{
+			"last period prorated by day", 0, date(2025, time.March, 15),
+			[]due{{"rent:2025-01-15", 1000}, {"rent:2025-02-15", 1000}, {"rent:2025-03-15", 548.39}},
+		},
+		{
+			"nothing after the end", 0, date(2026, time.January, 1),
+			[]due{{"rent:2025-01-15", 1000}, {"rent:2025-02-15", 1000}, {"rent:2025-03-15", 548.39}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lease := newLease(tt.deposit)
+			var got []due
+			for _, invoice := range DueInvoices(lease, tt.now) {
+				if invoice.LeaseID != lease.ID || invoice.Status != models.InvoiceOpen {
+					t.Errorf("invoice %s is not an open invoice of the lease", invoice.Key)
+				}
+				got = append(got, due{invoice.Key, invoice.Amount})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DueInvoices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueInvoicesPeriods(t *testing.T) {
+	invoices := DueInvoices(newLease(0), date(2025, time.March, 20))
+	want := [][2]time.Time{
+		{date(2025, time.January, 15), date(2025, time.February, 15)},
+		{date(2025, time.February, 15), date(2025, time.March, 15)},
+		{date(2025, time.March, 15), date(2025, time.April, 1)},
+	}
+	if len(invoices) != len(want) {
+		t.Fatalf("got %d invoices, want %d", len(invoices), len(want))
+	}
+	for i, invoice := range invoices {
+		start, end := invoice.PeriodStart.Time().UTC(), invoice.PeriodEnd.Time().UTC()
+		if !start.Equal(want[i][0]) || !end.Equal(want[i][1]) || !invoice.DueDate.Time().UTC().Equal(want[i][0]) {
+			t.Errorf("invoice %d covers %s to %s due %s, want %s to %s due on the start",
+				i, start, end, invoice.DueDate.Time().UTC(), want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	invoices := memory.NewInvoiceRepository()
+	lease := newLease(500)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"deposit and first rent", date(2025, time.January, 20), 2},
+		{"same day again", date(2025, time.January, 20), 0},
+		{"next period", date(2025, time.February, 15), 1},
+		{"after the end", date(2025, time.June, 1), 1},
+		{"nothing left", date(2025, time.July, 1), 0},
+	}
+	for _, tt := range tests {
+		created, err := Generate(context.Background(), invoices, lease, tt.now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if created != tt.want {
+			t.Errorf("%s: created %d invoices, want %d", tt.name, created, tt.want)
+		}
+	}
+}
+
+func TestLateFee(t *testing.T) {
+	overdue := &models.Invoice{ID: primitive.NewObjectID(), LeaseID: primitive.NewObjectID(), Amount: 1234.5}
+	fee := LateFee(overdue, date(2025, time.March, 1))
+
+	if fee.Kind != models.InvoiceLateFee || fee.LeaseID != overdue.LeaseID {
+		t.Errorf("fee = %+v, want a late fee of the overdue invoice's lease", fee)
+	}
+	if fee.Amount != 61.73 {
+		t.Errorf("fee amount = %v, want 61.73", fee.Amount)
+	}
+	if fee.Key != "late_fee:"+overdue.ID.Hex() {
+		t.Errorf("fee key = %q, want one per overdue invoice", fee.Key)
+	}
+}
+
+func TestStatement(t *testing.T) {
+	lease := newLease(500)
+	now := date(2025, time.March, 1)
+	past := primitive.NewDateTimeFromTime(date(2025, time.February, 15))
+	future := primitive.NewDateTimeFromTime(date(2025, time.March, 15))
+	invoices := []models.Invoice{
+		{Kind: models.InvoiceDeposit, Amount: 500, AmountPaid: 500, Status: models.InvoicePaid, DueDate: past},
+		{Kind: models.InvoiceRent, Amount: 1000, AmountPaid: 400, Status: models.InvoiceOpen, DueDate: past},
+		{Kind: models.InvoiceLateFee, Amount: 50, Status: models.InvoiceOpen, DueDate: future},
+	}
+
+	got := Statement(lease, invoices, nil, now)
+	want := map[string][2]float64{
+		"invoiced":     {got.TotalInvoiced, 1550},
+		"paid":         {got.TotalPaid, 900},
+		"balance":      {got.Balance, 650},
+		"overdue":      {got.Overdue, 600},
+		"late fees":    {got.LateFees, 50},
+		"deposit held": {got.DepositHeld, 500},
+	}
+	for name, values := range want {
+		if values[0] != values[1] {
+			t.Errorf("%s = %v, want %v", name, values[0], values[1])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Mailer delivers plain-text emails such as one-time codes and password reset links.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New returns a FileMailer writing to path if it is set, otherwise a
+// StdoutMailer.
+func New(path string) Mailer {
+	if path != "" {
+		return &FileMailer{Path: path}
+	}
+	return StdoutMailer{}
+}
+
+// StdoutMailer prints emails to stdout. Useful for local development.
+type StdoutMailer struct{}
+
+func (StdoutMailer) Send(_ context.Context, to, subject, body string) error {
+	_, err := fmt.Print(format(to, subject, body))
+	return err
+}
+
+// FileMailer appends emails to a local file.
+type FileMailer struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (m *FileMailer) Send(_ context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(format(to, subject, body))
+	return err
+}
+
+func format(to, subject, body string) string {
+	return fmt.Sprintf("Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), to, subject, body)
+}
@@ -3,29 +3,224 @@
 // @description This is a sample server.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token.
 package main
 
 import (
+	"context"
+	"dwello-api/auth"
+	"dwello-api/blob"
 	"dwello-api/config"
+	"dwello-api/health"
+	"dwello-api/images"
+	"dwello-api/mailer"
+	"dwello-api/notify"
+	"dwello-api/payments"
+	"dwello-api/problem"
+	"dwello-api/push"
+	"dwello-api/realtime"
+	"dwello-api/repository"
+	"dwello-api/repository/memory"
+	"dwello-api/repository/mongodb"
 	"dwello-api/routes"
+	"dwello-api/utils"
+	"dwello-api/webhook"
+	"dwello-api/worker"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	_ "dwello-api/docs" // docs generated by Swag CLI
 
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
 func main() {
-	// Initialize the database connection
-	config.ConnectDB()
-	defer config.DisconnectDB() // Ensure the client disconnects when the program exits
+	configPath := flag.String("config", os.Getenv("DWELLO_CONFIG"), "path to a YAML config file")
+	printConfig := flag.Bool("print-config", false, "print the configuration with secrets redacted and exit")
+	flag.Parse()
 
-	app := fiber.New()
-	app.Get("/swagger/*", fiberSwagger.WrapHandler)
+	// Settings come from the defaults, the config file and then the environment
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	if *printConfig {
+		if err := cfg.Dump(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	routes.Setup(app) // Setup all routes
+	auth.SetSecret(cfg.Auth.JWTSecret)
+	utils.DatabaseTimeout = cfg.Mongo.QueryTimeout
+	images.MaxUploadSize = cfg.Uploads.MaxUploadBytes()
+	images.MaxPixels = cfg.Uploads.MaxPixels()
 
-	log.Fatal(app.Listen(":8080"))
+	// Readiness checks served on /readyz
+	checker := health.NewChecker()
+
+	var store repository.Store
+	if cfg.Store == config.StoreMemory {
+		// Keep everything in process memory, useful for local development
+		log.Println("Using the in-memory store, data will not be persisted")
+		store = memory.NewStore()
+	} else {
+		// Initialize the database connection
+		config.ConnectDB(cfg.Mongo)
+		store = mongodb.NewStore(config.DB)
+		checker.Add("mongo", config.PingDB)
+	}
+
+	m := mailer.New(cfg.Mail.File)
+
+	// Pushes events to the users connected over WebSocket
+	hub := realtime.NewHub()
+
+	// Delivers the notifications raised by the handlers and background jobs
+	channels := []notify.Channel{notify.NewInApp(store.Notifications, hub), notify.NewEmail(m)}
+	sender, err := push.New(cfg.Push.Sender)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if sender != nil {
+		channels = append(channels, notify.NewPush(store.Users, sender))
+	} else {
+		log.Println("Push notifications are turned off, set push.sender to send them")
+	}
+	notifier := notify.NewDispatcher(store.Users, channels...)
+
+	// Charges tenants paying invoices online
+	provider, err := payments.New(cfg.Payments.Provider)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if provider == nil {
+		log.Println("Online payments are turned off, set payments.provider to take them")
+	}
+
+	// Background jobs
+	workers := worker.NewPool(
+		worker.ExpireRentalRequests(store.RentalRequests, webhook.NewPublisher(store.Webhooks, store.Deliveries)),
+		worker.EndLeases(store),
+		worker.GenerateInvoices(store),
+		worker.ApplyLateFees(store.Invoices),
+		worker.MatchSavedSearches(store, m),
+		worker.ComputeSimilarProperties(store),
+		worker.DeliverWebhooks(store),
+		worker.RemindViewings(store.Viewings, notifier),
+	)
+	if cfg.Features.Workers {
+		checker.Add("workers", workers.Check)
+	} else {
+		log.Println("Background jobs are turned off")
+	}
+	startWorkers := func() {
+		if cfg.Features.Workers {
+			workers.Start(context.Background())
+		}
+	}
+
+	// failed reports a startup step that failed after the API started
+	// listening, which shuts the API down the same way a signal does
+	failed := make(chan error, 1)
+	if cfg.Store == config.StoreMemory {
+		startWorkers()
+	} else {
+		// Upgrade documents written by older versions and create the indexes
+		// while the API already answers the probes. It is not ready, and the
+		// jobs do not run, until they are done.
+		var indexes health.Step
+		checker.Add("indexes", indexes.Check)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.MigrateTimeout)
+			defer cancel()
+			err := mongodb.Migrate(ctx, config.DB)
+			if err == nil {
+				err = mongodb.EnsureIndexes(ctx, config.DB)
+			}
+			indexes.Finish(err)
+			if err != nil {
+				failed <- err
+				return
+			}
+			startWorkers()
+		}()
+	}
+
+	app := fiber.New(fiber.Config{
+		// Render every error as problem details
+		ErrorHandler: problem.Handler,
+		// Leave room for the multipart encoding around an upload of the largest size
+		BodyLimit:    images.MaxUploadSize + 1<<20,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	})
+	if len(cfg.Server.CORSOrigins) > 0 {
+		app.Use(cors.New(cors.Config{
+			AllowOrigins: strings.Join(cfg.Server.CORSOrigins, ","),
+			AllowHeaders: "Authorization, Content-Type",
+		}))
+	}
+	if cfg.Features.Swagger {
+		app.Get("/swagger/*", fiberSwagger.WrapHandler)
+	}
+
+	// Serve uploaded pictures when they are kept on the local filesystem
+	blobs := blob.New(cfg.Media.Dir, cfg.Media.URL)
+	if local, ok := blobs.(*blob.LocalStore); ok {
+		app.Static(blob.LocalPath, local.Dir)
+	}
+
+	routes.Setup(app, store, m, provider, blobs, hub, notifier, checker) // Setup all routes
+
+	go func() {
+		if err := app.Listen(cfg.Server.Addr); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// Drain on SIGINT or SIGTERM: stop accepting connections, let in-flight
+	// requests and job runs finish, then disconnect from the database
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	var startErr error
+	select {
+	case <-quit:
+	case startErr = <-failed:
+		log.Println("Failed to prepare the database:", startErr)
+	}
+	log.Println("Shutting down")
+
+	// Requests, job runs and the disconnect share a single deadline
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	workers.Stop()
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		log.Println("Failed to drain requests:", err)
+	}
+	if err := workers.Wait(ctx); err != nil {
+		log.Println("Failed to wait for background jobs:", err)
+	}
+	if cfg.Store != config.StoreMemory {
+		if err := config.DisconnectDB(ctx); err != nil {
+			log.Println("Failed to disconnect from MongoDB:", err)
+		}
+	}
+	log.Println("Stopped")
+	if startErr != nil {
+		cancel()
+		os.Exit(1)
+	}
 }
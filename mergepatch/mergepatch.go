@@ -0,0 +1,200 @@
+// Package mergepatch applies JSON Merge Patches (RFC 7396) to Go values and
+// reports what they changed. A patch is a JSON object: its members replace
+// those of the value, objects are patched member by member and null removes
+// a member, which leaves the Go field at its zero value.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrNotObject is returned for a patch that is not a JSON object
+var ErrNotObject = errors.New("patch must be a JSON object")
+
+// UnknownFieldsError lists the members of a patch that have no field in the
+// value, by their dotted JSON path
+type UnknownFieldsError struct {
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.Fields, ", ")
+}
+
+// Apply patches v, a pointer to a struct, through its JSON encoding, so
+// fields without one are reset to their zero value. Members of the patch that
+// v has no field for are an *UnknownFieldsError and v is left untouched;
+// values of the wrong type are a *json.UnmarshalTypeError.
+func Apply(v any, patch []byte) error {
+	var changes map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.UseNumber()
+	if err := decoder.Decode(&changes); err != nil || changes == nil {
+		return ErrNotObject
+	}
+
+	target := reflect.ValueOf(v).Elem()
+	if unknown := unknownFields(target.Type(), changes, ""); len(unknown) > 0 {
+		slices.Sort(unknown)
+		return &UnknownFieldsError{Fields: unknown}
+	}
+
+	doc, err := toMap(v, true)
+	if err != nil {
+		return err
+	}
+	merged, err := json.Marshal(merge(doc, changes))
+	if err != nil {
+		return err
+	}
+
+	patched := reflect.New(target.Type())
+	if err := json.Unmarshal(merged, patched.Interface()); err != nil {
+		return err
+	}
+	target.Set(patched.Elem())
+	return nil
+}
+
+// merge applies patch to doc as RFC 7396 describes
+func merge(doc, patch map[string]any) map[string]any {
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	for name, value := range patch {
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		if object, ok := value.(map[string]any); ok {
+			current, _ := doc[name].(map[string]any)
+			doc[name] = merge(current, object)
+			continue
+		}
+		doc[name] = value
+	}
+	return doc
+}
+
+// unknownFields lists the members of patch that t, a struct type, has no
+// JSON field for. Objects are checked against the struct they patch.
+func unknownFields(t reflect.Type, patch map[string]any, prefix string) []string {
+	fields := jsonFields(t)
+	var unknown []string
+	for name, value := range patch {
+		field, ok := fields[name]
+		if !ok {
+			unknown = append(unknown, prefix+name)
+			continue
+		}
+		object, ok := value.(map[string]any)
+		for field.Kind() == reflect.Pointer {
+			field = field.Elem()
+		}
+		if ok && field.Kind() == reflect.Struct {
+			unknown = append(unknown, unknownFields(field, object, prefix+name+".")...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields maps the JSON names of the fields of t to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// Change is a value that differs, Field being its dotted JSON path. A missing
+// value is nil.
+type Change struct {
+	Field string
+	From  any
+	To    any
+}
+
+// Diff reports the values that differ between the JSON encodings of a and b,
+// sorted by field. Objects are compared member by member and other values,
+// lists included, as a whole.
+func Diff(a, b any) ([]Change, error) {
+	from, err := toMap(a, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toMap(b, false)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diff(from, to, "")
+	slices.SortFunc(changes, func(x, y Change) int { return strings.Compare(x.Field, y.Field) })
+	return changes, nil
+}
+
+func diff(from, to map[string]any, prefix string) []Change {
+	var changes []Change
+	for name := range union(from, to) {
+		a, b := from[name], to[name]
+		objectA, okA := a.(map[string]any)
+		objectB, okB := b.(map[string]any)
+		switch {
+		case okA || okB:
+			if !okA && a != nil || !okB && b != nil {
+				changes = append(changes, Change{Field: prefix + name, From: a, To: b})
+				continue
+			}
+			changes = append(changes, diff(objectA, objectB, prefix+name+".")...)
+		case !reflect.DeepEqual(a, b):
+			changes = append(changes, Change{Field: prefix + name, From: a, To: b})
+		}
+	}
+	return changes
+}
+
+func union(a, b map[string]any) map[string]struct{} {
+	names := map[string]struct{}{}
+	for name := range a {
+		names[name] = struct{}{}
+	}
+	for name := range b {
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// toMap returns the JSON encoding of v as a map. Numbers are float64 unless
+// exact, when they are kept as json.Number so that they round-trip.
+func toMap(v any, exact bool) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if exact {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%T does not encode to a JSON object: %w", v, err)
+	}
+	return doc, nil
+}
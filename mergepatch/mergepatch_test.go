@@ -0,0 +1,206 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type details struct {
+	Bedrooms   int    `json:"bedrooms"`
+	Furnishing string `json:"furnishing,omitempty"`
+}
+
+type listing struct {
+	Title    string   `json:"title"`
+	Price    float64  `json:"price"`
+	Tags     []string `json:"tags,omitempty"`
+	Details  details  `json:"details"`
+	Location *details `json:"location,omitempty"`
+	Secret   string   `json:"-"`
+}
+
+func TestApply(t *testing.T) {
+	original := listing{
+		Title:   "Flat",
+		Price:   100,
+		Tags:    []string{"garden", "lift"},
+		Details: details{Bedrooms: 2, Furnishing: "furnished"},
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    listing
+		wantErr error
+	}{
+		{
+			name:  "empty patch keeps everything",
+			patch: `{}`,
+			want:  original,
+		},
+		{
+			name:  "members replace fields",
+			patch: `{"title": "House", "price": 250.5}`,
+			want: listing{
+				Title: "House", Price: 250.5, Tags: original.Tags, Details: original.Details,
+			},
+		},
+		{
+			name:  "objects are patched member by member",
+			patch: `{"details": {"bedrooms": 3}}`,
+			want: listing{
+				Title: "Flat", Price: 100, Tags: original.Tags,
+				Details: details{Bedrooms: 3, Furnishing: "furnished"},
+			},
+		},
+		{
+			name:  "null clears a field",
+			patch: `{"details": {"furnishing": null}, "tags": null}`,
+			want:  listing{Title: "Flat", Price: 100, Details: details{Bedrooms: 2}},
+		},
+		{
+			name:  "lists are replaced as a whole",
+			patch: `{"tags": ["pool"]}`,
+			want: listing{
+				Title: "Flat", Price: 100, Tags: []string{"pool"}, Details: original.Details,
+			},
+		},
+		{
+			name:  "missing pointers are created",
+			patch: `{"location": {"bedrooms": 1}}`,
+			want: listing{
+				Title: "Flat", Price: 100, Tags: original.Tags, Details: original.Details,
+				Location: &details{Bedrooms: 1},
+			},
+		},
+		{
+			name:    "unknown members",
+			patch:   `{"owner": "me", "details": {"pool": true}}`,
+			want:    original,
+			wantErr: &UnknownFieldsError{Fields: []string{"details.pool", "owner"}},
+		},
+		{
+			name:    "hidden fields are unknown",
+			patch:   `{"Secret": "changed"}`,
+			want:    original,
+			wantErr: &UnknownFieldsError{Fields: []string{"Secret"}},
+		},
+		{
+			name:    "not an object",
+			patch:   `["title"]`,
+			want:    original,
+			wantErr: ErrNotObject,
+		},
+		{
+			name:    "null patch",
+			patch:   `null`,
+			want:    original,
+			wantErr: ErrNotObject,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := original
+			got.Tags = append([]string(nil), original.Tags...)
+			err := Apply(&got, []byte(tt.patch))
+
+			var unknown *UnknownFieldsError
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Apply() error = %v", err)
+			case errors.As(tt.wantErr, &unknown):
+				var gotUnknown *UnknownFieldsError
+				if !errors.As(err, &gotUnknown) || !reflect.DeepEqual(gotUnknown.Fields, unknown.Fields) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyResetsFieldsWithoutJSON(t *testing.T) {
+	got := listing{Title: "Flat", Secret: "dropped"}
+	if err := Apply(&got, []byte(`{"price": 100}`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := (listing{Title: "Flat", Price: 100}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyWrongType(t *testing.T) {
+	got := listing{Title: "Flat"}
+	err := Apply(&got, []byte(`{"price": "cheap"}`))
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Apply() error = %v, want a *json.UnmarshalTypeError", err)
+	}
+	if got.Title != "Flat" || got.Price != 0 {
+		t.Errorf("Apply() changed the value to %+v", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := listing{Title: "Flat", Price: 100, Tags: []string{"garden"}, Details: details{Bedrooms: 2}}
+
+	tests := []struct {
+		name  string
+		after listing
+		want  []Change
+	}{
+		{
+			name:  "no change",
+			after: before,
+		},
+		{
+			name:  "changed values sorted by field",
+			after: listing{Title: "House", Price: 120, Tags: []string{"garden"}, Details: details{Bedrooms: 2}},
+			want: []Change{
+				{Field: "price", From: 100.0, To: 120.0},
+				{Field: "title", From: "Flat", To: "House"},
+			},
+		},
+		{
+			name:  "objects compared member by member",
+			after: listing{Title: "Flat", Price: 100, Tags: []string{"garden"}, Details: details{Bedrooms: 3, Furnishing: "furnished"}},
+			want: []Change{
+				{Field: "details.bedrooms", From: 2.0, To: 3.0},
+				{Field: "details.furnishing", From: nil, To: "furnished"},
+			},
+		},
+		{
+			name:  "lists compared as a whole",
+			after: listing{Title: "Flat", Price: 100, Tags: []string{"garden", "lift"}, Details: details{Bedrooms: 2}},
+			want:  []Change{{Field: "tags", From: []any{"garden"}, To: []any{"garden", "lift"}}},
+		},
+		{
+			name:  "removed value",
+			after: listing{Title: "Flat", Price: 100, Details: details{Bedrooms: 2}},
+			want:  []Change{{Field: "tags", From: []any{"garden"}, To: nil}},
+		},
+		{
+			name:  "added object",
+			after: listing{Title: "Flat", Price: 100, Tags: []string{"garden"}, Details: details{Bedrooms: 2}, Location: &details{}},
+			want:  []Change{{Field: "location.bedrooms", From: nil, To: 0.0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
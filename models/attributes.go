@@ -0,0 +1,133 @@
+package models
+
+import "strconv"
+
+// PropertyType is the kind of dwelling
+type PropertyType string
+
+const (
+	PropertyApartment PropertyType = "apartment"
+	PropertyHouse     PropertyType = "house"
+	PropertyVilla     PropertyType = "villa"
+	PropertyStudio    PropertyType = "studio"
+	PropertyRoom      PropertyType = "room"
+)
+
+// Valid reports whether t is one of the known property types
+func (t PropertyType) Valid() bool {
+	switch t {
+	case PropertyApartment, PropertyHouse, PropertyVilla, PropertyStudio, PropertyRoom:
+		return true
+	}
+	return false
+}
+
+// Furnishing says how much furniture comes with the property
+type Furnishing string
+
+const (
+	Unfurnished   Furnishing = "unfurnished"
+	SemiFurnished Furnishing = "semi_furnished"
+	Furnished     Furnishing = "furnished"
+)
+
+// Valid reports whether f is one of the known furnishings
+func (f Furnishing) Valid() bool {
+	switch f {
+	case Unfurnished, SemiFurnished, Furnished:
+		return true
+	}
+	return false
+}
+
+// PropertyAttributes are the structured details renters filter on. Listings
+// created before they existed have the zero value.
+type PropertyAttributes struct {
+	Type        PropertyType `bson:"type,omitempty" json:"type,omitempty" validate:"omitempty,oneof=apartment house villa studio room"`
+	Bedrooms    int          `bson:"bedrooms" json:"bedrooms" validate:"min=0,max=20"`
+	Bathrooms   int          `bson:"bathrooms" json:"bathrooms" validate:"min=0,max=20"`
+	AreaSqft    float64      `bson:"area_sqft,omitempty" json:"area_sqft,omitempty" validate:"min=0,max=100000"`
+	Furnishing  Furnishing   `bson:"furnishing,omitempty" json:"furnishing,omitempty" validate:"omitempty,oneof=unfurnished semi_furnished furnished"`
+	PetsAllowed bool         `bson:"pets_allowed" json:"pets_allowed"`
+	Amenities   []string     `bson:"amenities,omitempty" json:"amenities,omitempty" validate:"unique,dive,oneof=parking lift power_backup security gym pool garden balcony air_conditioning wifi laundry"`
+}
+
+// PropertyFacets counts the properties matching a search by attribute value.
+// Keys are the values as strings, such as "2" for two bedrooms.
+type PropertyFacets struct {
+	Type        map[string]int64 `json:"type"`
+	Bedrooms    map[string]int64 `json:"bedrooms"`
+	Bathrooms   map[string]int64 `json:"bathrooms"`
+	Furnishing  map[string]int64 `json:"furnishing"`
+	PetsAllowed map[string]int64 `json:"pets_allowed"`
+	Amenities   map[string]int64 `json:"amenities"`
+	Location    map[string]int64 `json:"location"`
+	// BedroomsByLocation counts bedrooms per location, such as how many 2BHKs each location has
+	BedroomsByLocation map[string]map[string]int64 `json:"bedrooms_by_location"`
+}
+
+// NewPropertyFacets returns facets with every count at zero
+func NewPropertyFacets() *PropertyFacets {
+	return &PropertyFacets{
+		Type:               map[string]int64{},
+		Bedrooms:           map[string]int64{},
+		Bathrooms:          map[string]int64{},
+		Furnishing:         map[string]int64{},
+		PetsAllowed:        map[string]int64{},
+		Amenities:          map[string]int64{},
+		Location:           map[string]int64{},
+		BedroomsByLocation: map[string]map[string]int64{},
+	}
+}
+
+// AddBedroomsByLocation adds count properties with the given bedrooms at the location
+func (f *PropertyFacets) AddBedroomsByLocation(location string, bedrooms int, count int64) {
+	if f.BedroomsByLocation[location] == nil {
+		f.BedroomsByLocation[location] = map[string]int64{}
+	}
+	f.BedroomsByLocation[location][strconv.Itoa(bedrooms)] += count
+}
+
+// Add counts a single property in every facet
+func (f *PropertyFacets) Add(property *Property) {
+	attrs := property.Attributes
+	if attrs.Type != "" {
+		f.Type[string(attrs.Type)]++
+	}
+	f.Bedrooms[strconv.Itoa(attrs.Bedrooms)]++
+	f.Bathrooms[strconv.Itoa(attrs.Bathrooms)]++
+	if attrs.Furnishing != "" {
+		f.Furnishing[string(attrs.Furnishing)]++
+	}
+	f.PetsAllowed[strconv.FormatBool(attrs.PetsAllowed)]++
+	for _, amenity := range attrs.Amenities {
+		f.Amenities[amenity]++
+	}
+	if property.Location != "" {
+		f.Location[property.Location]++
+	}
+	f.AddBedroomsByLocation(property.Location, attrs.Bedrooms, 1)
+}
+
+// PropertyAttributesSwagger is a Swagger-friendly version of PropertyAttributes
+type PropertyAttributesSwagger struct {
+	Type        string   `json:"type,omitempty" example:"apartment" enums:"apartment,house,villa,studio,room"`
+	Bedrooms    int      `json:"bedrooms" example:"2"`
+	Bathrooms   int      `json:"bathrooms" example:"2"`
+	AreaSqft    float64  `json:"area_sqft,omitempty" example:"950"`
+	Furnishing  string   `json:"furnishing,omitempty" example:"semi_furnished" enums:"unfurnished,semi_furnished,furnished"`
+	PetsAllowed bool     `json:"pets_allowed" example:"true"`
+	Amenities   []string `json:"amenities,omitempty" example:"parking,lift"`
+}
+
+// PropertyFacetsSwagger is a Swagger-friendly version of PropertyFacets
+type PropertyFacetsSwagger struct {
+	Type               map[string]int64            `json:"type"`
+	Bedrooms           map[string]int64            `json:"bedrooms"`
+	Bathrooms          map[string]int64            `json:"bathrooms"`
+	Furnishing         map[string]int64            `json:"furnishing"`
+	PetsAllowed        map[string]int64            `json:"pets_allowed"`
+	Amenities          map[string]int64            `json:"amenities"`
+	Location           map[string]int64            `json:"location"`
+	BedroomsByLocation map[string]map[string]int64 `json:"bedrooms_by_location"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Conversation is the message thread between a property's owner and a user
+// interested in renting it. There is at most one per property and user.
+type Conversation struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID    primitive.ObjectID `bson:"property_id" json:"property_id"`
+	PropertyTitle string             `bson:"property_title" json:"property_title"`
+
+	OwnerID    primitive.ObjectID `bson:"owner_id" json:"owner_id"`
+	OwnerEmail string             `bson:"owner_email" json:"owner_email"`
+	OwnerName  string             `bson:"owner_name" json:"owner_name"`
+
+	TenantID    primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantEmail string             `bson:"tenant_email" json:"tenant_email"`
+	TenantName  string             `bson:"tenant_name" json:"tenant_name"`
+
+	// LastMessage is the start of the latest message, for conversation lists
+	LastMessage   string             `bson:"last_message,omitempty" json:"last_message,omitempty"`
+	LastMessageAt primitive.DateTime `bson:"last_message_at,omitempty" json:"last_message_at,omitempty"`
+
+	// Read receipts: each participant has read the messages sent up to then
+	OwnerReadAt  primitive.DateTime `bson:"owner_read_at,omitempty" json:"owner_read_at,omitempty"`
+	TenantReadAt primitive.DateTime `bson:"tenant_read_at,omitempty" json:"tenant_read_at,omitempty"`
+
+	// Unread is how many messages the requesting user has not read yet
+	Unread int64 `bson:"-" json:"unread"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// IsParticipant reports whether the user is the owner or the tenant
+func (c *Conversation) IsParticipant(userID primitive.ObjectID) bool {
+	return userID == c.OwnerID || userID == c.TenantID
+}
+
+// OtherParticipant returns the participant the user is talking to
+func (c *Conversation) OtherParticipant(userID primitive.ObjectID) primitive.ObjectID {
+	if userID == c.OwnerID {
+		return c.TenantID
+	}
+	return c.OwnerID
+}
+
+// ReadAt returns the time up to which the participant has read the messages
+func (c *Conversation) ReadAt(userID primitive.ObjectID) primitive.DateTime {
+	if userID == c.OwnerID {
+		return c.OwnerReadAt
+	}
+	return c.TenantReadAt
+}
+
+// Message is sent by one participant of a conversation to the other
+type Message struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ConversationID primitive.ObjectID `bson:"conversation_id" json:"conversation_id"`
+	SenderID       primitive.ObjectID `bson:"sender_id" json:"sender_id"`
+	SenderEmail    string             `bson:"sender_email" json:"sender_email"`
+	Body           string             `bson:"body" json:"body"`
+
+	// Read reports whether the recipient has read the message
+	Read bool `bson:"-" json:"read"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// ConversationSwagger is a Swagger-friendly version of Conversation
+type ConversationSwagger struct {
+	ID            string `json:"id" example:"665f1c2e9b1e8a4d2c3b4a62"`
+	PropertyID    string `json:"property_id" example:"665f1c2e9b1e8a4d2c3b4a5f"`
+	PropertyTitle string `json:"property_title" example:"Modern 2BHK Apartment"`
+	OwnerID       string `json:"owner_id" example:"665f1c2e9b1e8a4d2c3b4a63"`
+	OwnerEmail    string `json:"owner_email" example:"owner@example.com"`
+	OwnerName     string `json:"owner_name" example:"John Doe"`
+	TenantID      string `json:"tenant_id" example:"665f1c2e9b1e8a4d2c3b4a5e"`
+	TenantEmail   string `json:"tenant_email" example:"tenant@example.com"`
+	TenantName    string `json:"tenant_name" example:"Alice Smith"`
+	LastMessage   string `json:"last_message,omitempty" example:"Is the flat still available?"`
+	LastMessageAt string `json:"last_message_at,omitempty" example:"2025-06-01T10:05:00Z"`
+	OwnerReadAt   string `json:"owner_read_at,omitempty" example:"2025-06-01T10:06:00Z"`
+	TenantReadAt  string `json:"tenant_read_at,omitempty" example:"2025-06-01T10:05:00Z"`
+	Unread        int64  `json:"unread" example:"2"`
+	CreatedAt     string `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// MessageSwagger is a Swagger-friendly version of Message
+type MessageSwagger struct {
+	ID             string `json:"id" example:"665f1c2e9b1e8a4d2c3b4a64"`
+	ConversationID string `json:"conversation_id" example:"665f1c2e9b1e8a4d2c3b4a62"`
+	SenderID       string `json:"sender_id" example:"665f1c2e9b1e8a4d2c3b4a5e"`
+	SenderEmail    string `json:"sender_email" example:"tenant@example.com"`
+	Body           string `json:"body" example:"Is the flat still available?"`
+	Read           bool   `json:"read" example:"false"`
+	CreatedAt      string `json:"created_at,omitempty" example:"2025-06-01T10:05:00Z"`
+}
+
+// MessageInputSwagger is the body of a new message
+type MessageInputSwagger struct {
+	Body string `json:"body" example:"Is the flat still available?"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "math"
+
+// earthRadius is the mean radius of the Earth in meters, as used by MongoDB
+const earthRadius = 6378100.0
+
+// GeoPoint is a GeoJSON point. Coordinates are longitude then latitude.
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint returns the point at the given longitude and latitude
+func NewGeoPoint(lng, lat float64) *GeoPoint {
+	return &GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+// Valid reports whether p is a GeoJSON point with coordinates on the globe
+func (p *GeoPoint) Valid() bool {
+	if p == nil || p.Type != "Point" || len(p.Coordinates) != 2 {
+		return false
+	}
+	return math.Abs(p.Lng()) <= 180 && math.Abs(p.Lat()) <= 90
+}
+
+func (p *GeoPoint) Lng() float64 { return p.Coordinates[0] }
+func (p *GeoPoint) Lat() float64 { return p.Coordinates[1] }
+
+// DistanceTo returns the great-circle distance to q in meters
+func (p *GeoPoint) DistanceTo(q *GeoPoint) float64 {
+	lat1, lat2 := p.Lat()*math.Pi/180, q.Lat()*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (q.Lng() - p.Lng()) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// GeoBox is the area between two corners, such as the visible part of a map
+type GeoBox struct {
+	// South-west corner
+	MinLng float64 `bson:"min_lng" json:"min_lng"`
+	MinLat float64 `bson:"min_lat" json:"min_lat"`
+	// North-east corner
+	MaxLng float64 `bson:"max_lng" json:"max_lng"`
+	MaxLat float64 `bson:"max_lat" json:"max_lat"`
+}
+
+// Valid reports whether the corners are on the globe and in order
+func (b *GeoBox) Valid() bool {
+	return b.MinLng >= -180 && b.MaxLng <= 180 && b.MinLat >= -90 && b.MaxLat <= 90 &&
+		b.MinLng < b.MaxLng && b.MinLat < b.MaxLat
+}
+
+// Contains reports whether the point lies inside the box. Its edges are
+// lines of latitude and longitude, as with a MongoDB $box.
+func (b *GeoBox) Contains(p *GeoPoint) bool {
+	return p.Lng() >= b.MinLng && p.Lng() <= b.MaxLng && p.Lat() >= b.MinLat && p.Lat() <= b.MaxLat
+}
+
+// GeoPointSwagger is a Swagger-friendly version of GeoPoint
+type GeoPointSwagger struct {
+	Type        string    `json:"type" example:"Point" enums:"Point"`
+	Coordinates []float64 `json:"coordinates" example:"73.8567,18.5204"`
+}
+
+// GeoBoxSwagger is a Swagger-friendly version of GeoBox
+type GeoBoxSwagger struct {
+	MinLng float64 `json:"min_lng" example:"73.7"`
+	MinLat float64 `json:"min_lat" example:"18.4"`
+	MaxLng float64 `json:"max_lng" example:"73.9"`
+	MaxLat float64 `json:"max_lat" example:"18.6"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Names of the resized variants of uploaded images
+const (
+	ImageLarge     = "large"
+	ImageMedium    = "medium"
+	ImageThumbnail = "thumbnail"
+)
+
+// Image is an uploaded picture. Every variant is a resized copy of the
+// original with its metadata stripped; the original itself is not kept.
+type Image struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	// Width and Height are those of the upright original
+	Width  int `bson:"width" json:"width"`
+	Height int `bson:"height" json:"height"`
+	// Variants maps each variant name to its URL
+	Variants map[string]string `bson:"variants" json:"variants"`
+	// Keys are the blob keys of the variants, used to delete them
+	Keys      []string           `bson:"keys" json:"-"`
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// ImageSwagger is a Swagger-friendly version of Image
+type ImageSwagger struct {
+	ID          string            `json:"id" example:"665f1c2e9b1e8a4d2c3b4a61"`
+	ContentType string            `json:"content_type" example:"image/jpeg"`
+	Width       int               `json:"width" example:"4032"`
+	Height      int               `json:"height" example:"3024"`
+	Variants    map[string]string `json:"variants"`
+	CreatedAt   string            `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// LeaseStatus is the state of a lease. A lease is active from the moment the
+// rental request is accepted until its end date has passed.
+type LeaseStatus string
+
+const (
+	LeaseActive     LeaseStatus = "active"
+	LeaseEnded      LeaseStatus = "ended"      // ran until its end date
+	LeaseTerminated LeaseStatus = "terminated" // ended early by the tenant or owner
+)
+
+// Valid reports whether s is one of the known statuses
+func (s LeaseStatus) Valid() bool {
+	switch s {
+	case LeaseActive, LeaseEnded, LeaseTerminated:
+		return true
+	}
+	return false
+}
+
+// LeaseEventType names an entry in a lease's history
+type LeaseEventType string
+
+const (
+	LeaseCreated         LeaseEventType = "created"
+	LeaseRenewalProposed LeaseEventType = "renewal_proposed"
+	LeaseRenewed         LeaseEventType = "renewed"
+	LeaseRenewalDeclined LeaseEventType = "renewal_declined"
+	LeaseTerminationSet  LeaseEventType = "termination_scheduled"
+	LeaseClosed          LeaseEventType = "closed"
+)
+
+// Lease is the agreement created when an owner accepts a rental request
+type Lease struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID      primitive.ObjectID `bson:"property_id" json:"property_id"`
+	RentalRequestID primitive.ObjectID `bson:"rental_request_id" json:"rental_request_id"`
+	OwnerEmail      string             `bson:"owner_email" json:"owner_email"`
+
+	TenantID    primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantEmail string             `bson:"tenant_email" json:"tenant_email"`
+	TenantName  string             `bson:"tenant_name" json:"tenant_name"`
+
+	StartDate   primitive.DateTime `bson:"start_date" json:"start_date"`
+	EndDate     primitive.DateTime `bson:"end_date" json:"end_date"`
+	MonthlyRent float64            `bson:"monthly_rent" json:"monthly_rent"`
+	Deposit     float64            `bson:"deposit" json:"deposit"`
+
+	Status         LeaseStatus       `bson:"status" json:"status"`
+	PendingRenewal *LeaseRenewal     `bson:"pending_renewal,omitempty" json:"pending_renewal,omitempty"`
+	Termination    *LeaseTermination `bson:"termination,omitempty" json:"termination,omitempty"`
+	History        []LeaseEvent      `bson:"history" json:"history"`
+
+	// Version is incremented on every update to detect concurrent changes
+	Version int64 `bson:"version" json:"-"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// LeaseRenewal is an extension offered by the owner and waiting for the tenant
+type LeaseRenewal struct {
+	EndDate     primitive.DateTime `bson:"end_date" json:"end_date"`
+	MonthlyRent float64            `bson:"monthly_rent" json:"monthly_rent"`
+	ProposedBy  string             `bson:"proposed_by" json:"proposed_by"`
+	ProposedAt  primitive.DateTime `bson:"proposed_at" json:"proposed_at"`
+}
+
+// LeaseTermination records an early end of the lease
+type LeaseTermination struct {
+	By          string             `bson:"by" json:"by"`
+	Reason      string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	RequestedAt primitive.DateTime `bson:"requested_at" json:"requested_at"`
+}
+
+// LeaseEvent is a single entry in a lease's history
+type LeaseEvent struct {
+	Type LeaseEventType     `bson:"type" json:"type"`
+	By   string             `bson:"by,omitempty" json:"by,omitempty"` // email of the user, empty for system events
+	Note string             `bson:"note,omitempty" json:"note,omitempty"`
+	At   primitive.DateTime `bson:"at" json:"at"`
+}
+
+// LeaseSwagger is a Swagger-friendly version of Lease
+type LeaseSwagger struct {
+	ID              string                   `json:"id" example:"665f1c2e8f1b2a3c4d5e6f73"`
+	PropertyID      string                   `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	RentalRequestID string                   `json:"rental_request_id" example:"665f1c2e8f1b2a3c4d5e6f70"`
+	OwnerEmail      string                   `json:"owner_email" example:"owner@example.com"`
+	TenantID        string                   `json:"tenant_id" example:"665f1c2e8f1b2a3c4d5e6f72"`
+	TenantEmail     string                   `json:"tenant_email" example:"tenant@example.com"`
+	TenantName      string                   `json:"tenant_name" example:"Alice Smith"`
+	StartDate       string                   `json:"start_date" example:"2025-07-01T00:00:00Z"`
+	EndDate         string                   `json:"end_date" example:"2026-07-01T00:00:00Z"`
+	MonthlyRent     float64                  `json:"monthly_rent" example:"2500"`
+	Deposit         float64                  `json:"deposit" example:"5000"`
+	Status          string                   `json:"status" example:"active" enums:"active,ended,terminated"`
+	PendingRenewal  *LeaseRenewalSwagger     `json:"pending_renewal,omitempty"`
+	Termination     *LeaseTerminationSwagger `json:"termination,omitempty"`
+	History         []LeaseEventSwagger      `json:"history"`
+	CreatedAt       string                   `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+	UpdatedAt       string                   `json:"updated_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// LeaseRenewalSwagger is a Swagger-friendly version of LeaseRenewal
+type LeaseRenewalSwagger struct {
+	EndDate     string  `json:"end_date" example:"2027-07-01T00:00:00Z"`
+	MonthlyRent float64 `json:"monthly_rent" example:"2600"`
+	ProposedBy  string  `json:"proposed_by" example:"owner@example.com"`
+	ProposedAt  string  `json:"proposed_at" example:"2026-05-01T10:00:00Z"`
+}
+
+// LeaseTerminationSwagger is a Swagger-friendly version of LeaseTermination
+type LeaseTerminationSwagger struct {
+	By          string `json:"by" example:"tenant@example.com"`
+	Reason      string `json:"reason,omitempty" example:"Moving abroad"`
+	RequestedAt string `json:"requested_at" example:"2026-01-10T10:00:00Z"`
+}
+
+// LeaseEventSwagger is a Swagger-friendly version of LeaseEvent
+type LeaseEventSwagger struct {
+	Type string `json:"type" example:"created" enums:"created,renewal_proposed,renewed,renewal_declined,termination_scheduled,closed"`
+	By   string `json:"by,omitempty" example:"owner@example.com"`
+	Note string `json:"note,omitempty"`
+	At   string `json:"at" example:"2025-06-01T10:00:00Z"`
+}
+
+// LeaseRenewalRequestSwagger is a Swagger-friendly version of the renewal proposal body
+type LeaseRenewalRequestSwagger struct {
+	EndDate     string  `json:"end_date" example:"2027-07-01"`
+	MonthlyRent float64 `json:"monthly_rent,omitempty" example:"2600"`
+}
+
+// LeaseTerminationRequestSwagger is a Swagger-friendly version of the termination body
+type LeaseTerminationRequestSwagger struct {
+	EndDate string `json:"end_date" example:"2026-02-01"`
+	Reason  string `json:"reason,omitempty" example:"Moving abroad"`
+}
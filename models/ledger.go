@@ -0,0 +1,160 @@
+package models
+
+import (
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvoiceKind says what an invoice is charging for
+type InvoiceKind string
+
+const (
+	InvoiceRent    InvoiceKind = "rent"
+	InvoiceDeposit InvoiceKind = "deposit"
+	InvoiceLateFee InvoiceKind = "late_fee"
+)
+
+// InvoiceStatus is the payment state of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceOpen InvoiceStatus = "open"
+	InvoicePaid InvoiceStatus = "paid"
+)
+
+// Valid reports whether s is one of the known statuses
+func (s InvoiceStatus) Valid() bool {
+	return s == InvoiceOpen || s == InvoicePaid
+}
+
+// Invoice is an amount a tenant owes under a lease
+type Invoice struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LeaseID     primitive.ObjectID `bson:"lease_id" json:"lease_id"`
+	PropertyID  primitive.ObjectID `bson:"property_id" json:"property_id"`
+	OwnerEmail  string             `bson:"owner_email" json:"owner_email"`
+	TenantID    primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantEmail string             `bson:"tenant_email" json:"tenant_email"`
+
+	// Key is unique per lease and makes invoice generation idempotent
+	Key  string      `bson:"key" json:"-"`
+	Kind InvoiceKind `bson:"kind" json:"kind"`
+
+	// PeriodStart and PeriodEnd are set on rent invoices
+	PeriodStart primitive.DateTime `bson:"period_start,omitempty" json:"period_start,omitempty"`
+	PeriodEnd   primitive.DateTime `bson:"period_end,omitempty" json:"period_end,omitempty"`
+	DueDate     primitive.DateTime `bson:"due_date" json:"due_date"`
+
+	Amount     float64       `bson:"amount" json:"amount"`
+	AmountPaid float64       `bson:"amount_paid" json:"amount_paid"`
+	Status     InvoiceStatus `bson:"status" json:"status"`
+
+	// LateFeeApplied is set once a late fee has been charged for this invoice
+	LateFeeApplied bool `bson:"late_fee_applied,omitempty" json:"late_fee_applied,omitempty"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Outstanding returns the amount still owed
+func (i *Invoice) Outstanding() float64 {
+	return i.Amount - i.AmountPaid
+}
+
+// Settled reports whether the invoice has been paid in full, to the cent
+func (i *Invoice) Settled() bool {
+	return math.Round(i.AmountPaid*100) >= math.Round(i.Amount*100)
+}
+
+// Payment is money received against an invoice
+type Payment struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	InvoiceID   primitive.ObjectID `bson:"invoice_id" json:"invoice_id"`
+	LeaseID     primitive.ObjectID `bson:"lease_id" json:"lease_id"`
+	OwnerEmail  string             `bson:"owner_email" json:"owner_email"`
+	TenantID    primitive.ObjectID `bson:"tenant_id" json:"tenant_id"`
+	TenantEmail string             `bson:"tenant_email" json:"tenant_email"`
+
+	Amount float64 `bson:"amount" json:"amount"`
+	// Method is "manual" for payments recorded by the owner, or the name of the payment provider
+	Method string `bson:"method" json:"method"`
+	// Reference is the provider's charge ID
+	Reference  string             `bson:"reference,omitempty" json:"reference,omitempty"`
+	RecordedBy string             `bson:"recorded_by" json:"recorded_by"`
+	Note       string             `bson:"note,omitempty" json:"note,omitempty"`
+	PaidAt     primitive.DateTime `bson:"paid_at" json:"paid_at"`
+	CreatedAt  primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// Statement summarizes the money side of a lease
+type Statement struct {
+	LeaseID    primitive.ObjectID `json:"lease_id"`
+	PropertyID primitive.ObjectID `json:"property_id"`
+	Invoices   []Invoice          `json:"invoices"`
+	Payments   []Payment          `json:"payments"`
+
+	TotalInvoiced float64 `json:"total_invoiced"`
+	TotalPaid     float64 `json:"total_paid"`
+	// Balance is what the tenant still owes, including amounts not yet due
+	Balance float64 `json:"balance"`
+	// Overdue is the part of the balance that is past its due date
+	Overdue     float64 `json:"overdue"`
+	LateFees    float64 `json:"late_fees"`
+	DepositHeld float64 `json:"deposit_held"`
+}
+
+// InvoiceSwagger is a Swagger-friendly version of Invoice
+type InvoiceSwagger struct {
+	ID             string  `json:"id" example:"665f1c2e8f1b2a3c4d5e6f74"`
+	LeaseID        string  `json:"lease_id" example:"665f1c2e8f1b2a3c4d5e6f73"`
+	PropertyID     string  `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	OwnerEmail     string  `json:"owner_email" example:"owner@example.com"`
+	TenantID       string  `json:"tenant_id" example:"665f1c2e8f1b2a3c4d5e6f72"`
+	TenantEmail    string  `json:"tenant_email" example:"tenant@example.com"`
+	Kind           string  `json:"kind" example:"rent" enums:"rent,deposit,late_fee"`
+	PeriodStart    string  `json:"period_start,omitempty" example:"2025-07-01T00:00:00Z"`
+	PeriodEnd      string  `json:"period_end,omitempty" example:"2025-08-01T00:00:00Z"`
+	DueDate        string  `json:"due_date" example:"2025-07-01T00:00:00Z"`
+	Amount         float64 `json:"amount" example:"2500"`
+	AmountPaid     float64 `json:"amount_paid" example:"0"`
+	Status         string  `json:"status" example:"open" enums:"open,paid"`
+	LateFeeApplied bool    `json:"late_fee_applied,omitempty"`
+}
+
+// PaymentSwagger is a Swagger-friendly version of Payment
+type PaymentSwagger struct {
+	ID          string  `json:"id" example:"665f1c2e8f1b2a3c4d5e6f75"`
+	InvoiceID   string  `json:"invoice_id" example:"665f1c2e8f1b2a3c4d5e6f74"`
+	LeaseID     string  `json:"lease_id" example:"665f1c2e8f1b2a3c4d5e6f73"`
+	OwnerEmail  string  `json:"owner_email" example:"owner@example.com"`
+	TenantID    string  `json:"tenant_id" example:"665f1c2e8f1b2a3c4d5e6f72"`
+	TenantEmail string  `json:"tenant_email" example:"tenant@example.com"`
+	Amount      float64 `json:"amount" example:"2500"`
+	Method      string  `json:"method" example:"manual"`
+	Reference   string  `json:"reference,omitempty" example:"fake_ch_1"`
+	RecordedBy  string  `json:"recorded_by" example:"owner@example.com"`
+	Note        string  `json:"note,omitempty" example:"Bank transfer"`
+	PaidAt      string  `json:"paid_at" example:"2025-07-02T09:30:00Z"`
+}
+
+// StatementSwagger is a Swagger-friendly version of Statement
+type StatementSwagger struct {
+	LeaseID       string           `json:"lease_id" example:"665f1c2e8f1b2a3c4d5e6f73"`
+	PropertyID    string           `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	Invoices      []InvoiceSwagger `json:"invoices"`
+	Payments      []PaymentSwagger `json:"payments"`
+	TotalInvoiced float64          `json:"total_invoiced" example:"7500"`
+	TotalPaid     float64          `json:"total_paid" example:"5000"`
+	Balance       float64          `json:"balance" example:"2500"`
+	Overdue       float64          `json:"overdue" example:"0"`
+	LateFees      float64          `json:"late_fees" example:"0"`
+	DepositHeld   float64          `json:"deposit_held" example:"5000"`
+}
+
+// PaymentRequestSwagger is a Swagger-friendly version of the payment bodies
+type PaymentRequestSwagger struct {
+	Amount float64 `json:"amount,omitempty" example:"2500"`
+	PaidAt string  `json:"paid_at,omitempty" example:"2025-07-02"`
+	Note   string  `json:"note,omitempty" example:"Bank transfer"`
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationType tells what a notification is about
+type NotificationType string
+
+const (
+	// NotificationRentalRequest tells an owner about a new rental request
+	NotificationRentalRequest NotificationType = "rental_request"
+	// NotificationRequestAccepted and NotificationRequestRejected tell an
+	// applicant the owner decided on their rental request
+	NotificationRequestAccepted NotificationType = "rental_request_accepted"
+	NotificationRequestRejected NotificationType = "rental_request_rejected"
+	// NotificationPropertyLiked tells an owner someone liked their property
+	NotificationPropertyLiked NotificationType = "property_liked"
+	// NotificationViewingBooked tells an owner someone booked a viewing of
+	// their property, or moved theirs to another slot
+	NotificationViewingBooked NotificationType = "viewing_booked"
+	// NotificationViewingCancelled tells one party the other called off a viewing
+	NotificationViewingCancelled NotificationType = "viewing_cancelled"
+	// NotificationViewingReminder reminds both parties of an upcoming viewing
+	NotificationViewingReminder NotificationType = "viewing_reminder"
+)
+
+// NotificationTypes lists every notification type
+var NotificationTypes = []NotificationType{
+	NotificationRentalRequest,
+	NotificationRequestAccepted,
+	NotificationRequestRejected,
+	NotificationPropertyLiked,
+	NotificationViewingBooked,
+	NotificationViewingCancelled,
+	NotificationViewingReminder,
+}
+
+// Valid reports whether t is one of the known notification types
+func (t NotificationType) Valid() bool {
+	return slices.Contains(NotificationTypes, t)
+}
+
+// NotificationChannel is a way notifications are delivered
+type NotificationChannel string
+
+const (
+	ChannelInApp NotificationChannel = "in_app"
+	ChannelEmail NotificationChannel = "email"
+	ChannelPush  NotificationChannel = "push"
+)
+
+// NotificationChannels lists every channel
+var NotificationChannels = []NotificationChannel{ChannelInApp, ChannelEmail, ChannelPush}
+
+// Valid reports whether c is one of the known channels
+func (c NotificationChannel) Valid() bool {
+	return slices.Contains(NotificationChannels, c)
+}
+
+// Notification is an event delivered to a user. The ones delivered in the
+// app are kept in the user's notification feed.
+type Notification struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type   NotificationType   `bson:"type" json:"type"`
+	Title  string             `bson:"title" json:"title"`
+	Body   string             `bson:"body" json:"body"`
+
+	PropertyID      primitive.ObjectID  `bson:"property_id" json:"property_id"`
+	RentalRequestID *primitive.ObjectID `bson:"rental_request_id,omitempty" json:"rental_request_id,omitempty"`
+	ViewingID       *primitive.ObjectID `bson:"viewing_id,omitempty" json:"viewing_id,omitempty"`
+
+	// ReadAt is set once the user has seen the notification
+	ReadAt    *primitive.DateTime `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt primitive.DateTime  `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// NotificationPreferences lists, per notification type, the channels a user
+// turned off. Types missing from it are delivered on every channel.
+type NotificationPreferences map[NotificationType][]NotificationChannel
+
+// Allows reports whether notifications of type t may be delivered on channel c
+func (p NotificationPreferences) Allows(t NotificationType, c NotificationChannel) bool {
+	return !slices.Contains(p[t], c)
+}
+
+// Settings returns whether each type is delivered on each channel
+func (p NotificationPreferences) Settings() map[NotificationType]map[NotificationChannel]bool {
+	settings := map[NotificationType]map[NotificationChannel]bool{}
+	for _, t := range NotificationTypes {
+		settings[t] = map[NotificationChannel]bool{}
+		for _, c := range NotificationChannels {
+			settings[t][c] = p.Allows(t, c)
+		}
+	}
+	return settings
+}
+
+// Apply returns the preferences with the given settings changed. Types and
+// channels missing from settings keep their current setting.
+func (p NotificationPreferences) Apply(settings map[NotificationType]map[NotificationChannel]bool) NotificationPreferences {
+	current := p.Settings()
+	for t, channels := range settings {
+		for c, on := range channels {
+			current[t][c] = on
+		}
+	}
+
+	updated := NotificationPreferences{}
+	for _, t := range NotificationTypes {
+		for _, c := range NotificationChannels {
+			if !current[t][c] {
+				updated[t] = append(updated[t], c)
+			}
+		}
+	}
+	return updated
+}
+
+// NotificationSwagger is a Swagger-friendly version of Notification
+type NotificationSwagger struct {
+	ID              string `json:"id" example:"665f1c2e9b1e8a4d2c3b4a61"`
+	UserID          string `json:"user_id" example:"665f1c2e9b1e8a4d2c3b4a5e"`
+	Type            string `json:"type" example:"rental_request" enums:"rental_request,rental_request_accepted,rental_request_rejected,property_liked,viewing_booked,viewing_cancelled,viewing_reminder"`
+	Title           string `json:"title" example:"New rental request"`
+	Body            string `json:"body" example:"Alice Smith would like to rent Modern 2BHK Apartment."`
+	PropertyID      string `json:"property_id" example:"665f1c2e9b1e8a4d2c3b4a5f"`
+	RentalRequestID string `json:"rental_request_id,omitempty" example:"665f1c2e8f1b2a3c4d5e6f70"`
+	ViewingID       string `json:"viewing_id,omitempty" example:"665f1c2e8f1b2a3c4d5e6f91"`
+	ReadAt          string `json:"read_at,omitempty" example:"2025-06-02T08:00:00Z"`
+	CreatedAt       string `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// NotificationPreferencesSwagger is a Swagger-friendly version of the
+// notification settings: for each type, whether each channel is on
+type NotificationPreferencesSwagger map[string]map[string]bool
+
+// PushTokenSwagger is the body registering a device for push notifications
+type PushTokenSwagger struct {
+	Token string `json:"token" example:"fcm-device-token"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// Page is one page of a list. NextCursor is empty on the last page and
+// Total is only set when requested.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// PageSwagger is a Swagger-friendly version of Page
+type PageSwagger[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdCIsImQiOnRydWUsImlkIjoiNjY1ZjFjMmU5YjFlOGE0ZDJjM2I0YTVkIn0"`
+	Total      int64  `json:"total,omitempty" example:"42"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Property struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -8,23 +12,100 @@ type Property struct {
 	Description string             `bson:"description" json:"description"`
 	Price       float64            `bson:"price" json:"price"`
 	Location    string             `bson:"location" json:"location"`
+	// Coordinates pin the property on a map for nearby and map searches
+	Coordinates *GeoPoint `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
+
+	Attributes PropertyAttributes `bson:"attributes" json:"attributes"`
 
 	OwnerEmail string `bson:"owner_email" json:"owner_email"`
 	OwnerName  string `bson:"owner_name" json:"owner_name"`
 	OwnerPic   string `bson:"owner_pic" json:"owner_pic"`
 
-	IsRented       bool                 `bson:"is_rented" json:"is_rented"`
-	RentedByEmail  string               `bson:"rented_by_email,omitempty" json:"rented_by_email,omitempty"`
-	RentalRequests []primitive.ObjectID `bson:"rental_requests,omitempty" json:"rental_requests,omitempty"`
+	IsRented      bool   `bson:"is_rented" json:"is_rented"`
+	RentedByEmail string `bson:"rented_by_email,omitempty" json:"rented_by_email,omitempty"`
 
 	Thumbnail string   `bson:"thumbnail,omitempty" json:"thumbnail,omitempty"`
 	Pictures  []string `bson:"pictures,omitempty" json:"pictures,omitempty"`
+	// Images are the pictures uploaded to the API. Their large variants are
+	// listed in Pictures too and the first one's thumbnail is the default
+	// Thumbnail.
+	Images []Image `bson:"images,omitempty" json:"images,omitempty"`
 
 	LikedBy   []string           `bson:"liked_by,omitempty" json:"liked_by,omitempty"`
 	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
 	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
 
+// AddImage appends an uploaded image, lists its large variant in Pictures
+// and makes its thumbnail the Thumbnail when the property has none
+func (p *Property) AddImage(img Image) {
+	p.Images = append(p.Images, img)
+	p.Pictures = append(p.Pictures, img.Variants[ImageLarge])
+	if p.Thumbnail == "" {
+		p.Thumbnail = img.Variants[ImageThumbnail]
+	}
+}
+
+// RemoveImage removes an uploaded image and its picture. A Thumbnail taken
+// from it falls back to the next image's. It reports whether the image was
+// found.
+func (p *Property) RemoveImage(id primitive.ObjectID) (Image, bool) {
+	i := slices.IndexFunc(p.Images, func(img Image) bool { return img.ID == id })
+	if i < 0 {
+		return Image{}, false
+	}
+	img := p.Images[i]
+	p.Images = slices.Delete(p.Images, i, i+1)
+	if j := slices.Index(p.Pictures, img.Variants[ImageLarge]); j >= 0 {
+		p.Pictures = slices.Delete(p.Pictures, j, j+1)
+	}
+	if p.Thumbnail == img.Variants[ImageThumbnail] {
+		p.Thumbnail = ""
+		if len(p.Images) > 0 {
+			p.Thumbnail = p.Images[min(i, len(p.Images)-1)].Variants[ImageThumbnail]
+		}
+	}
+	return img, true
+}
+
+// PropertyChange records an update of a listing for auditing
+type PropertyChange struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	PropertyID primitive.ObjectID `bson:"property_id" json:"property_id"`
+	By         string             `bson:"by" json:"by"`
+	// Changes lists every value that changed, attributes one by one
+	Changes []FieldChange      `bson:"changes" json:"changes"`
+	At      primitive.DateTime `bson:"at" json:"at"`
+}
+
+// FieldChange is a value of a listing before and after an update. Field is
+// its dotted JSON path, such as attributes.bedrooms; a missing value is null.
+type FieldChange struct {
+	Field string `bson:"field" json:"field"`
+	From  any    `bson:"from" json:"from"`
+	To    any    `bson:"to" json:"to"`
+}
+
+// PropertySearchResult is a property returned by a search
+type PropertySearchResult struct {
+	Property `bson:",inline"`
+
+	// Score is the text relevance, only set when searching with a query
+	Score float64 `bson:"score,omitempty" json:"score,omitempty"`
+	// Distance is in meters from the searched point, only set when searching around one
+	Distance *float64 `bson:"distance,omitempty" json:"distance,omitempty"`
+	// Highlights maps the fields that matched the query to their HTML-escaped
+	// text with the matching words wrapped in <em> tags
+	Highlights map[string]string `bson:"-" json:"highlights,omitempty"`
+}
+
+// PropertySearchPage is the response of a property search. Facets are only
+// set when requested.
+type PropertySearchPage struct {
+	Page[PropertySearchResult]
+	Facets *PropertyFacets `json:"facets,omitempty"`
+}
+
 // PropertySwagger is a Swagger-friendly version of Property
 type PropertySwagger struct {
 	Title       string  `json:"title" example:"Modern 2BHK Apartment"`
@@ -32,16 +113,65 @@ type PropertySwagger struct {
 	Price       float64 `json:"price" example:"2500"`
 	Location    string  `json:"location" example:"New York"`
 
+	Coordinates *GeoPointSwagger `json:"coordinates,omitempty"`
+
+	Attributes PropertyAttributesSwagger `json:"attributes"`
+
 	OwnerEmail string `json:"owner_email" example:"owner@example.com"`
 	OwnerName  string `json:"owner_name" example:"John Doe"`
 	OwnerPic   string `json:"owner_pic" example:"https://example.com/pic.jpg"`
 
-	RentedBy       []string `json:"rented_by,omitempty"`
-	RentalRequests []string `json:"rental_requests,omitempty"`
-	IsRented       bool     `json:"is_rented" example:"false"`
+	IsRented      bool   `json:"is_rented" example:"false"`
+	RentedByEmail string `json:"rented_by_email,omitempty" example:"tenant@example.com"`
 
-	Thumbnail string   `json:"thumbnail,omitempty"`
-	Pictures  []string `json:"pictures,omitempty"`
+	Thumbnail string         `json:"thumbnail,omitempty"`
+	Pictures  []string       `json:"pictures,omitempty"`
+	Images    []ImageSwagger `json:"images,omitempty"`
 
 	LikedBy []string `json:"liked_by,omitempty"`
 }
+
+// PropertySearchResultSwagger is a Swagger-friendly version of PropertySearchResult
+type PropertySearchResultSwagger struct {
+	PropertySwagger
+	Score      float64           `json:"score,omitempty" example:"11.5"`
+	Distance   float64           `json:"distance,omitempty" example:"1250.5"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// PropertySearchPageSwagger is a Swagger-friendly version of PropertySearchPage
+type PropertySearchPageSwagger struct {
+	Items      []PropertySearchResultSwagger `json:"items"`
+	NextCursor string                        `json:"next_cursor,omitempty"`
+	Total      int64                         `json:"total,omitempty" example:"42"`
+	Facets     *PropertyFacetsSwagger        `json:"facets,omitempty"`
+}
+
+// PropertyPatchSwagger is a JSON Merge Patch of a listing. Every field is
+// optional and null removes a value.
+type PropertyPatchSwagger struct {
+	Title       string                     `json:"title,omitempty" example:"Modern 2BHK Apartment"`
+	Description string                     `json:"description,omitempty" example:"Spacious apartment near downtown."`
+	Price       float64                    `json:"price,omitempty" example:"2600"`
+	Location    string                     `json:"location,omitempty" example:"New York"`
+	Coordinates *GeoPointSwagger           `json:"coordinates,omitempty"`
+	Attributes  *PropertyAttributesSwagger `json:"attributes,omitempty"`
+	Thumbnail   string                     `json:"thumbnail,omitempty"`
+	Pictures    []string                   `json:"pictures,omitempty"`
+}
+
+// FieldChangeSwagger is a Swagger-friendly version of FieldChange
+type FieldChangeSwagger struct {
+	Field string `json:"field" example:"price"`
+	From  any    `json:"from" swaggertype:"number" example:"2500"`
+	To    any    `json:"to" swaggertype:"number" example:"2600"`
+}
+
+// PropertyChangeSwagger is a Swagger-friendly version of PropertyChange
+type PropertyChangeSwagger struct {
+	ID         string               `json:"id" example:"665f1c2e9b1e8a4d2c3b4a80"`
+	PropertyID string               `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	By         string               `json:"by" example:"owner@example.com"`
+	Changes    []FieldChangeSwagger `json:"changes"`
+	At         string               `json:"at" example:"2025-06-01T10:00:00Z"`
+}
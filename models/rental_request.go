@@ -0,0 +1,108 @@
+package models
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RentalRequestStatus is the state of a rental request. Requests start out
+// pending and move to exactly one final state.
+type RentalRequestStatus string
+
+const (
+	RentalRequestPending   RentalRequestStatus = "pending"
+	RentalRequestAccepted  RentalRequestStatus = "accepted"
+	RentalRequestRejected  RentalRequestStatus = "rejected"
+	RentalRequestWithdrawn RentalRequestStatus = "withdrawn"
+	RentalRequestExpired   RentalRequestStatus = "expired"
+)
+
+// rentalRequestTransitions lists the states each state may move to
+var rentalRequestTransitions = map[RentalRequestStatus][]RentalRequestStatus{
+	RentalRequestPending: {RentalRequestAccepted, RentalRequestRejected, RentalRequestWithdrawn, RentalRequestExpired},
+}
+
+// Valid reports whether s is one of the known statuses
+func (s RentalRequestStatus) Valid() bool {
+	switch s {
+	case RentalRequestPending, RentalRequestAccepted, RentalRequestRejected, RentalRequestWithdrawn, RentalRequestExpired:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a request in state s may move to next
+func (s RentalRequestStatus) CanTransitionTo(next RentalRequestStatus) bool {
+	return slices.Contains(rentalRequestTransitions[s], next)
+}
+
+// RentalRequest is a tenant's application to rent a property. Requests are
+// never deleted; every status change is appended to History.
+type RentalRequest struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID primitive.ObjectID `bson:"property_id" json:"property_id"`
+	OwnerEmail string             `bson:"owner_email" json:"owner_email"`
+
+	ApplicantID    primitive.ObjectID `bson:"applicant_id" json:"applicant_id"`
+	ApplicantEmail string             `bson:"applicant_email" json:"applicant_email"`
+	ApplicantName  string             `bson:"applicant_name" json:"applicant_name"`
+
+	Message    string             `bson:"message,omitempty" json:"message,omitempty"`
+	MoveInDate primitive.DateTime `bson:"move_in_date,omitempty" json:"move_in_date,omitempty"`
+
+	Status  RentalRequestStatus `bson:"status" json:"status"`
+	History []StatusChange      `bson:"history" json:"history"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// StatusChange records a single transition of a rental request
+type StatusChange struct {
+	Status RentalRequestStatus `bson:"status" json:"status"`
+	By     string              `bson:"by,omitempty" json:"by,omitempty"` // email of the user, empty for system changes
+	Note   string              `bson:"note,omitempty" json:"note,omitempty"`
+	At     primitive.DateTime  `bson:"at" json:"at"`
+}
+
+// RentalRequestSwagger is a Swagger-friendly version of RentalRequest
+type RentalRequestSwagger struct {
+	ID             string                `json:"id" example:"665f1c2e8f1b2a3c4d5e6f70"`
+	PropertyID     string                `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	OwnerEmail     string                `json:"owner_email" example:"owner@example.com"`
+	ApplicantID    string                `json:"applicant_id" example:"665f1c2e8f1b2a3c4d5e6f72"`
+	ApplicantEmail string                `json:"applicant_email" example:"tenant@example.com"`
+	ApplicantName  string                `json:"applicant_name" example:"Alice Smith"`
+	Message        string                `json:"message,omitempty" example:"I'd love to move in next month."`
+	MoveInDate     string                `json:"move_in_date,omitempty" example:"2025-07-01T00:00:00Z"`
+	Status         string                `json:"status" example:"pending" enums:"pending,accepted,rejected,withdrawn,expired"`
+	History        []StatusChangeSwagger `json:"history"`
+	CreatedAt      string                `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+	UpdatedAt      string                `json:"updated_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// StatusChangeSwagger is a Swagger-friendly version of StatusChange
+type StatusChangeSwagger struct {
+	Status string `json:"status" example:"pending"`
+	By     string `json:"by,omitempty" example:"tenant@example.com"`
+	Note   string `json:"note,omitempty"`
+	At     string `json:"at" example:"2025-06-01T10:00:00Z"`
+}
+
+// RentalRequestCreateSwagger is a Swagger-friendly version of the create body
+type RentalRequestCreateSwagger struct {
+	PropertyID string `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	Message    string `json:"message,omitempty" example:"I'd love to move in next month."`
+	MoveInDate string `json:"move_in_date,omitempty" example:"2025-07-01"`
+}
+
+// RentalRequestDecisionSwagger is a Swagger-friendly version of the decision body
+type RentalRequestDecisionSwagger struct {
+	Decision    string  `json:"decision" example:"accept" enums:"accept,reject"`
+	Note        string  `json:"note,omitempty" example:"Welcome aboard!"`
+	StartDate   string  `json:"start_date,omitempty" example:"2025-07-01"`
+	EndDate     string  `json:"end_date,omitempty" example:"2026-07-01"`
+	MonthlyRent float64 `json:"monthly_rent,omitempty" example:"2500"`
+	Deposit     float64 `json:"deposit,omitempty" example:"5000"`
+}
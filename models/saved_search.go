@@ -0,0 +1,111 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// SearchFilters are the SearchProperties filters a saved search keeps
+type SearchFilters struct {
+	Query    string   `bson:"q,omitempty" json:"q,omitempty" validate:"max=200"`
+	Location string   `bson:"location,omitempty" json:"location,omitempty" validate:"max=100"`
+	MinPrice *float64 `bson:"min_price,omitempty" json:"min_price,omitempty" validate:"omitempty,min=0"`
+	MaxPrice *float64 `bson:"max_price,omitempty" json:"max_price,omitempty" validate:"omitempty,min=0"`
+
+	Types        []PropertyType `bson:"type,omitempty" json:"type,omitempty" validate:"dive,oneof=apartment house villa studio room"`
+	MinBedrooms  *int           `bson:"min_bedrooms,omitempty" json:"min_bedrooms,omitempty" validate:"omitempty,min=0"`
+	MaxBedrooms  *int           `bson:"max_bedrooms,omitempty" json:"max_bedrooms,omitempty" validate:"omitempty,min=0"`
+	MinBathrooms *int           `bson:"min_bathrooms,omitempty" json:"min_bathrooms,omitempty" validate:"omitempty,min=0"`
+	MinArea      *float64       `bson:"min_area,omitempty" json:"min_area,omitempty" validate:"omitempty,min=0"`
+	MaxArea      *float64       `bson:"max_area,omitempty" json:"max_area,omitempty" validate:"omitempty,min=0"`
+	Furnishing   []Furnishing   `bson:"furnishing,omitempty" json:"furnishing,omitempty" validate:"dive,oneof=unfurnished semi_furnished furnished"`
+	PetsAllowed  *bool          `bson:"pets_allowed,omitempty" json:"pets_allowed,omitempty"`
+	Amenities    []string       `bson:"amenities,omitempty" json:"amenities,omitempty" validate:"unique,dive,oneof=parking lift power_backup security gym pool garden balcony air_conditioning wifi laundry"`
+
+	// Box keeps the map area the search was saved from
+	Box *GeoBox `bson:"bbox,omitempty" json:"bbox,omitempty"`
+}
+
+// SavedSearch is a property search a user keeps to be alerted of new
+// listings matching it
+type SavedSearch struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	UserEmail string             `bson:"user_email" json:"user_email"`
+	Name      string             `bson:"name" json:"name"`
+	Filters   SearchFilters      `bson:"filters" json:"filters"`
+	// EmailAlerts sends alerts by email as well as to the in-app feed
+	EmailAlerts bool `bson:"email_alerts" json:"email_alerts"`
+
+	// CheckedUntil is the creation time of the newest listing already
+	// matched against the search. Listings created before the search was
+	// saved are never matched.
+	CheckedUntil primitive.DateTime `bson:"checked_until" json:"-"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Alert tells a user about a new listing matching one of their saved searches
+type Alert struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
+	SavedSearchID   primitive.ObjectID `bson:"saved_search_id" json:"saved_search_id"`
+	SavedSearchName string             `bson:"saved_search_name" json:"saved_search_name"`
+
+	PropertyID       primitive.ObjectID `bson:"property_id" json:"property_id"`
+	PropertyTitle    string             `bson:"property_title" json:"property_title"`
+	PropertyLocation string             `bson:"property_location" json:"property_location"`
+	PropertyPrice    float64            `bson:"property_price" json:"property_price"`
+
+	// ReadAt is set once the user has seen the alert
+	ReadAt    *primitive.DateTime `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt primitive.DateTime  `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// SearchFiltersSwagger is a Swagger-friendly version of SearchFilters
+type SearchFiltersSwagger struct {
+	Query        string         `json:"q,omitempty" example:"garden"`
+	Location     string         `json:"location,omitempty" example:"Pune"`
+	MinPrice     float64        `json:"min_price,omitempty" example:"1000"`
+	MaxPrice     float64        `json:"max_price,omitempty" example:"3000"`
+	Types        []string       `json:"type,omitempty" example:"apartment"`
+	MinBedrooms  int            `json:"min_bedrooms,omitempty" example:"2"`
+	MaxBedrooms  int            `json:"max_bedrooms,omitempty" example:"3"`
+	MinBathrooms int            `json:"min_bathrooms,omitempty" example:"1"`
+	MinArea      float64        `json:"min_area,omitempty" example:"800"`
+	MaxArea      float64        `json:"max_area,omitempty" example:"1500"`
+	Furnishing   []string       `json:"furnishing,omitempty" example:"furnished"`
+	PetsAllowed  bool           `json:"pets_allowed,omitempty" example:"true"`
+	Amenities    []string       `json:"amenities,omitempty" example:"parking"`
+	Box          *GeoBoxSwagger `json:"bbox,omitempty"`
+}
+
+// SavedSearchSwagger is a Swagger-friendly version of SavedSearch
+type SavedSearchSwagger struct {
+	ID          string               `json:"id" example:"665f1c2e9b1e8a4d2c3b4a5d"`
+	UserID      string               `json:"user_id" example:"665f1c2e9b1e8a4d2c3b4a5e"`
+	UserEmail   string               `json:"user_email" example:"tenant@example.com"`
+	Name        string               `json:"name" example:"2BHK in Pune"`
+	Filters     SearchFiltersSwagger `json:"filters"`
+	EmailAlerts bool                 `json:"email_alerts" example:"true"`
+	CreatedAt   string               `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// AlertSwagger is a Swagger-friendly version of Alert
+type AlertSwagger struct {
+	ID               string  `json:"id" example:"665f1c2e9b1e8a4d2c3b4a60"`
+	UserID           string  `json:"user_id" example:"665f1c2e9b1e8a4d2c3b4a5e"`
+	SavedSearchID    string  `json:"saved_search_id" example:"665f1c2e9b1e8a4d2c3b4a5d"`
+	SavedSearchName  string  `json:"saved_search_name" example:"2BHK in Pune"`
+	PropertyID       string  `json:"property_id" example:"665f1c2e9b1e8a4d2c3b4a5f"`
+	PropertyTitle    string  `json:"property_title" example:"Modern 2BHK Apartment"`
+	PropertyLocation string  `json:"property_location" example:"Pune"`
+	PropertyPrice    float64 `json:"property_price" example:"2500"`
+	ReadAt           string  `json:"read_at,omitempty" example:"2025-06-02T08:00:00Z"`
+	CreatedAt        string  `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// SavedSearchInputSwagger is the body of a new saved search
+type SavedSearchInputSwagger struct {
+	Name        string               `json:"name" example:"2BHK in Pune"`
+	Filters     SearchFiltersSwagger `json:"filters"`
+	EmailAlerts bool                 `json:"email_alerts" example:"true"`
+}
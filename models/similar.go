@@ -0,0 +1,29 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// SimilarProperties lists the listings most like a property, best first.
+// They are precomputed by a background job; see the similarity package.
+type SimilarProperties struct {
+	PropertyID primitive.ObjectID `bson:"_id" json:"property_id"`
+	Similar    []SimilarityScore  `bson:"similar" json:"similar"`
+	ComputedAt primitive.DateTime `bson:"computed_at" json:"computed_at"`
+}
+
+// SimilarityScore is how alike another listing is, from 0 to 1
+type SimilarityScore struct {
+	PropertyID primitive.ObjectID `bson:"property_id" json:"property_id"`
+	Score      float64            `bson:"score" json:"score"`
+}
+
+// SimilarProperty is a listing offered as an alternative to another
+type SimilarProperty struct {
+	Property   `bson:",inline"`
+	Similarity float64 `bson:"-" json:"similarity"`
+}
+
+// SimilarPropertySwagger is a Swagger-friendly version of SimilarProperty
+type SimilarPropertySwagger struct {
+	PropertySwagger
+	Similarity float64 `json:"similarity" example:"0.82"`
+}
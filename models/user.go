@@ -2,31 +2,134 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// Role determines what a user is allowed to do. See the policy package.
+type Role string
+
+const (
+	RoleTenant Role = "tenant"
+	RoleOwner  Role = "owner"
+	RoleAgent  Role = "agent"
+	RoleAdmin  Role = "admin"
+)
+
+// Valid reports whether r is one of the known roles
+func (r Role) Valid() bool {
+	switch r {
+	case RoleTenant, RoleOwner, RoleAgent, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID                 primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
 	Email              string               `bson:"email" json:"email"`
 	Name               string               `bson:"name" json:"name"`
+	Role               Role                 `bson:"role,omitempty" json:"role,omitempty"`
 	ProfilePic         string               `bson:"profile_pic,omitempty" json:"profile_pic,omitempty"`
+	ProfileImage       *Image               `bson:"profile_image,omitempty" json:"profile_image,omitempty"`
 	Location           string               `bson:"location,omitempty" json:"location,omitempty"`
+	Coordinates        *GeoPoint            `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
 	PreferredLocations []string             `bson:"preferred_locations,omitempty" json:"preferred_locations,omitempty"`
 	PostedProperties   []primitive.ObjectID `bson:"posted_properties,omitempty" json:"posted_properties,omitempty"`
 	LikedProperties    []primitive.ObjectID `bson:"liked_properties,omitempty" json:"liked_properties,omitempty"`
 	RentedProperties   []primitive.ObjectID `bson:"rented_properties,omitempty" json:"rented_properties,omitempty"`
-	RentalRequests     []primitive.ObjectID `bson:"rental_requests,omitempty" json:"rental_requests,omitempty"` // properties the user has requested
+
+	Credentials Credentials `bson:"credentials,omitempty" json:"-"`
+
+	NotificationPreferences NotificationPreferences `bson:"notification_preferences,omitempty" json:"-"`
+	// PushTokens identify the devices push notifications are sent to
+	PushTokens []string `bson:"push_tokens,omitempty" json:"-"`
 
 	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
 	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
 
+// PublicUser is what other users can see of a user
+type PublicUser struct {
+	ID               primitive.ObjectID   `json:"id"`
+	Email            string               `json:"email"`
+	Name             string               `json:"name"`
+	Role             Role                 `json:"role,omitempty"`
+	ProfilePic       string               `json:"profile_pic,omitempty"`
+	ProfileImage     *Image               `json:"profile_image,omitempty"`
+	PostedProperties []primitive.ObjectID `json:"posted_properties,omitempty"`
+}
+
+// Public returns the profile of the user other users can see, leaving out
+// where they are and what they like or rent
+func (u *User) Public() PublicUser {
+	return PublicUser{
+		ID:               u.ID,
+		Email:            u.Email,
+		Name:             u.Name,
+		Role:             u.Role,
+		ProfilePic:       u.ProfilePic,
+		ProfileImage:     u.ProfileImage,
+		PostedProperties: u.PostedProperties,
+	}
+}
+
+// Credentials holds the secrets used to authenticate a user. It is never sent to clients.
+type Credentials struct {
+	PasswordHash string `bson:"password_hash,omitempty"`
+
+	FailedAttempts int                `bson:"failed_attempts,omitempty"`
+	LockedUntil    primitive.DateTime `bson:"locked_until,omitempty"`
+
+	OTPHash      string             `bson:"otp_hash,omitempty"`
+	OTPExpiresAt primitive.DateTime `bson:"otp_expires_at,omitempty"`
+
+	ResetTokenHash string             `bson:"reset_token_hash,omitempty"`
+	ResetExpiresAt primitive.DateTime `bson:"reset_expires_at,omitempty"`
+
+	// TokenVersion is carried by the tokens issued to the user. Bumping it
+	// revokes every token issued before.
+	TokenVersion int `bson:"token_version,omitempty"`
+
+	// CalendarTokenHash authenticates the user's calendar feeds, which
+	// calendar apps fetch without an access token
+	CalendarTokenHash string `bson:"calendar_token_hash,omitempty"`
+}
+
 // UserSwagger is a Swagger-friendly version of User
 type UserSwagger struct {
-	Email              string   `json:"email" example:"user@example.com"`
-	Name               string   `json:"name" example:"Alice Smith"`
-	ProfilePic         string   `json:"profile_pic,omitempty"`
-	Location           string   `json:"location,omitempty"`
-	PreferredLocations []string `json:"preferred_locations,omitempty" example:"[\"Los Angeles\", \"New York\"]"`
-	PostedProperties   []string `json:"posted_properties,omitempty"`
-	LikedProperties    []string `json:"liked_properties,omitempty"`
-	RentedProperties   []string `json:"rented_properties,omitempty"`
-	RentalRequests     []string `json:"rental_requests,omitempty"` // properties the user has requested
+	Email              string           `json:"email" example:"user@example.com"`
+	Name               string           `json:"name" example:"Alice Smith"`
+	Role               string           `json:"role,omitempty" example:"tenant" enums:"tenant,owner,agent,admin"`
+	ProfilePic         string           `json:"profile_pic,omitempty"`
+	ProfileImage       *ImageSwagger    `json:"profile_image,omitempty"`
+	Location           string           `json:"location,omitempty"`
+	Coordinates        *GeoPointSwagger `json:"coordinates,omitempty"`
+	PreferredLocations []string         `json:"preferred_locations,omitempty" example:"[\"Los Angeles\", \"New York\"]"`
+	PostedProperties   []string         `json:"posted_properties,omitempty"`
+	LikedProperties    []string         `json:"liked_properties,omitempty"`
+	RentedProperties   []string         `json:"rented_properties,omitempty"`
+}
+
+// RegisterSwagger is a Swagger-friendly version of the registration body
+type RegisterSwagger struct {
+	Email              string           `json:"email" example:"user@example.com"`
+	Password           string           `json:"password" example:"correct-horse-battery"`
+	Name               string           `json:"name" example:"Alice Smith"`
+	Role               string           `json:"role,omitempty" example:"tenant" enums:"tenant,owner"`
+	ProfilePic         string           `json:"profile_pic,omitempty"`
+	Location           string           `json:"location,omitempty"`
+	Coordinates        *GeoPointSwagger `json:"coordinates,omitempty"`
+	PreferredLocations []string         `json:"preferred_locations,omitempty" example:"[\"Los Angeles\", \"New York\"]"`
+}
+
+// LocationUpdateSwagger is a Swagger-friendly version of the location update body
+type LocationUpdateSwagger struct {
+	Location    string           `json:"location" example:"Pune"`
+	Coordinates *GeoPointSwagger `json:"coordinates,omitempty"`
+}
+
+// AuthSwagger is a Swagger-friendly version of the token response
+type AuthSwagger struct {
+	User         UserSwagger `json:"user"`
+	AccessToken  string      `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string      `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string      `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64       `json:"expires_in" example:"900"`
 }
@@ -0,0 +1,138 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ViewingSlot is a time an owner is available to show a property. Each slot
+// takes a single viewing.
+type ViewingSlot struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID primitive.ObjectID `bson:"property_id" json:"property_id"`
+	OwnerEmail string             `bson:"owner_email" json:"owner_email"`
+
+	Start primitive.DateTime `bson:"start" json:"start"`
+	End   primitive.DateTime `bson:"end" json:"end"`
+
+	// ViewingID is the viewing booked in the slot, if any
+	ViewingID *primitive.ObjectID `bson:"viewing_id,omitempty" json:"viewing_id,omitempty"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// Booked reports whether someone booked the slot
+func (s *ViewingSlot) Booked() bool {
+	return s.ViewingID != nil
+}
+
+// ViewingStatus is the state of a viewing
+type ViewingStatus string
+
+const (
+	ViewingScheduled ViewingStatus = "scheduled"
+	ViewingCancelled ViewingStatus = "cancelled"
+)
+
+// Valid reports whether s is one of the known statuses
+func (s ViewingStatus) Valid() bool {
+	return s == ViewingScheduled || s == ViewingCancelled
+}
+
+// Viewing is a visit of a property booked in one of its slots. Rescheduling
+// moves the viewing to another slot of the same property.
+type Viewing struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID       primitive.ObjectID `bson:"property_id" json:"property_id"`
+	PropertyTitle    string             `bson:"property_title" json:"property_title"`
+	PropertyLocation string             `bson:"property_location" json:"property_location"`
+	OwnerEmail       string             `bson:"owner_email" json:"owner_email"`
+
+	VisitorID    primitive.ObjectID `bson:"visitor_id" json:"visitor_id"`
+	VisitorEmail string             `bson:"visitor_email" json:"visitor_email"`
+	VisitorName  string             `bson:"visitor_name" json:"visitor_name"`
+
+	SlotID primitive.ObjectID `bson:"slot_id" json:"slot_id"`
+	Start  primitive.DateTime `bson:"start" json:"start"`
+	End    primitive.DateTime `bson:"end" json:"end"`
+	Note   string             `bson:"note,omitempty" json:"note,omitempty"`
+
+	Status       ViewingStatus        `bson:"status" json:"status"`
+	Cancellation *ViewingCancellation `bson:"cancellation,omitempty" json:"cancellation,omitempty"`
+
+	// RemindedAt is set once both parties have been reminded of the viewing
+	RemindedAt *primitive.DateTime `bson:"reminded_at,omitempty" json:"-"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// ViewingCancellation records who called off a viewing
+type ViewingCancellation struct {
+	By     string             `bson:"by" json:"by"`
+	Reason string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	At     primitive.DateTime `bson:"at" json:"at"`
+}
+
+// ViewingSlotSwagger is a Swagger-friendly version of ViewingSlot
+type ViewingSlotSwagger struct {
+	ID         string `json:"id" example:"665f1c2e8f1b2a3c4d5e6f90"`
+	PropertyID string `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	OwnerEmail string `json:"owner_email" example:"owner@example.com"`
+	Start      string `json:"start" example:"2025-06-07T10:00:00Z"`
+	End        string `json:"end" example:"2025-06-07T10:30:00Z"`
+	ViewingID  string `json:"viewing_id,omitempty" example:"665f1c2e8f1b2a3c4d5e6f91"`
+	CreatedAt  string `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// ViewingSlotInputSwagger is the body of a new viewing slot
+type ViewingSlotInputSwagger struct {
+	Start string `json:"start" example:"2025-06-07T10:00:00Z"`
+	End   string `json:"end" example:"2025-06-07T10:30:00Z"`
+}
+
+// ViewingCancellationSwagger is a Swagger-friendly version of ViewingCancellation
+type ViewingCancellationSwagger struct {
+	By     string `json:"by" example:"tenant@example.com"`
+	Reason string `json:"reason,omitempty" example:"Found another place"`
+	At     string `json:"at" example:"2025-06-05T09:00:00Z"`
+}
+
+// ViewingSwagger is a Swagger-friendly version of Viewing
+type ViewingSwagger struct {
+	ID               string                      `json:"id" example:"665f1c2e8f1b2a3c4d5e6f91"`
+	PropertyID       string                      `json:"property_id" example:"665f1c2e8f1b2a3c4d5e6f71"`
+	PropertyTitle    string                      `json:"property_title" example:"Modern 2BHK Apartment"`
+	PropertyLocation string                      `json:"property_location" example:"Pune"`
+	OwnerEmail       string                      `json:"owner_email" example:"owner@example.com"`
+	VisitorID        string                      `json:"visitor_id" example:"665f1c2e8f1b2a3c4d5e6f72"`
+	VisitorEmail     string                      `json:"visitor_email" example:"tenant@example.com"`
+	VisitorName      string                      `json:"visitor_name" example:"Alice Smith"`
+	SlotID           string                      `json:"slot_id" example:"665f1c2e8f1b2a3c4d5e6f90"`
+	Start            string                      `json:"start" example:"2025-06-07T10:00:00Z"`
+	End              string                      `json:"end" example:"2025-06-07T10:30:00Z"`
+	Note             string                      `json:"note,omitempty" example:"I'll come with my partner."`
+	Status           string                      `json:"status" example:"scheduled" enums:"scheduled,cancelled"`
+	Cancellation     *ViewingCancellationSwagger `json:"cancellation,omitempty"`
+	CreatedAt        string                      `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+	UpdatedAt        string                      `json:"updated_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// ViewingInputSwagger is the body of a new viewing
+type ViewingInputSwagger struct {
+	SlotID string `json:"slot_id" example:"665f1c2e8f1b2a3c4d5e6f90"`
+	Note   string `json:"note,omitempty" example:"I'll come with my partner."`
+}
+
+// ViewingRescheduleSwagger is the body of a reschedule
+type ViewingRescheduleSwagger struct {
+	SlotID string `json:"slot_id" example:"665f1c2e8f1b2a3c4d5e6f92"`
+}
+
+// ViewingCancelSwagger is the body of a cancellation
+type ViewingCancelSwagger struct {
+	Reason string `json:"reason,omitempty" example:"Found another place"`
+}
+
+// CalendarFeedsSwagger lists the URLs of a user's calendar feeds
+type CalendarFeedsSwagger struct {
+	VisitorURL string `json:"visitor_url" example:"http://localhost:8080/api/calendar/3f2a.../viewings.ics?as=visitor"`
+	OwnerURL   string `json:"owner_url" example:"http://localhost:8080/api/calendar/3f2a.../viewings.ics?as=owner"`
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookEvent names something webhooks can subscribe to
+type WebhookEvent string
+
+const (
+	EventPropertyCreated WebhookEvent = "property.created"
+	EventPropertyUpdated WebhookEvent = "property.updated"
+	EventPropertyDeleted WebhookEvent = "property.deleted"
+
+	EventRentalRequestCreated   WebhookEvent = "rental_request.created"
+	EventRentalRequestAccepted  WebhookEvent = "rental_request.accepted"
+	EventRentalRequestRejected  WebhookEvent = "rental_request.rejected"
+	EventRentalRequestWithdrawn WebhookEvent = "rental_request.withdrawn"
+	EventRentalRequestExpired   WebhookEvent = "rental_request.expired"
+)
+
+// WebhookEvents lists every event
+var WebhookEvents = []WebhookEvent{
+	EventPropertyCreated,
+	EventPropertyUpdated,
+	EventPropertyDeleted,
+	EventRentalRequestCreated,
+	EventRentalRequestAccepted,
+	EventRentalRequestRejected,
+	EventRentalRequestWithdrawn,
+	EventRentalRequestExpired,
+}
+
+// Valid reports whether e is one of the known events
+func (e WebhookEvent) Valid() bool {
+	return slices.Contains(WebhookEvents, e)
+}
+
+// RentalRequestEvent returns the event raised when a rental request moves to
+// the status, or "" for statuses that raise none
+func RentalRequestEvent(status RentalRequestStatus) WebhookEvent {
+	switch status {
+	case RentalRequestPending:
+		return EventRentalRequestCreated
+	case RentalRequestAccepted:
+		return EventRentalRequestAccepted
+	case RentalRequestRejected:
+		return EventRentalRequestRejected
+	case RentalRequestWithdrawn:
+		return EventRentalRequestWithdrawn
+	case RentalRequestExpired:
+		return EventRentalRequestExpired
+	}
+	return ""
+}
+
+// Webhook is a partner's subscription to events, delivered by POSTing them
+// to URL signed with Secret
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL         string             `bson:"url" json:"url"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Events      []WebhookEvent     `bson:"events" json:"events"`
+	// Inactive webhooks receive no new events
+	Active bool `bson:"active" json:"active"`
+	// Secret is only sent to clients when the webhook is created
+	Secret    string             `bson:"secret" json:"secret,omitempty"`
+	CreatedBy string             `bson:"created_by" json:"created_by"`
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Subscribes reports whether the webhook receives the event
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	return w.Active && slices.Contains(w.Events, event)
+}
+
+// WebhookDeliveryStatus tracks a delivery through the queue
+type WebhookDeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are waiting for their next attempt
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// DeliveryFailed deliveries ran out of attempts
+	DeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// Valid reports whether s is one of the known statuses
+func (s WebhookDeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliverySucceeded, DeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for one webhook
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	// EventID is shared by the deliveries of an event to every webhook and
+	// by redeliveries, so receivers can tell duplicates apart
+	EventID primitive.ObjectID `bson:"event_id" json:"event_id"`
+	Event   WebhookEvent       `bson:"event" json:"event"`
+	// Payload is the exact body POSTed to the webhook
+	Payload string `bson:"payload" json:"payload"`
+	// RedeliveryOf is the delivery this one was manually created from
+	RedeliveryOf *primitive.ObjectID `bson:"redelivery_of,omitempty" json:"redelivery_of,omitempty"`
+
+	Status        WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts      []WebhookAttempt      `bson:"attempts" json:"attempts"`
+	NextAttemptAt *primitive.DateTime   `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+
+	CreatedAt primitive.DateTime `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// WebhookAttempt records one try at POSTing a delivery
+type WebhookAttempt struct {
+	At primitive.DateTime `bson:"at" json:"at"`
+	// StatusCode is the response status, or zero when no response came back
+	StatusCode int    `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Error      string `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMS int64  `bson:"duration_ms" json:"duration_ms"`
+}
+
+// WebhookSwagger is a Swagger-friendly version of Webhook
+type WebhookSwagger struct {
+	ID          string   `json:"id" example:"665f1c2e9b1e8a4d2c3b4a70"`
+	URL         string   `json:"url" example:"https://partner.example.com/dwello"`
+	Description string   `json:"description,omitempty" example:"Listings sync"`
+	Events      []string `json:"events" example:"property.created,property.updated"`
+	Active      bool     `json:"active" example:"true"`
+	Secret      string   `json:"secret,omitempty" example:"whsec_3f1c9a..."`
+	CreatedBy   string   `json:"created_by" example:"admin@example.com"`
+	CreatedAt   string   `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+	UpdatedAt   string   `json:"updated_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
+
+// WebhookInputSwagger is the body creating or updating a webhook
+type WebhookInputSwagger struct {
+	URL         string   `json:"url" example:"https://partner.example.com/dwello"`
+	Description string   `json:"description,omitempty" example:"Listings sync"`
+	Events      []string `json:"events" example:"property.created,property.updated"`
+	Active      *bool    `json:"active,omitempty" example:"true"`
+}
+
+// WebhookAttemptSwagger is a Swagger-friendly version of WebhookAttempt
+type WebhookAttemptSwagger struct {
+	At         string `json:"at" example:"2025-06-01T10:00:01Z"`
+	StatusCode int    `json:"status_code,omitempty" example:"500"`
+	Error      string `json:"error,omitempty" example:"unexpected response status 500"`
+	DurationMS int64  `json:"duration_ms" example:"132"`
+}
+
+// WebhookDeliverySwagger is a Swagger-friendly version of WebhookDelivery
+type WebhookDeliverySwagger struct {
+	ID            string                  `json:"id" example:"665f1c2e9b1e8a4d2c3b4a71"`
+	WebhookID     string                  `json:"webhook_id" example:"665f1c2e9b1e8a4d2c3b4a70"`
+	EventID       string                  `json:"event_id" example:"665f1c2e9b1e8a4d2c3b4a72"`
+	Event         string                  `json:"event" example:"property.created"`
+	Payload       string                  `json:"payload" example:"{\"id\":\"665f1c2e9b1e8a4d2c3b4a72\",\"type\":\"property.created\",\"created_at\":\"2025-06-01T10:00:00Z\",\"data\":{}}"`
+	RedeliveryOf  string                  `json:"redelivery_of,omitempty"`
+	Status        string                  `json:"status" example:"pending" enums:"pending,succeeded,failed"`
+	Attempts      []WebhookAttemptSwagger `json:"attempts"`
+	NextAttemptAt string                  `json:"next_attempt_at,omitempty" example:"2025-06-01T10:02:00Z"`
+	CreatedAt     string                  `json:"created_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"dwello-api/mailer"
+	"dwello-api/models"
+	"dwello-api/push"
+	"dwello-api/realtime"
+	"dwello-api/repository"
+)
+
+// EventNotification is the type of the realtime events carrying new notifications
+const EventNotification = "notification"
+
+// InApp keeps notifications in the user's feed and pushes them to the
+// user's open WebSocket connections
+type InApp struct {
+	notifications repository.NotificationRepository
+	hub           *realtime.Hub
+}
+
+func NewInApp(notifications repository.NotificationRepository, hub *realtime.Hub) *InApp {
+	return &InApp{notifications: notifications, hub: hub}
+}
+
+func (c *InApp) Name() models.NotificationChannel {
+	return models.ChannelInApp
+}
+
+func (c *InApp) Send(ctx context.Context, user *models.User, notification *models.Notification) error {
+	if err := c.notifications.Create(ctx, notification); err != nil {
+		return err
+	}
+	c.hub.Publish(user.ID, realtime.Event{Type: EventNotification, Data: notification})
+	return nil
+}
+
+// Email sends notifications with the mailer
+type Email struct {
+	mailer mailer.Mailer
+}
+
+func NewEmail(m mailer.Mailer) *Email {
+	return &Email{mailer: m}
+}
+
+func (c *Email) Name() models.NotificationChannel {
+	return models.ChannelEmail
+}
+
+func (c *Email) Send(ctx context.Context, user *models.User, notification *models.Notification) error {
+	return c.mailer.Send(ctx, user.Email, notification.Title, notification.Body)
+}
+
+// Push sends notifications to every device the user registered. Devices
+// that are no longer registered are forgotten.
+type Push struct {
+	users  repository.UserRepository
+	sender push.Sender
+}
+
+func NewPush(users repository.UserRepository, sender push.Sender) *Push {
+	return &Push{users: users, sender: sender}
+}
+
+func (c *Push) Name() models.NotificationChannel {
+	return models.ChannelPush
+}
+
+func (c *Push) Send(ctx context.Context, user *models.User, notification *models.Notification) error {
+	data := map[string]string{
+		"notification_id": notification.ID.Hex(),
+		"type":            string(notification.Type),
+		"property_id":     notification.PropertyID.Hex(),
+	}
+	if notification.RentalRequestID != nil {
+		data["rental_request_id"] = notification.RentalRequestID.Hex()
+	}
+
+	var errs []error
+	for _, token := range user.PushTokens {
+		err := c.sender.Send(ctx, push.Message{Token: token, Title: notification.Title, Body: notification.Body, Data: data})
+		if errors.Is(err, push.ErrUnregistered) {
+			err = c.users.RemovePushToken(ctx, user.ID, token)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
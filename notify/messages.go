@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"fmt"
+
+	"dwello-api/models"
+)
+
+// RentalRequested tells an owner about a new request for their property
+func RentalRequested(request *models.RentalRequest, property *models.Property) models.Notification {
+	return models.Notification{
+		Type:            models.NotificationRentalRequest,
+		Title:           "New rental request",
+		Body:            fmt.Sprintf("%s would like to rent %s.", request.ApplicantName, property.Title),
+		PropertyID:      property.ID,
+		RentalRequestID: &request.ID,
+	}
+}
+
+// RentalRequestDecided tells an applicant their request was accepted or
+// rejected, with the owner's note if any
+func RentalRequestDecided(request *models.RentalRequest, property *models.Property, note string) models.Notification {
+	notification := models.Notification{
+		Type:            models.NotificationRequestAccepted,
+		Title:           "Rental request accepted",
+		Body:            fmt.Sprintf("Your request to rent %s has been accepted.", property.Title),
+		PropertyID:      property.ID,
+		RentalRequestID: &request.ID,
+	}
+	if request.Status == models.RentalRequestRejected {
+		notification.Type = models.NotificationRequestRejected
+		notification.Title = "Rental request declined"
+		notification.Body = fmt.Sprintf("Your request to rent %s has been declined.", property.Title)
+	}
+	if note != "" {
+		notification.Body += " " + note
+	}
+	return notification
+}
+
+// PropertyLiked tells an owner someone liked their property
+func PropertyLiked(property *models.Property, by *models.User) models.Notification {
+	return models.Notification{
+		Type:       models.NotificationPropertyLiked,
+		Title:      "Someone liked your property",
+		Body:       fmt.Sprintf("%s liked %s.", by.Name, property.Title),
+		PropertyID: property.ID,
+	}
+}
+
+// ViewingBooked tells an owner someone booked a viewing of their property,
+// or moved theirs to another time when rescheduled is set
+func ViewingBooked(viewing *models.Viewing, rescheduled bool) models.Notification {
+	notification := models.Notification{
+		Type:       models.NotificationViewingBooked,
+		Title:      "New viewing booked",
+		Body:       fmt.Sprintf("%s booked a viewing of %s on %s.", viewing.VisitorName, viewing.PropertyTitle, viewingTime(viewing)),
+		PropertyID: viewing.PropertyID,
+		ViewingID:  &viewing.ID,
+	}
+	if rescheduled {
+		notification.Title = "Viewing rescheduled"
+		notification.Body = fmt.Sprintf("%s moved their viewing of %s to %s.", viewing.VisitorName, viewing.PropertyTitle, viewingTime(viewing))
+	}
+	return notification
+}
+
+// ViewingCancelled tells one party the other called off a viewing, with the
+// reason given if any
+func ViewingCancelled(viewing *models.Viewing) models.Notification {
+	notification := models.Notification{
+		Type:       models.NotificationViewingCancelled,
+		Title:      "Viewing cancelled",
+		Body:       fmt.Sprintf("The viewing of %s on %s has been cancelled.", viewing.PropertyTitle, viewingTime(viewing)),
+		PropertyID: viewing.PropertyID,
+		ViewingID:  &viewing.ID,
+	}
+	if viewing.Cancellation != nil && viewing.Cancellation.Reason != "" {
+		notification.Body += " " + viewing.Cancellation.Reason
+	}
+	return notification
+}
+
+// ViewingReminder reminds the visitor, or the owner when forOwner is set, of
+// an upcoming viewing
+func ViewingReminder(viewing *models.Viewing, forOwner bool) models.Notification {
+	body := fmt.Sprintf("Your viewing of %s in %s is on %s.", viewing.PropertyTitle, viewing.PropertyLocation, viewingTime(viewing))
+	if forOwner {
+		body = fmt.Sprintf("%s is viewing %s on %s.", viewing.VisitorName, viewing.PropertyTitle, viewingTime(viewing))
+	}
+	return models.Notification{
+		Type:       models.NotificationViewingReminder,
+		Title:      "Upcoming viewing",
+		Body:       body,
+		PropertyID: viewing.PropertyID,
+		ViewingID:  &viewing.ID,
+	}
+}
+
+// viewingTime formats the start of a viewing. Users have no time zone yet,
+// so it is given in UTC.
+func viewingTime(viewing *models.Viewing) string {
+	return viewing.Start.Time().UTC().Format("Mon 2 Jan 2006 at 15:04 UTC")
+}
@@ -0,0 +1,68 @@
+// Package notify delivers notifications to users on the channels their
+// preferences allow: in the app, by email and by push.
+package notify
+
+import (
+	"context"
+	"log"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Channel delivers notifications one way
+type Channel interface {
+	Name() models.NotificationChannel
+	Send(ctx context.Context, user *models.User, notification *models.Notification) error
+}
+
+// Dispatcher delivers the notifications raised by the handlers
+type Dispatcher struct {
+	users    repository.UserRepository
+	channels []Channel
+}
+
+func NewDispatcher(users repository.UserRepository, channels ...Channel) *Dispatcher {
+	return &Dispatcher{users: users, channels: channels}
+}
+
+// Notify delivers the notification to the user on every channel their
+// preferences allow. Failures are logged rather than returned: the action
+// that raised the notification has already succeeded.
+func (d *Dispatcher) Notify(ctx context.Context, user *models.User, notification models.Notification) {
+	notification.ID = primitive.NewObjectID()
+	notification.UserID = user.ID
+	notification.CreatedAt = primitive.NewDateTimeFromTime(utils.Now())
+
+	for _, channel := range d.channels {
+		if !user.NotificationPreferences.Allows(notification.Type, channel.Name()) {
+			continue
+		}
+		if err := channel.Send(ctx, user, &notification); err != nil {
+			log.Printf("Failed to send %s notification %s to %s: %v", channel.Name(), notification.ID.Hex(), user.Email, err)
+		}
+	}
+}
+
+// NotifyID looks up the user with the given ID and notifies them
+func (d *Dispatcher) NotifyID(ctx context.Context, userID primitive.ObjectID, notification models.Notification) {
+	user, err := d.users.FindByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to find user %s to notify: %v", userID.Hex(), err)
+		return
+	}
+	d.Notify(ctx, user, notification)
+}
+
+// NotifyEmail looks up the user with the given email and notifies them
+func (d *Dispatcher) NotifyEmail(ctx context.Context, email string, notification models.Notification) {
+	user, err := d.users.FindByEmail(ctx, email)
+	if err != nil {
+		log.Printf("Failed to find user %s to notify: %v", email, err)
+		return
+	}
+	d.Notify(ctx, user, notification)
+}
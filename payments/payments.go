@@ -0,0 +1,104 @@
+// Package payments abstracts the payment provider used to charge tenants.
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDeclined is returned when the provider refuses a charge
+var ErrDeclined = errors.New("payment declined")
+
+// Charge describes money to collect from a tenant
+type Charge struct {
+	Amount      float64
+	Description string
+	// Reference identifies what is being paid for, such as an invoice ID
+	Reference  string
+	PayerEmail string
+}
+
+// Receipt is returned for a successful charge
+type Receipt struct {
+	ID string
+}
+
+// Provider collects payments. Implementations must be safe for concurrent use.
+type Provider interface {
+	// Name is stored as the payment method of recorded payments
+	Name() string
+	Charge(ctx context.Context, charge Charge) (Receipt, error)
+	// Refund reverses a successful charge
+	Refund(ctx context.Context, receiptID string) error
+}
+
+// Providers that New knows
+const (
+	ProviderFake = "fake"
+)
+
+// New returns the provider with the given name. It returns nil when name is
+// empty, which turns online payments off.
+func New(name string) (Provider, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case ProviderFake:
+		return NewFakeProvider(), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", name)
+}
+
+// maxFakeCharges is how many charges FakeProvider remembers for refunds
+const maxFakeCharges = 1000
+
+// FakeProvider accepts every charge without moving any money. Useful for
+// local development and tests; set Decline to simulate refused payments.
+// Only the latest charges can be refunded.
+type FakeProvider struct {
+	Decline bool
+
+	mu      sync.Mutex
+	seq     int
+	charges map[string]Charge
+	order   []string
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{charges: map[string]Charge{}}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Charge(_ context.Context, charge Charge) (Receipt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Decline || charge.Amount <= 0 {
+		return Receipt{}, ErrDeclined
+	}
+	p.seq++
+	id := fmt.Sprintf("fake_ch_%d", p.seq)
+	p.charges[id] = charge
+	p.order = append(p.order, id)
+	if len(p.order) > maxFakeCharges {
+		delete(p.charges, p.order[0])
+		p.order = p.order[1:]
+	}
+	return Receipt{ID: id}, nil
+}
+
+func (p *FakeProvider) Refund(_ context.Context, receiptID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.charges[receiptID]; !ok {
+		return fmt.Errorf("unknown charge %q", receiptID)
+	}
+	delete(p.charges, receiptID)
+	return nil
+}
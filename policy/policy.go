@@ -0,0 +1,247 @@
+// Package policy centralizes authorization decisions. Handlers ask the policy
+// whether a user may perform an action instead of comparing emails inline.
+package policy
+
+import (
+	"slices"
+
+	"dwello-api/models"
+)
+
+// Permission is a coarse-grained capability granted to a role.
+type Permission string
+
+const (
+	// PermCreateProperty allows publishing new listings
+	PermCreateProperty Permission = "property:create"
+	// PermManageAnyProperty allows updating and deleting any listing and
+	// deciding its rental requests, regardless of ownership
+	PermManageAnyProperty Permission = "property:manage_any"
+	// PermRequestRental allows sending rental requests
+	PermRequestRental Permission = "rental:request"
+	// PermManageUsers allows listing users and changing their roles
+	PermManageUsers Permission = "users:manage"
+	// PermManageWebhooks allows managing partner webhooks and their deliveries
+	PermManageWebhooks Permission = "webhooks:manage"
+)
+
+var rolePermissions = map[models.Role][]Permission{
+	models.RoleTenant: {PermRequestRental},
+	models.RoleOwner:  {PermCreateProperty, PermRequestRental},
+	models.RoleAgent:  {PermCreateProperty, PermRequestRental},
+	models.RoleAdmin:  {PermCreateProperty, PermRequestRental, PermManageAnyProperty, PermManageUsers, PermManageWebhooks},
+}
+
+// RoleOf returns the user's role. Accounts created before roles existed have
+// no role stored; they are owners if they have posted anything, tenants otherwise.
+func RoleOf(user *models.User) models.Role {
+	if user.Role != "" {
+		return user.Role
+	}
+	if len(user.PostedProperties) > 0 {
+		return models.RoleOwner
+	}
+	return models.RoleTenant
+}
+
+// Has reports whether the user's role grants the permission.
+func Has(user *models.User, perm Permission) bool {
+	if user == nil {
+		return false
+	}
+	return slices.Contains(rolePermissions[RoleOf(user)], perm)
+}
+
+// IsSelfAssignable reports whether users may pick the role themselves, at
+// registration or later. Agent and admin roles are granted by an admin.
+func IsSelfAssignable(role models.Role) bool {
+	return role == models.RoleTenant || role == models.RoleOwner
+}
+
+// CanCreateProperty reports whether the user may publish a listing.
+func CanCreateProperty(user *models.User) bool {
+	return Has(user, PermCreateProperty)
+}
+
+// CanManageProperty reports whether the user may update or delete the property.
+func CanManageProperty(user *models.User, property *models.Property) bool {
+	if user == nil || property == nil {
+		return false
+	}
+	return property.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanRequestRental reports whether the user may send a rental request for the property.
+// Owners cannot request their own listings and rented properties take no new requests.
+func CanRequestRental(user *models.User, property *models.Property) bool {
+	if user == nil || property == nil {
+		return false
+	}
+	return Has(user, PermRequestRental) && property.OwnerEmail != user.Email && !property.IsRented
+}
+
+// CanDecideRentalRequest reports whether the user may accept or reject rental
+// requests for the property.
+func CanDecideRentalRequest(user *models.User, property *models.Property) bool {
+	return CanManageProperty(user, property)
+}
+
+// CanViewRentalRequest reports whether the user may see the rental request.
+// Only the applicant, the property owner and admins can.
+func CanViewRentalRequest(user *models.User, request *models.RentalRequest) bool {
+	if user == nil || request == nil {
+		return false
+	}
+	return request.ApplicantID == user.ID || request.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanWithdrawRentalRequest reports whether the user may withdraw the rental request.
+func CanWithdrawRentalRequest(user *models.User, request *models.RentalRequest) bool {
+	if user == nil || request == nil {
+		return false
+	}
+	return request.ApplicantID == user.ID
+}
+
+// CanViewLease reports whether the user may see the lease. Only the tenant,
+// the property owner and admins can.
+func CanViewLease(user *models.User, lease *models.Lease) bool {
+	if user == nil || lease == nil {
+		return false
+	}
+	return lease.TenantID == user.ID || lease.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanProposeRenewal reports whether the user may offer the tenant a renewal.
+func CanProposeRenewal(user *models.User, lease *models.Lease) bool {
+	if user == nil || lease == nil {
+		return false
+	}
+	return lease.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanRespondToRenewal reports whether the user may accept or decline a pending renewal.
+func CanRespondToRenewal(user *models.User, lease *models.Lease) bool {
+	if user == nil || lease == nil {
+		return false
+	}
+	return lease.TenantID == user.ID
+}
+
+// CanTerminateLease reports whether the user may end the lease early.
+// Either party can, as can admins.
+func CanTerminateLease(user *models.User, lease *models.Lease) bool {
+	return CanViewLease(user, lease)
+}
+
+// CanViewInvoice reports whether the user may see the invoice and its payments.
+// Only the tenant, the property owner and admins can.
+func CanViewInvoice(user *models.User, invoice *models.Invoice) bool {
+	if user == nil || invoice == nil {
+		return false
+	}
+	return invoice.TenantID == user.ID || invoice.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanRecordPayment reports whether the user may record a payment received
+// outside the app, such as a bank transfer.
+func CanRecordPayment(user *models.User, invoice *models.Invoice) bool {
+	if user == nil || invoice == nil {
+		return false
+	}
+	return invoice.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanPayInvoice reports whether the user may pay the invoice through the payment provider.
+func CanPayInvoice(user *models.User, invoice *models.Invoice) bool {
+	if user == nil || invoice == nil {
+		return false
+	}
+	return invoice.TenantID == user.ID
+}
+
+// CanManageSavedSearch reports whether the user may see or delete the saved search.
+func CanManageSavedSearch(user *models.User, search *models.SavedSearch) bool {
+	if user == nil || search == nil {
+		return false
+	}
+	return search.UserID == user.ID
+}
+
+// CanViewAlert reports whether the user may see the alert and mark it read.
+func CanViewAlert(user *models.User, alert *models.Alert) bool {
+	if user == nil || alert == nil {
+		return false
+	}
+	return alert.UserID == user.ID
+}
+
+// CanManageUsers reports whether the user may list users and change roles.
+func CanManageUsers(user *models.User) bool {
+	return Has(user, PermManageUsers)
+}
+
+// CanStartConversation reports whether the user may message the owner of the
+// property. Owners cannot message themselves about their own listings.
+func CanStartConversation(user *models.User, property *models.Property) bool {
+	if user == nil || property == nil {
+		return false
+	}
+	return property.OwnerEmail != user.Email
+}
+
+// CanViewConversation reports whether the user takes part in the conversation.
+func CanViewConversation(user *models.User, conversation *models.Conversation) bool {
+	if user == nil || conversation == nil {
+		return false
+	}
+	return conversation.IsParticipant(user.ID)
+}
+
+// CanViewNotification reports whether the user may see the notification and mark it read.
+func CanViewNotification(user *models.User, notification *models.Notification) bool {
+	if user == nil || notification == nil {
+		return false
+	}
+	return notification.UserID == user.ID
+}
+
+// CanManageViewingSlots reports whether the user may publish or remove
+// viewing slots of the property.
+func CanManageViewingSlots(user *models.User, property *models.Property) bool {
+	return CanManageProperty(user, property)
+}
+
+// CanBookViewing reports whether the user may book a viewing of the property.
+// Owners cannot book viewings of their own listings and rented properties
+// take no new viewings.
+func CanBookViewing(user *models.User, property *models.Property) bool {
+	if user == nil || property == nil {
+		return false
+	}
+	return property.OwnerEmail != user.Email && !property.IsRented
+}
+
+// CanViewViewing reports whether the user may see the viewing. Only the
+// visitor, the property owner and admins can.
+func CanViewViewing(user *models.User, viewing *models.Viewing) bool {
+	if user == nil || viewing == nil {
+		return false
+	}
+	return viewing.VisitorID == user.ID || viewing.OwnerEmail == user.Email || Has(user, PermManageAnyProperty)
+}
+
+// CanRescheduleViewing reports whether the user may move the viewing to
+// another slot. Only the visitor can.
+func CanRescheduleViewing(user *models.User, viewing *models.Viewing) bool {
+	if user == nil || viewing == nil {
+		return false
+	}
+	return viewing.VisitorID == user.ID
+}
+
+// CanCancelViewing reports whether the user may call off the viewing.
+// Either party can, as can admins.
+func CanCancelViewing(user *models.User, viewing *models.Viewing) bool {
+	return CanViewViewing(user, viewing)
+}
@@ -0,0 +1,234 @@
+package policy
+
+import (
+	"testing"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Users of each role, the owner being the one who posted the listings below
+var (
+	tenant = &models.User{ID: primitive.NewObjectID(), Email: "tenant@example.com", Role: models.RoleTenant}
+	owner  = &models.User{ID: primitive.NewObjectID(), Email: "owner@example.com", Role: models.RoleOwner}
+	agent  = &models.User{ID: primitive.NewObjectID(), Email: "agent@example.com", Role: models.RoleAgent}
+	admin  = &models.User{ID: primitive.NewObjectID(), Email: "admin@example.com", Role: models.RoleAdmin}
+)
+
+func TestRoleOf(t *testing.T) {
+	tests := []struct {
+		name string
+		user *models.User
+		want models.Role
+	}{
+		{"stored role", agent, models.RoleAgent},
+		{"legacy account that posted", &models.User{PostedProperties: []primitive.ObjectID{primitive.NewObjectID()}}, models.RoleOwner},
+		{"legacy account that never posted", &models.User{}, models.RoleTenant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoleOf(tt.user); got != tt.want {
+				t.Errorf("RoleOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHas(t *testing.T) {
+	tests := []struct {
+		user *models.User
+		perm Permission
+		want bool
+	}{
+		{tenant, PermRequestRental, true},
+		{tenant, PermCreateProperty, false},
+		{owner, PermCreateProperty, true},
+		{owner, PermManageAnyProperty, false},
+		{agent, PermCreateProperty, true},
+		{agent, PermManageUsers, false},
+		{admin, PermManageAnyProperty, true},
+		{admin, PermManageUsers, true},
+		{admin, PermManageWebhooks, true},
+		{nil, PermRequestRental, false},
+	}
+	for _, tt := range tests {
+		name := "nil"
+		if tt.user != nil {
+			name = string(tt.user.Role)
+		}
+		t.Run(name+" "+string(tt.perm), func(t *testing.T) {
+			if got := Has(tt.user, tt.perm); got != tt.want {
+				t.Errorf("Has() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSelfAssignable(t *testing.T) {
+	tests := []struct {
+		role models.Role
+		want bool
+	}{
+		{models.RoleTenant, true},
+		{models.RoleOwner, true},
+		{models.RoleAgent, false},
+		{models.RoleAdmin, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsSelfAssignable(tt.role); got != tt.want {
+			t.Errorf("IsSelfAssignable(%q) = %v, want %v", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestPropertyPolicies(t *testing.T) {
+	listing := &models.Property{OwnerEmail: owner.Email}
+	rented := &models.Property{OwnerEmail: owner.Email, IsRented: true}
+
+	tests := []struct {
+		name     string
+		can      func(*models.User, *models.Property) bool
+		user     *models.User
+		property *models.Property
+		want     bool
+	}{
+		{"owner manages their listing", CanManageProperty, owner, listing, true},
+		{"admin manages any listing", CanManageProperty, admin, listing, true},
+		{"agent does not manage others' listings", CanManageProperty, agent, listing, false},
+		{"tenant does not manage listings", CanManageProperty, tenant, listing, false},
+		{"nobody manages a missing listing", CanManageProperty, owner, nil, false},
+		{"tenant requests a rental", CanRequestRental, tenant, listing, true},
+		{"owner does not request their own listing", CanRequestRental, owner, listing, false},
+		{"nobody requests a rented listing", CanRequestRental, tenant, rented, false},
+		{"owner decides requests", CanDecideRentalRequest, owner, listing, true},
+		{"tenant does not decide requests", CanDecideRentalRequest, tenant, listing, false},
+		{"tenant messages the owner", CanStartConversation, tenant, listing, true},
+		{"owner does not message themselves", CanStartConversation, owner, listing, false},
+		{"owner manages viewing slots", CanManageViewingSlots, owner, listing, true},
+		{"tenant books a viewing", CanBookViewing, tenant, listing, true},
+		{"owner does not book their own listing", CanBookViewing, owner, listing, false},
+		{"nobody books a rented listing", CanBookViewing, tenant, rented, false},
+		{"anonymous users can do nothing", CanBookViewing, nil, listing, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.can(tt.user, tt.property); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRentalRequestPolicies(t *testing.T) {
+	request := &models.RentalRequest{ApplicantID: tenant.ID, OwnerEmail: owner.Email}
+	other := &models.User{ID: primitive.NewObjectID(), Email: "other@example.com", Role: models.RoleTenant}
+
+	tests := []struct {
+		name string
+		can  func(*models.User, *models.RentalRequest) bool
+		user *models.User
+		want bool
+	}{
+		{"applicant views", CanViewRentalRequest, tenant, true},
+		{"owner views", CanViewRentalRequest, owner, true},
+		{"admin views", CanViewRentalRequest, admin, true},
+		{"another tenant does not view", CanViewRentalRequest, other, false},
+		{"applicant withdraws", CanWithdrawRentalRequest, tenant, true},
+		{"owner does not withdraw", CanWithdrawRentalRequest, owner, false},
+		{"admin does not withdraw", CanWithdrawRentalRequest, admin, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.can(tt.user, request); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeasePolicies(t *testing.T) {
+	lease := &models.Lease{TenantID: tenant.ID, OwnerEmail: owner.Email}
+
+	tests := []struct {
+		name string
+		can  func(*models.User, *models.Lease) bool
+		user *models.User
+		want bool
+	}{
+		{"tenant views", CanViewLease, tenant, true},
+		{"owner views", CanViewLease, owner, true},
+		{"admin views", CanViewLease, admin, true},
+		{"agent does not view", CanViewLease, agent, false},
+		{"owner proposes a renewal", CanProposeRenewal, owner, true},
+		{"tenant does not propose a renewal", CanProposeRenewal, tenant, false},
+		{"tenant responds to a renewal", CanRespondToRenewal, tenant, true},
+		{"owner does not respond to a renewal", CanRespondToRenewal, owner, false},
+		{"tenant terminates", CanTerminateLease, tenant, true},
+		{"owner terminates", CanTerminateLease, owner, true},
+		{"agent does not terminate", CanTerminateLease, agent, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.can(tt.user, lease); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvoicePolicies(t *testing.T) {
+	invoice := &models.Invoice{TenantID: tenant.ID, OwnerEmail: owner.Email}
+
+	tests := []struct {
+		name string
+		can  func(*models.User, *models.Invoice) bool
+		user *models.User
+		want bool
+	}{
+		{"tenant views", CanViewInvoice, tenant, true},
+		{"owner views", CanViewInvoice, owner, true},
+		{"admin views", CanViewInvoice, admin, true},
+		{"agent does not view", CanViewInvoice, agent, false},
+		{"owner records a payment", CanRecordPayment, owner, true},
+		{"admin records a payment", CanRecordPayment, admin, true},
+		{"tenant does not record a payment", CanRecordPayment, tenant, false},
+		{"tenant pays", CanPayInvoice, tenant, true},
+		{"owner does not pay", CanPayInvoice, owner, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.can(tt.user, invoice); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewingPolicies(t *testing.T) {
+	viewing := &models.Viewing{VisitorID: tenant.ID, OwnerEmail: owner.Email}
+
+	tests := []struct {
+		name string
+		can  func(*models.User, *models.Viewing) bool
+		user *models.User
+		want bool
+	}{
+		{"visitor views", CanViewViewing, tenant, true},
+		{"owner views", CanViewViewing, owner, true},
+		{"agent does not view", CanViewViewing, agent, false},
+		{"visitor reschedules", CanRescheduleViewing, tenant, true},
+		{"owner does not reschedule", CanRescheduleViewing, owner, false},
+		{"owner cancels", CanCancelViewing, owner, true},
+		{"admin cancels", CanCancelViewing, admin, true},
+		{"agent does not cancel", CanCancelViewing, agent, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.can(tt.user, viewing); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Package problem defines the errors handlers return and renders them as
+// RFC 7807 problem details. Every error response carries a stable code that
+// clients can switch on instead of parsing the message.
+package problem
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentType is the media type of error responses
+const ContentType = "application/problem+json"
+
+// Codes shared by every error of a kind. Errors that clients need to tell
+// apart from others of the same status get a code of their own.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidation           = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeLocked               = "locked"
+	CodeUpgradeRequired      = "upgrade_required"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "service_unavailable"
+)
+
+// Codes of specific errors
+const (
+	CodeMissingToken        = "missing_token"
+	CodeInvalidToken        = "invalid_token"
+	CodeInvalidCredentials  = "invalid_credentials"
+	CodeInvalidCode         = "invalid_code"
+	CodeInvalidResetToken   = "invalid_reset_token"
+	CodeInvalidRefreshToken = "invalid_refresh_token"
+	CodeAccountLocked       = "account_locked"
+	CodeConcurrentUpdate    = "concurrent_update"
+	CodePaymentDeclined     = "payment_declined"
+	CodePaymentUnavailable  = "payment_provider_unavailable"
+)
+
+// codes are the codes used for each status when none is given
+var codes = map[int]string{
+	fiber.StatusBadRequest:            CodeBadRequest,
+	fiber.StatusUnauthorized:          CodeUnauthorized,
+	fiber.StatusForbidden:             CodeForbidden,
+	fiber.StatusNotFound:              CodeNotFound,
+	fiber.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	fiber.StatusConflict:              CodeConflict,
+	fiber.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	fiber.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	fiber.StatusLocked:                CodeLocked,
+	fiber.StatusUpgradeRequired:       CodeUpgradeRequired,
+	fiber.StatusTooManyRequests:       CodeTooManyRequests,
+	fiber.StatusInternalServerError:   CodeInternal,
+	fiber.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// FieldError tells which field of the request is invalid and why
+type FieldError struct {
+	Field   string `json:"field" example:"price"`
+	Message string `json:"message" example:"must be greater than 0"`
+}
+
+// Error is an error a handler responds with. Detail is shown to the client;
+// Err, the underlying cause, is only logged.
+type Error struct {
+	Status int
+	Code   string
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Detail + ": " + e.Err.Error()
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode returns a copy of the error with a specific code
+func (e *Error) WithCode(code string) *Error {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// New returns an error with the status and the code of its kind
+func New(status int, detail string) *Error {
+	code, ok := codes[status]
+	if !ok {
+		code = CodeBadRequest
+		if status >= 500 {
+			code = CodeInternal
+		}
+	}
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+// BadRequest is a request that cannot be understood, such as a malformed
+// body or ID
+func BadRequest(detail string) *Error {
+	return New(fiber.StatusBadRequest, detail)
+}
+
+// Validation is a well-formed request with invalid values. fields lists
+// the invalid fields when they are known.
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Status: fiber.StatusBadRequest, Code: CodeValidation, Detail: detail, Fields: fields}
+}
+
+func Unauthorized(detail string) *Error {
+	return New(fiber.StatusUnauthorized, detail)
+}
+
+func Forbidden(detail string) *Error {
+	return New(fiber.StatusForbidden, detail)
+}
+
+func NotFound(detail string) *Error {
+	return New(fiber.StatusNotFound, detail)
+}
+
+func Conflict(detail string) *Error {
+	return New(fiber.StatusConflict, detail)
+}
+
+// Internal is a failure of the server. err is logged and never shown.
+func Internal(detail string, err error) *Error {
+	e := New(fiber.StatusInternalServerError, detail)
+	e.Err = err
+	return e
+}
+
+// Problem is the body of every error response
+type Problem struct {
+	// Type is always about:blank, the code tells problems apart
+	Type     string       `json:"type" example:"about:blank"`
+	Title    string       `json:"title" example:"Not Found"`
+	Status   int          `json:"status" example:"404"`
+	Detail   string       `json:"detail" example:"Property not found"`
+	Instance string       `json:"instance" example:"/api/properties/665f1c2e8f1b2a3c4d5e6f71"`
+	Code     string       `json:"code" example:"not_found"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Handler is the Fiber error handler. It renders the errors returned by
+// handlers and middleware, including Fiber's own such as unknown routes.
+// Any other error is a 500 whose cause is logged but not shown.
+func Handler(c *fiber.Ctx, err error) error {
+	var e *Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &e):
+	case errors.As(err, &fiberErr):
+		e = New(fiberErr.Code, fiberErr.Message)
+	default:
+		e = Internal("Something went wrong", err)
+	}
+	if e.Status >= fiber.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Method(), c.Path(), e)
+	}
+
+	return c.Status(e.Status).JSON(Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Detail,
+		Instance: c.Path(),
+		Code:     e.Code,
+		Errors:   e.Fields,
+	}, ContentType)
+}
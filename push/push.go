@@ -0,0 +1,92 @@
+// Package push sends notifications to users' mobile devices.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+)
+
+// ErrUnregistered is returned for device tokens that can no longer receive
+// messages, such as after the app was uninstalled. They should be forgotten.
+var ErrUnregistered = errors.New("device token is not registered")
+
+// Message is a notification sent to one device
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	// Data is handed to the app along with the notification
+	Data map[string]string
+}
+
+// Sender delivers messages to devices, typically through Firebase Cloud
+// Messaging. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Senders that New knows
+const (
+	SenderFake = "fake"
+)
+
+// New returns the sender with the given name. It returns nil when name is
+// empty, which turns push notifications off.
+func New(name string) (Sender, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case SenderFake:
+		return NewFakeSender(), nil
+	}
+	return nil, fmt.Errorf("unknown push sender %q", name)
+}
+
+// maxFakeSent is how many messages FakeSender keeps
+const maxFakeSent = 100
+
+// FakeSender stands in for Firebase Cloud Messaging: it logs messages
+// instead of sending them and keeps the latest for inspection. Useful for
+// local development and tests; Unregister simulates uninstalled apps.
+type FakeSender struct {
+	mu           sync.Mutex
+	sent         []Message
+	unregistered map[string]bool
+}
+
+func NewFakeSender() *FakeSender {
+	return &FakeSender{unregistered: map[string]bool{}}
+}
+
+func (s *FakeSender) Send(_ context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unregistered[message.Token] {
+		return ErrUnregistered
+	}
+	s.sent = append(s.sent, message)
+	if len(s.sent) > maxFakeSent {
+		s.sent = slices.Delete(s.sent, 0, len(s.sent)-maxFakeSent)
+	}
+	log.Printf("Push to %s: %s - %s", message.Token, message.Title, message.Body)
+	return nil
+}
+
+// Unregister makes later messages to the token fail with ErrUnregistered
+func (s *FakeSender) Unregister(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unregistered[token] = true
+}
+
+// Sent returns the latest messages sent
+func (s *FakeSender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
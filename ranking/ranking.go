@@ -0,0 +1,188 @@
+// Package ranking orders the homescreen listings for a user. Listings are
+// scored on how well they fit the user's locations and the prices of the
+// listings they liked, then on how new and how popular they are. Users the
+// app knows nothing about yet get trending listings instead.
+package ranking
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxCandidates is how many of the newest listings are ranked, plus as many
+// in the user's locations
+const MaxCandidates = 500
+
+// Score weights. A listing in a preferred location at the price the user
+// usually likes outranks a brand new, popular one elsewhere.
+const (
+	PreferredLocationWeight = 3
+	CurrentLocationWeight   = 2
+	PriceWeight             = 2
+	RecencyWeight           = 1.5
+	PopularityWeight        = 1
+)
+
+// RecencyHalfLife is the age at which a listing's recency score halves
+const RecencyHalfLife = 14 * 24 * time.Hour
+
+// trendingGravity sets how fast likes stop counting as listings age
+const trendingGravity = 1.5
+
+// Ranked is a listing with its homescreen score
+type Ranked struct {
+	models.Property
+	Score float64 `json:"-"`
+}
+
+// Homescreen returns the listings available to the user, best first. Rented
+// listings and the user's own are left out. Listings are aged from the start
+// of the current hour so pages fetched within it are scored alike.
+func Homescreen(ctx context.Context, properties repository.PropertyRepository, user *models.User, now time.Time) ([]Ranked, error) {
+	now = now.Truncate(time.Hour)
+	search := repository.PropertySearch{Available: true, NotOwnedBy: user.Email}
+	newest := repository.Page{Sort: repository.SortCreatedAt, Desc: true, Limit: MaxCandidates}
+
+	results, err := properties.Search(ctx, search, newest)
+	if err != nil {
+		return nil, err
+	}
+	// Older listings in the user's locations would not make the newest ones
+	if locations := userLocations(user); len(locations) > 0 {
+		search.Locations = locations
+		local, err := properties.Search(ctx, search, newest)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, local...)
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(results))
+	candidates := make([]models.Property, 0, len(results))
+	for _, result := range results {
+		if !seen[result.ID] {
+			seen[result.ID] = true
+			candidates = append(candidates, result.Property)
+		}
+	}
+
+	var liked []models.Property
+	if len(user.LikedProperties) > 0 {
+		results, err := properties.Search(ctx, repository.PropertySearch{IDs: user.LikedProperties}, repository.Page{})
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			liked = append(liked, result.Property)
+		}
+	}
+	return Rank(user, candidates, liked, now), nil
+}
+
+// Rank scores the candidates for the user and sorts them best first, newest
+// first on equal scores. liked are the listings the user liked.
+func Rank(user *models.User, candidates, liked []models.Property, now time.Time) []Ranked {
+	maxLikes := 0
+	for _, p := range candidates {
+		maxLikes = max(maxLikes, len(p.LikedBy))
+	}
+	likedPrice := medianPrice(liked)
+	coldStart := len(user.PreferredLocations) == 0 && user.Location == "" && len(liked) == 0
+
+	ranked := make([]Ranked, len(candidates))
+	for i, p := range candidates {
+		ranked[i].Property = p
+		if coldStart {
+			ranked[i].Score = trending(&p, now)
+			continue
+		}
+
+		score := 0.0
+		if slices.ContainsFunc(user.PreferredLocations, func(l string) bool { return inLocation(p.Location, l) }) {
+			score += PreferredLocationWeight
+		}
+		if inLocation(p.Location, user.Location) {
+			score += CurrentLocationWeight
+		}
+		score += PriceWeight * priceAffinity(p.Price, likedPrice)
+		score += RecencyWeight * recency(&p, now)
+		if maxLikes > 0 {
+			score += PopularityWeight * math.Log1p(float64(len(p.LikedBy))) / math.Log1p(float64(maxLikes))
+		}
+		ranked[i].Score = score
+	}
+
+	slices.SortFunc(ranked, func(a, b Ranked) int {
+		if order := cmp.Compare(b.Score, a.Score); order != 0 {
+			return order
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+	return ranked
+}
+
+// userLocations returns the preferred and current locations of the user
+func userLocations(user *models.User) []string {
+	locations := slices.Clone(user.PreferredLocations)
+	if user.Location != "" && !slices.Contains(locations, user.Location) {
+		locations = append(locations, user.Location)
+	}
+	return locations
+}
+
+// inLocation reports whether a listing's location falls in the given one,
+// so "Baner, Pune" is in "Pune"
+func inLocation(listing, location string) bool {
+	return location != "" && strings.Contains(strings.ToLower(listing), strings.ToLower(location))
+}
+
+// medianPrice returns the median price of the listings, or zero when none
+// has a price
+func medianPrice(properties []models.Property) float64 {
+	var prices []float64
+	for _, p := range properties {
+		if p.Price > 0 {
+			prices = append(prices, p.Price)
+		}
+	}
+	if len(prices) == 0 {
+		return 0
+	}
+	slices.Sort(prices)
+	mid := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[mid-1] + prices[mid]) / 2
+	}
+	return prices[mid]
+}
+
+// priceAffinity is 1 for a listing at the price the user usually likes and
+// falls with the ratio between the two prices
+func priceAffinity(price, liked float64) float64 {
+	if price <= 0 || liked <= 0 {
+		return 0
+	}
+	return min(price/liked, liked/price)
+}
+
+// recency is 1 for a listing created now and halves every RecencyHalfLife
+func recency(p *models.Property, now time.Time) float64 {
+	age := max(now.Sub(p.CreatedAt.Time()), 0)
+	return math.Exp2(-float64(age) / float64(RecencyHalfLife))
+}
+
+// trending scores a listing by its likes, discounted by its age in days
+func trending(p *models.Property, now time.Time) float64 {
+	days := max(now.Sub(p.CreatedAt.Time()), 0).Hours() / 24
+	return float64(len(p.LikedBy)+1) / math.Pow(days+2, trendingGravity)
+}
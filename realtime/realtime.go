@@ -0,0 +1,94 @@
+// Package realtime pushes events to users connected over WebSocket.
+// Subscriptions live in process memory, so events only reach users
+// connected to the same API instance.
+package realtime
+
+import (
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// subscriptionBuffer is how many events may wait for a slow connection
+// before it is dropped
+const subscriptionBuffer = 32
+
+// Event is a message pushed to a user
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Hub delivers events to the subscriptions of each user. A user may be
+// subscribed several times, once per connected device.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[primitive.ObjectID]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: map[primitive.ObjectID]map[*Subscription]struct{}{}}
+}
+
+// Subscription receives the events published to a user until it is
+// unsubscribed
+type Subscription struct {
+	userID primitive.ObjectID
+	events chan Event
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription ends, including when the subscriber falls too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Subscribe starts delivering the user's events
+func (h *Hub) Subscribe(userID primitive.ObjectID) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{userID: userID, events: make(chan Event, subscriptionBuffer)}
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[*Subscription]struct{}{}
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops delivering events to the subscription. It is safe to
+// call more than once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// Publish delivers the event to every subscription of the user without
+// waiting. Subscriptions whose buffer is full are dropped so the client
+// reconnects and catches up through the REST endpoints.
+func (h *Hub) Publish(userID primitive.ObjectID, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[userID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// remove ends the subscription. The caller must hold mu.
+func (h *Hub) remove(sub *Subscription) {
+	subs := h.subs[sub.userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.events)
+}
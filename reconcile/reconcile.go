@@ -0,0 +1,157 @@
+// Package reconcile detects and repairs drift between the two sides of the
+// user/property relations: liked_by and liked_properties, and posted_properties.
+// Property documents are treated as the source of truth because every
+// two-sided write updates the property first.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Issue is a single inconsistency. Repair is nil when it cannot be fixed automatically.
+type Issue struct {
+	Relation    string
+	Description string
+	Repair      func(ctx context.Context) error
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("[%s] %s", i.Relation, i.Description)
+}
+
+// Report lists the issues found by Run and how many of them were repaired
+type Report struct {
+	Issues   []Issue
+	Repaired int
+	Failed   int
+}
+
+// Run scans every user and property. When fix is true each repairable issue is repaired.
+func Run(ctx context.Context, store repository.Store, fix bool) (Report, error) {
+	users, err := store.Users.List(ctx, repository.UserFilter{}, repository.Page{})
+	if err != nil {
+		return Report{}, err
+	}
+	properties, err := store.Properties.All(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	c := checker{store: store, users: users, properties: properties}
+	c.index()
+	c.checkLikes()
+	c.checkPostedProperties()
+
+	report := Report{Issues: c.issues}
+	if !fix {
+		return report, nil
+	}
+	for _, issue := range report.Issues {
+		if issue.Repair == nil {
+			continue
+		}
+		if err := issue.Repair(ctx); err != nil {
+			log.Println("Repair failed:", issue, err)
+			report.Failed++
+			continue
+		}
+		report.Repaired++
+	}
+	return report, nil
+}
+
+type checker struct {
+	store      repository.Store
+	users      []models.User
+	properties []models.Property
+
+	usersByID      map[primitive.ObjectID]*models.User
+	usersByEmail   map[string]*models.User
+	propertiesByID map[primitive.ObjectID]*models.Property
+	issues         []Issue
+}
+
+func (c *checker) index() {
+	c.usersByID = make(map[primitive.ObjectID]*models.User, len(c.users))
+	c.usersByEmail = make(map[string]*models.User, len(c.users))
+	for i := range c.users {
+		c.usersByID[c.users[i].ID] = &c.users[i]
+		c.usersByEmail[c.users[i].Email] = &c.users[i]
+	}
+	c.propertiesByID = make(map[primitive.ObjectID]*models.Property, len(c.properties))
+	for i := range c.properties {
+		c.propertiesByID[c.properties[i].ID] = &c.properties[i]
+	}
+}
+
+func (c *checker) report(relation string, repair func(ctx context.Context) error, format string, args ...any) {
+	c.issues = append(c.issues, Issue{Relation: relation, Description: fmt.Sprintf(format, args...), Repair: repair})
+}
+
+func (c *checker) checkLikes() {
+	const relation = "likes"
+	users, properties := c.store.Users, c.store.Properties
+
+	for _, p := range c.properties {
+		for _, email := range p.LikedBy {
+			user, ok := c.usersByEmail[email]
+			if !ok {
+				c.report(relation, func(ctx context.Context) error { return properties.RemoveLike(ctx, p.ID, email) },
+					"property %s is liked by unknown user %s", p.ID.Hex(), email)
+				continue
+			}
+			if !slices.Contains(user.LikedProperties, p.ID) {
+				c.report(relation, func(ctx context.Context) error { return users.AddLike(ctx, user.ID, p.ID) },
+					"user %s is missing liked property %s", user.Email, p.ID.Hex())
+			}
+		}
+	}
+
+	for _, u := range c.users {
+		for _, propertyID := range u.LikedProperties {
+			property, ok := c.propertiesByID[propertyID]
+			if ok && slices.Contains(property.LikedBy, u.Email) {
+				continue
+			}
+			c.report(relation, func(ctx context.Context) error { return users.RemoveLike(ctx, u.ID, propertyID) },
+				"user %s likes %s but the property does not list them", u.Email, propertyID.Hex())
+		}
+	}
+}
+
+func (c *checker) checkPostedProperties() {
+	const relation = "posted_properties"
+	users := c.store.Users
+
+	for _, p := range c.properties {
+		owner, ok := c.usersByEmail[p.OwnerEmail]
+		if !ok {
+			// Nothing to attach the listing to, an admin has to decide what to do with it
+			c.report(relation, nil, "property %s belongs to unknown owner %s", p.ID.Hex(), p.OwnerEmail)
+			continue
+		}
+		if !slices.Contains(owner.PostedProperties, p.ID) {
+			c.report(relation, func(ctx context.Context) error { return users.AddPostedProperty(ctx, owner.ID, p.ID) },
+				"user %s is missing posted property %s", owner.Email, p.ID.Hex())
+		}
+	}
+
+	for _, u := range c.users {
+		for _, propertyID := range u.PostedProperties {
+			property, ok := c.propertiesByID[propertyID]
+			if ok && property.OwnerEmail == u.Email {
+				continue
+			}
+			c.report(relation, func(ctx context.Context) error { return users.RemovePostedProperty(ctx, u.ID, propertyID) },
+				"user %s lists posted property %s that they do not own", u.Email, propertyID.Hex())
+		}
+	}
+}
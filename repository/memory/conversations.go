@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ConversationRepository struct {
+	mu            sync.RWMutex
+	conversations map[primitive.ObjectID]*models.Conversation
+}
+
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{conversations: map[primitive.ObjectID]*models.Conversation{}}
+}
+
+func (r *ConversationRepository) Create(_ context.Context, conversation *models.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.conversations {
+		if existing.PropertyID == conversation.PropertyID && existing.TenantID == conversation.TenantID {
+			return repository.ErrDuplicate
+		}
+	}
+	if conversation.ID.IsZero() {
+		conversation.ID = primitive.NewObjectID()
+	}
+	c := *conversation
+	r.conversations[c.ID] = &c
+	return nil
+}
+
+func (r *ConversationRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversation, ok := r.conversations[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	c := *conversation
+	return &c, nil
+}
+
+func (r *ConversationRepository) FindByPropertyAndTenant(_ context.Context, propertyID, tenantID primitive.ObjectID) (*models.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, conversation := range r.conversations {
+		if conversation.PropertyID == propertyID && conversation.TenantID == tenantID {
+			c := *conversation
+			return &c, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *ConversationRepository) List(_ context.Context, filter repository.ConversationFilter, page repository.Page) ([]models.Conversation, error) {
+	conversations := r.list(filter)
+	slices.Reverse(conversations) // newest first
+	return paginate(conversations, page, func(c *models.Conversation) (float64, primitive.ObjectID) { return repository.ByCreation(c.ID) }), nil
+}
+
+func (r *ConversationRepository) Count(_ context.Context, filter repository.ConversationFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *ConversationRepository) list(filter repository.ConversationFilter) []models.Conversation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversations := []models.Conversation{}
+	for _, id := range sortedIDs(r.conversations) {
+		conversation := r.conversations[id]
+		if !filter.UserID.IsZero() && !conversation.IsParticipant(filter.UserID) {
+			continue
+		}
+		if !filter.PropertyID.IsZero() && conversation.PropertyID != filter.PropertyID {
+			continue
+		}
+		conversations = append(conversations, *conversation)
+	}
+	return conversations
+}
+
+func (r *ConversationRepository) RecordMessage(_ context.Context, message *models.Message, preview string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conversation, ok := r.conversations[message.ConversationID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	conversation.LastMessage = preview
+	conversation.LastMessageAt = message.CreatedAt
+	conversation.UpdatedAt = message.CreatedAt
+	markRead(conversation, message.SenderID, message.CreatedAt)
+	return nil
+}
+
+func (r *ConversationRepository) MarkRead(_ context.Context, id, userID primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conversation, ok := r.conversations[id]
+	if !ok || !conversation.IsParticipant(userID) {
+		return repository.ErrNotFound
+	}
+	markRead(conversation, userID, primitive.NewDateTimeFromTime(at))
+	return nil
+}
+
+// markRead moves the participant's read receipt forward to at
+func markRead(conversation *models.Conversation, userID primitive.ObjectID, at primitive.DateTime) {
+	if userID == conversation.OwnerID {
+		conversation.OwnerReadAt = max(conversation.OwnerReadAt, at)
+	} else {
+		conversation.TenantReadAt = max(conversation.TenantReadAt, at)
+	}
+}
+
+type MessageRepository struct {
+	mu       sync.RWMutex
+	messages map[primitive.ObjectID]*models.Message
+}
+
+func NewMessageRepository() *MessageRepository {
+	return &MessageRepository{messages: map[primitive.ObjectID]*models.Message{}}
+}
+
+func (r *MessageRepository) Create(_ context.Context, message *models.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.messages[message.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	m := *message
+	r.messages[m.ID] = &m
+	return nil
+}
+
+func (r *MessageRepository) List(_ context.Context, filter repository.MessageFilter, page repository.Page) ([]models.Message, error) {
+	messages := r.list(filter)
+	slices.Reverse(messages) // newest first
+	return paginate(messages, page, func(m *models.Message) (float64, primitive.ObjectID) { return repository.ByCreation(m.ID) }), nil
+}
+
+func (r *MessageRepository) Count(_ context.Context, filter repository.MessageFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *MessageRepository) list(filter repository.MessageFilter) []models.Message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sentAfter := primitive.NewDateTimeFromTime(filter.SentAfter)
+	messages := []models.Message{}
+	for _, id := range sortedIDs(r.messages) {
+		message := r.messages[id]
+		if !filter.ConversationID.IsZero() && message.ConversationID != filter.ConversationID {
+			continue
+		}
+		if !filter.SentAfter.IsZero() && message.CreatedAt <= sentAfter {
+			continue
+		}
+		if !filter.NotFrom.IsZero() && message.SenderID == filter.NotFrom {
+			continue
+		}
+		messages = append(messages, *message)
+	}
+	return messages
+}
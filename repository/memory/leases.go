@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LeaseRepository struct {
+	mu     sync.RWMutex
+	leases map[primitive.ObjectID]*models.Lease
+}
+
+func NewLeaseRepository() *LeaseRepository {
+	return &LeaseRepository{leases: map[primitive.ObjectID]*models.Lease{}}
+}
+
+func (r *LeaseRepository) Create(_ context.Context, lease *models.Lease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.leases {
+		if existing.PropertyID == lease.PropertyID && existing.Status == models.LeaseActive {
+			return repository.ErrDuplicate
+		}
+	}
+	if lease.ID.IsZero() {
+		lease.ID = primitive.NewObjectID()
+	}
+	r.leases[lease.ID] = cloneLease(lease)
+	return nil
+}
+
+func (r *LeaseRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.leases[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.leases, id)
+	return nil
+}
+
+func (r *LeaseRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Lease, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lease, ok := r.leases[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneLease(lease), nil
+}
+
+func (r *LeaseRepository) FindActiveByProperty(_ context.Context, propertyID primitive.ObjectID) (*models.Lease, error) {
+	leases := r.filter(func(l *models.Lease) bool {
+		return l.PropertyID == propertyID && l.Status == models.LeaseActive
+	})
+	if len(leases) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &leases[0], nil
+}
+
+func (r *LeaseRepository) List(_ context.Context, filter repository.LeaseFilter, page repository.Page) ([]models.Lease, error) {
+	leases := r.list(filter)
+	slices.Reverse(leases) // newest first
+	return paginate(leases, page, func(l *models.Lease) (float64, primitive.ObjectID) { return repository.ByCreation(l.ID) }), nil
+}
+
+func (r *LeaseRepository) Count(_ context.Context, filter repository.LeaseFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *LeaseRepository) list(filter repository.LeaseFilter) []models.Lease {
+	return r.filter(func(l *models.Lease) bool {
+		if !filter.TenantID.IsZero() && l.TenantID != filter.TenantID {
+			return false
+		}
+		if filter.OwnerEmail != "" && l.OwnerEmail != filter.OwnerEmail {
+			return false
+		}
+		if !filter.PropertyID.IsZero() && l.PropertyID != filter.PropertyID {
+			return false
+		}
+		if filter.Status != "" && l.Status != filter.Status {
+			return false
+		}
+		return true
+	})
+}
+
+func (r *LeaseRepository) FindDue(_ context.Context, now time.Time) ([]models.Lease, error) {
+	cutoff := primitive.NewDateTimeFromTime(now)
+	return r.filter(func(l *models.Lease) bool {
+		return l.Status == models.LeaseActive && l.EndDate <= cutoff
+	}), nil
+}
+
+func (r *LeaseRepository) Update(_ context.Context, lease *models.Lease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.leases[lease.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if stored.Version != lease.Version {
+		return repository.ErrConflict
+	}
+	lease.Version++
+	r.leases[lease.ID] = cloneLease(lease)
+	return nil
+}
+
+// filter returns copies of the matching leases, oldest first
+func (r *LeaseRepository) filter(match func(*models.Lease) bool) []models.Lease {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	leases := []models.Lease{}
+	for _, id := range sortedIDs(r.leases) {
+		if lease := r.leases[id]; match(lease) {
+			leases = append(leases, *cloneLease(lease))
+		}
+	}
+	return leases
+}
+
+// cloneLease copies the lease so callers never share pointers or slices with the store
+func cloneLease(lease *models.Lease) *models.Lease {
+	c := *lease
+	c.History = slices.Clone(lease.History)
+	if lease.PendingRenewal != nil {
+		renewal := *lease.PendingRenewal
+		c.PendingRenewal = &renewal
+	}
+	if lease.Termination != nil {
+		termination := *lease.Termination
+		c.Termination = &termination
+	}
+	return &c
+}
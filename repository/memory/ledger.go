@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InvoiceRepository struct {
+	mu       sync.RWMutex
+	invoices map[primitive.ObjectID]*models.Invoice
+}
+
+func NewInvoiceRepository() *InvoiceRepository {
+	return &InvoiceRepository{invoices: map[primitive.ObjectID]*models.Invoice{}}
+}
+
+func (r *InvoiceRepository) Create(_ context.Context, invoice *models.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.invoices {
+		if existing.LeaseID == invoice.LeaseID && existing.Key == invoice.Key {
+			return repository.ErrDuplicate
+		}
+	}
+	if invoice.ID.IsZero() {
+		invoice.ID = primitive.NewObjectID()
+	}
+	stored := *invoice
+	r.invoices[invoice.ID] = &stored
+	return nil
+}
+
+func (r *InvoiceRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Invoice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	c := *invoice
+	return &c, nil
+}
+
+func (r *InvoiceRepository) List(_ context.Context, filter repository.InvoiceFilter, page repository.Page) ([]models.Invoice, error) {
+	return paginate(r.list(filter), page, func(i *models.Invoice) (float64, primitive.ObjectID) { return repository.ByCreation(i.ID) }), nil
+}
+
+func (r *InvoiceRepository) Count(_ context.Context, filter repository.InvoiceFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *InvoiceRepository) list(filter repository.InvoiceFilter) []models.Invoice {
+	return r.filter(func(i *models.Invoice) bool {
+		if !filter.LeaseID.IsZero() && i.LeaseID != filter.LeaseID {
+			return false
+		}
+		if !filter.TenantID.IsZero() && i.TenantID != filter.TenantID {
+			return false
+		}
+		if filter.OwnerEmail != "" && i.OwnerEmail != filter.OwnerEmail {
+			return false
+		}
+		if filter.Status != "" && i.Status != filter.Status {
+			return false
+		}
+		return true
+	})
+}
+
+func (r *InvoiceRepository) FindOverdue(_ context.Context, dueBefore time.Time) ([]models.Invoice, error) {
+	cutoff := primitive.NewDateTimeFromTime(dueBefore)
+	return r.filter(func(i *models.Invoice) bool {
+		return i.Kind == models.InvoiceRent && i.Status == models.InvoiceOpen && !i.LateFeeApplied && i.DueDate < cutoff
+	}), nil
+}
+
+func (r *InvoiceRepository) MarkLateFeeApplied(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	invoice.LateFeeApplied = true
+	return nil
+}
+
+func (r *InvoiceRepository) ApplyPayment(_ context.Context, invoice *models.Invoice, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.invoices[invoice.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if stored.AmountPaid != invoice.AmountPaid {
+		return repository.ErrConflict
+	}
+	applyPayment(stored, amount)
+	*invoice = *stored
+	return nil
+}
+
+// filter returns copies of the matching invoices ordered by due date
+func (r *InvoiceRepository) filter(match func(*models.Invoice) bool) []models.Invoice {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invoices := []models.Invoice{}
+	for _, id := range sortedIDs(r.invoices) {
+		if invoice := r.invoices[id]; match(invoice) {
+			invoices = append(invoices, *invoice)
+		}
+	}
+	slices.SortStableFunc(invoices, func(a, b models.Invoice) int { return cmp.Compare(a.DueDate, b.DueDate) })
+	return invoices
+}
+
+func applyPayment(invoice *models.Invoice, amount float64) {
+	invoice.AmountPaid += amount
+	invoice.Status = models.InvoiceOpen
+	if invoice.Settled() {
+		invoice.Status = models.InvoicePaid
+	}
+	invoice.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+}
+
+type PaymentRepository struct {
+	mu       sync.RWMutex
+	payments map[primitive.ObjectID]*models.Payment
+}
+
+func NewPaymentRepository() *PaymentRepository {
+	return &PaymentRepository{payments: map[primitive.ObjectID]*models.Payment{}}
+}
+
+func (r *PaymentRepository) Create(_ context.Context, payment *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	stored := *payment
+	r.payments[payment.ID] = &stored
+	return nil
+}
+
+func (r *PaymentRepository) List(_ context.Context, filter repository.PaymentFilter, page repository.Page) ([]models.Payment, error) {
+	return paginate(r.list(filter), page, func(p *models.Payment) (float64, primitive.ObjectID) { return repository.ByCreation(p.ID) }), nil
+}
+
+func (r *PaymentRepository) Count(_ context.Context, filter repository.PaymentFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+// list returns the matching payments ordered by payment date
+func (r *PaymentRepository) list(filter repository.PaymentFilter) []models.Payment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := []models.Payment{}
+	for _, id := range sortedIDs(r.payments) {
+		payment := r.payments[id]
+		if !filter.LeaseID.IsZero() && payment.LeaseID != filter.LeaseID {
+			continue
+		}
+		if !filter.InvoiceID.IsZero() && payment.InvoiceID != filter.InvoiceID {
+			continue
+		}
+		if !filter.TenantID.IsZero() && payment.TenantID != filter.TenantID {
+			continue
+		}
+		if filter.OwnerEmail != "" && payment.OwnerEmail != filter.OwnerEmail {
+			continue
+		}
+		payments = append(payments, *payment)
+	}
+	slices.SortStableFunc(payments, func(a, b models.Payment) int { return cmp.Compare(a.PaidAt, b.PaidAt) })
+	return payments
+}
@@ -0,0 +1,55 @@
+// Package memory implements the repository interfaces in process memory.
+// It is meant for tests and for running the API without MongoDB; data is
+// lost when the process exits.
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"dwello-api/repository"
+)
+
+// NewStore returns a repository.Store whose repositories share no state with any other store.
+func NewStore() repository.Store {
+	return repository.Store{
+		Transactor:      Transactor{},
+		Users:           NewUserRepository(),
+		Properties:      NewPropertyRepository(),
+		PropertyChanges: NewPropertyChangeRepository(),
+		RentalRequests:  NewRentalRequestRepository(),
+		Leases:          NewLeaseRepository(),
+		Invoices:        NewInvoiceRepository(),
+		Payments:        NewPaymentRepository(),
+		SavedSearches:   NewSavedSearchRepository(),
+		Alerts:          NewAlertRepository(),
+		Similarities:    NewSimilarityRepository(),
+		Conversations:   NewConversationRepository(),
+		Messages:        NewMessageRepository(),
+		Notifications:   NewNotificationRepository(),
+		Webhooks:        NewWebhookRepository(),
+		Deliveries:      NewWebhookDeliveryRepository(),
+		ViewingSlots:    NewViewingSlotRepository(),
+		Viewings:        NewViewingRepository(),
+	}
+}
+
+// Transactor has no real transactions and always relies on compensating actions.
+type Transactor struct{}
+
+func (Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx *repository.Tx) error) error {
+	return repository.WithCompensation(ctx, fn)
+}
+
+// addToSet appends v to s unless it is already present
+func addToSet[T comparable](s []T, v T) []T {
+	if slices.Contains(s, v) {
+		return s
+	}
+	return append(s, v)
+}
+
+// pull removes every occurrence of v from s
+func pull[T comparable](s []T, v T) []T {
+	return slices.DeleteFunc(s, func(e T) bool { return e == v })
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationRepository struct {
+	mu            sync.RWMutex
+	notifications map[primitive.ObjectID]*models.Notification
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{notifications: map[primitive.ObjectID]*models.Notification{}}
+}
+
+func (r *NotificationRepository) Create(_ context.Context, notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	r.notifications[notification.ID] = cloneNotification(notification)
+	return nil
+}
+
+func (r *NotificationRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneNotification(notification), nil
+}
+
+func (r *NotificationRepository) List(_ context.Context, filter repository.NotificationFilter, page repository.Page) ([]models.Notification, error) {
+	notifications := r.list(filter)
+	slices.Reverse(notifications) // newest first
+	return paginate(notifications, page, func(n *models.Notification) (float64, primitive.ObjectID) { return repository.ByCreation(n.ID) }), nil
+}
+
+func (r *NotificationRepository) Count(_ context.Context, filter repository.NotificationFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *NotificationRepository) list(filter repository.NotificationFilter) []models.Notification {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := []models.Notification{}
+	for _, id := range sortedIDs(r.notifications) {
+		notification := r.notifications[id]
+		if !filter.UserID.IsZero() && notification.UserID != filter.UserID {
+			continue
+		}
+		if filter.Unread && notification.ReadAt != nil {
+			continue
+		}
+		notifications = append(notifications, *cloneNotification(notification))
+	}
+	return notifications
+}
+
+func (r *NotificationRepository) MarkRead(_ context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if notification.ReadAt == nil {
+		readAt := primitive.NewDateTimeFromTime(at)
+		notification.ReadAt = &readAt
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(_ context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var marked int64
+	readAt := primitive.NewDateTimeFromTime(at)
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			notification.ReadAt = &readAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// cloneNotification copies the notification so callers never share pointers with the store
+func cloneNotification(notification *models.Notification) *models.Notification {
+	c := *notification
+	c.RentalRequestID = clonePtr(notification.RentalRequestID)
+	c.ReadAt = clonePtr(notification.ReadAt)
+	return &c
+}
@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PropertyRepository struct {
+	mu         sync.RWMutex
+	properties map[primitive.ObjectID]*models.Property
+}
+
+func NewPropertyRepository() *PropertyRepository {
+	return &PropertyRepository{properties: map[primitive.ObjectID]*models.Property{}}
+}
+
+func (r *PropertyRepository) Create(_ context.Context, property *models.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if property.ID.IsZero() {
+		property.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.properties[property.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.properties[property.ID] = cloneProperty(property)
+	return nil
+}
+
+func (r *PropertyRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Property, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	property, ok := r.properties[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneProperty(property), nil
+}
+
+func (r *PropertyRepository) UpdateListing(_ context.Context, property *models.Property, fields []string, since primitive.DateTime) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.properties[property.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if stored.UpdatedAt != since {
+		return repository.ErrConflict
+	}
+
+	updated := cloneProperty(stored)
+	from := cloneProperty(property)
+	for _, field := range fields {
+		switch field {
+		case "title":
+			updated.Title = from.Title
+		case "description":
+			updated.Description = from.Description
+		case "price":
+			updated.Price = from.Price
+		case "location":
+			updated.Location = from.Location
+		case "coordinates":
+			updated.Coordinates = from.Coordinates
+		case "attributes":
+			updated.Attributes = from.Attributes
+		case "thumbnail":
+			updated.Thumbnail = from.Thumbnail
+		case "pictures":
+			updated.Pictures = from.Pictures
+		default:
+			return fmt.Errorf("%s is not a listing field", field)
+		}
+	}
+	updated.UpdatedAt = property.UpdatedAt
+	r.properties[property.ID] = updated
+	return nil
+}
+
+func (r *PropertyRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.properties[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.properties, id)
+	return nil
+}
+
+func (r *PropertyRepository) All(_ context.Context) ([]models.Property, error) {
+	return r.filter(func(*models.Property) bool { return true }), nil
+}
+
+func (r *PropertyRepository) Search(_ context.Context, search repository.PropertySearch, page repository.Page) ([]models.PropertySearchResult, error) {
+	if err := search.Check(); err != nil {
+		return nil, err
+	}
+	return paginate(r.search(search), page, func(result *models.PropertySearchResult) (float64, primitive.ObjectID) {
+		return repository.PropertySortValue(result, page.Sort), result.ID
+	}), nil
+}
+
+func (r *PropertyRepository) Count(_ context.Context, search repository.PropertySearch) (int64, error) {
+	if err := search.Check(); err != nil {
+		return 0, err
+	}
+	return int64(len(r.search(search))), nil
+}
+
+func (r *PropertyRepository) Facets(_ context.Context, search repository.PropertySearch) (*models.PropertyFacets, error) {
+	if err := search.Check(); err != nil {
+		return nil, err
+	}
+	facets := models.NewPropertyFacets()
+	for _, result := range r.search(search) {
+		facets.Add(&result.Property)
+	}
+	return facets, nil
+}
+
+// search returns every property matching the search in insertion order
+func (r *PropertyRepository) search(search repository.PropertySearch) []models.PropertySearchResult {
+	location := strings.ToLower(search.Location)
+	properties := r.filter(func(p *models.Property) bool {
+		if len(search.IDs) > 0 && !slices.Contains(search.IDs, p.ID) {
+			return false
+		}
+		if len(search.Locations) > 0 && !slices.Contains(search.Locations, p.Location) {
+			return false
+		}
+		if search.RentedBy != "" && p.RentedByEmail != search.RentedBy {
+			return false
+		}
+		if search.Available && p.IsRented {
+			return false
+		}
+		if search.NotOwnedBy != "" && p.OwnerEmail == search.NotOwnedBy {
+			return false
+		}
+		if !search.CreatedAfter.IsZero() && p.CreatedAt <= primitive.NewDateTimeFromTime(search.CreatedAfter) {
+			return false
+		}
+		if location != "" && !strings.Contains(strings.ToLower(p.Location), location) {
+			return false
+		}
+		if !inRange(p.Price, search.MinPrice, search.MaxPrice) {
+			return false
+		}
+		if !matchesAttributes(&p.Attributes, search) {
+			return false
+		}
+		if (search.Near != nil || search.Box != nil) && p.Coordinates == nil {
+			return false
+		}
+		if search.Box != nil && !search.Box.Contains(p.Coordinates) {
+			return false
+		}
+		return true
+	})
+
+	terms := textsearch.Terms(search.Query)
+	results := []models.PropertySearchResult{}
+	for _, p := range properties {
+		result := models.PropertySearchResult{Property: p}
+		if len(terms) > 0 {
+			result.Score = textsearch.Score(terms, p.Title, p.Location, p.Description)
+			if result.Score == 0 {
+				continue
+			}
+		}
+		if search.Near != nil {
+			distance := search.Near.DistanceTo(p.Coordinates)
+			if distance > search.MaxDistance {
+				continue
+			}
+			result.Distance = &distance
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func matchesAttributes(attrs *models.PropertyAttributes, search repository.PropertySearch) bool {
+	if len(search.Types) > 0 && !slices.Contains(search.Types, attrs.Type) {
+		return false
+	}
+	if !inRange(attrs.Bedrooms, search.MinBedrooms, search.MaxBedrooms) {
+		return false
+	}
+	if !inRange(attrs.Bathrooms, search.MinBathrooms, nil) {
+		return false
+	}
+	if !inRange(attrs.AreaSqft, search.MinArea, search.MaxArea) {
+		return false
+	}
+	if len(search.Furnishing) > 0 && !slices.Contains(search.Furnishing, attrs.Furnishing) {
+		return false
+	}
+	if search.PetsAllowed != nil && attrs.PetsAllowed != *search.PetsAllowed {
+		return false
+	}
+	for _, amenity := range search.Amenities {
+		if !slices.Contains(attrs.Amenities, amenity) {
+			return false
+		}
+	}
+	return true
+}
+
+// inRange reports whether v lies within the bounds that are set
+func inRange[T int | float64](v T, lo, hi *T) bool {
+	return (lo == nil || v >= *lo) && (hi == nil || v <= *hi)
+}
+
+func (r *PropertyRepository) AddLike(_ context.Context, propertyID primitive.ObjectID, email string) error {
+	return r.update(propertyID, func(p *models.Property) {
+		if !slices.Contains(p.LikedBy, email) {
+			p.LikedBy = append(p.LikedBy, email)
+		}
+	})
+}
+
+func (r *PropertyRepository) RemoveLike(_ context.Context, propertyID primitive.ObjectID, email string) error {
+	return r.update(propertyID, func(p *models.Property) { p.LikedBy = pull(p.LikedBy, email) })
+}
+
+func (r *PropertyRepository) MarkRented(_ context.Context, propertyID primitive.ObjectID, renterEmail string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	property, ok := r.properties[propertyID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if property.IsRented {
+		return repository.ErrConflict
+	}
+	property.IsRented = true
+	property.RentedByEmail = renterEmail
+	return nil
+}
+
+func (r *PropertyRepository) MarkAvailable(_ context.Context, propertyID primitive.ObjectID) error {
+	return r.update(propertyID, func(p *models.Property) {
+		p.IsRented = false
+		p.RentedByEmail = ""
+	})
+}
+
+func (r *PropertyRepository) AddImage(_ context.Context, propertyID primitive.ObjectID, image *models.Image) error {
+	return r.update(propertyID, func(p *models.Property) { p.AddImage(cloneImage(*image)) })
+}
+
+func (r *PropertyRepository) RemoveImage(_ context.Context, propertyID, imageID primitive.ObjectID) error {
+	found := false
+	err := r.update(propertyID, func(p *models.Property) { _, found = p.RemoveImage(imageID) })
+	if err == nil && !found {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+func (r *PropertyRepository) SetOwnerPic(_ context.Context, ownerEmail, pic string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.properties {
+		if p.OwnerEmail == ownerEmail {
+			p.OwnerPic = pic
+		}
+	}
+	return nil
+}
+
+func (r *PropertyRepository) update(id primitive.ObjectID, fn func(*models.Property)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	property, ok := r.properties[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	fn(property)
+	return nil
+}
+
+// filter returns copies of the matching properties in insertion order
+func (r *PropertyRepository) filter(match func(*models.Property) bool) []models.Property {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	properties := []models.Property{}
+	for _, id := range sortedIDs(r.properties) {
+		if property := r.properties[id]; match(property) {
+			properties = append(properties, *cloneProperty(property))
+		}
+	}
+	return properties
+}
+
+// sortedIDs returns the map keys ordered by ObjectID, which follows creation time
+func sortedIDs[T any](m map[primitive.ObjectID]T) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b primitive.ObjectID) int { return slices.Compare(a[:], b[:]) })
+	return ids
+}
+
+// paginate sorts the items for the page and returns those following its
+// cursor, up to its limit. key returns an item's sort value and ID. Items
+// stay in the order given when the page has no sort.
+func paginate[T any](items []T, page repository.Page, key func(*T) (float64, primitive.ObjectID)) []T {
+	if page.Sort != "" {
+		slices.SortStableFunc(items, func(a, b T) int {
+			aValue, aID := key(&a)
+			bValue, bID := key(&b)
+			return page.Compare(aValue, aID, bValue, bID)
+		})
+	}
+	if after := page.After; after != nil {
+		items = slices.DeleteFunc(items, func(item T) bool {
+			value, id := key(&item)
+			return page.Compare(value, id, after.Value, after.ID) <= 0
+		})
+	}
+	if page.Limit > 0 && page.Limit < int64(len(items)) {
+		items = items[:page.Limit]
+	}
+	return items
+}
+
+// cloneProperty copies the property so callers never share slices with the store
+func cloneProperty(property *models.Property) *models.Property {
+	c := *property
+	c.Pictures = slices.Clone(property.Pictures)
+	c.Images = make([]models.Image, len(property.Images))
+	for i, img := range property.Images {
+		c.Images[i] = cloneImage(img)
+	}
+	c.LikedBy = slices.Clone(property.LikedBy)
+	c.Attributes.Amenities = slices.Clone(property.Attributes.Amenities)
+	c.Coordinates = cloneGeoPoint(property.Coordinates)
+	return &c
+}
+
+func cloneGeoPoint(point *models.GeoPoint) *models.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &models.GeoPoint{Type: point.Type, Coordinates: slices.Clone(point.Coordinates)}
+}
+
+func cloneImage(img models.Image) models.Image {
+	img.Variants = maps.Clone(img.Variants)
+	img.Keys = slices.Clone(img.Keys)
+	return img
+}
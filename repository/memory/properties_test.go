@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newListings stores the properties in the order given, so their IDs ascend
+func newListings(t *testing.T, properties ...models.Property) *PropertyRepository {
+	t.Helper()
+	r := NewPropertyRepository()
+	for i := range properties {
+		if err := r.Create(context.Background(), &properties[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func titles(results []models.PropertySearchResult) []string {
+	names := make([]string, len(results))
+	for i, result := range results {
+		names[i] = result.Title
+	}
+	return names
+}
+
+func TestSearchPagination(t *testing.T) {
+	r := newListings(t,
+		models.Property{Title: "a", Price: 300, LikedBy: []string{"x"}},
+		models.Property{Title: "b", Price: 100},
+		models.Property{Title: "c", Price: 200, LikedBy: []string{"x", "y"}},
+		models.Property{Title: "d", Price: 100, LikedBy: []string{"x"}},
+		models.Property{Title: "e", Price: 200},
+	)
+
+	tests := []struct {
+		name string
+		sort repository.SortField
+		desc bool
+		want []string
+	}{
+		{"newest first", repository.SortCreatedAt, true, []string{"e", "d", "c", "b", "a"}},
+		{"oldest first", repository.SortCreatedAt, false, []string{"a", "b", "c", "d", "e"}},
+		{"cheapest first, ties by ID", repository.SortPrice, false, []string{"b", "d", "c", "e", "a"}},
+		{"most expensive first, ties by ID", repository.SortPrice, true, []string{"a", "e", "c", "d", "b"}},
+		{"most liked first", repository.SortLikes, true, []string{"c", "d", "a", "e", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Walk the pages two at a time like a client following next cursors
+			page := repository.Page{Sort: tt.sort, Desc: tt.desc, Limit: 2}
+			var got []string
+			for range len(tt.want) {
+				results, err := r.Search(context.Background(), repository.PropertySearch{}, page)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, titles(results)...)
+				if len(results) < int(page.Limit) {
+					break
+				}
+				last := &results[len(results)-1]
+				page.After = &repository.Cursor{
+					Sort: tt.sort, Desc: tt.desc, Value: repository.PropertySortValue(last, tt.sort), ID: last.ID,
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	two, three := 2, 3
+	cheap, dear := 150.0, 250.0
+	pets := true
+	r := newListings(t,
+		models.Property{
+			Title: "Garden flat", Location: "Pune", Price: 100, OwnerEmail: "owner@example.com",
+			Coordinates: models.NewGeoPoint(73.85, 18.52),
+			Attributes: models.PropertyAttributes{
+				Type: models.PropertyApartment, Bedrooms: 2, Furnishing: models.Furnished, Amenities: []string{"parking", "lift"},
+			},
+		},
+		models.Property{
+			Title: "Villa", Location: "Mumbai", Price: 300, IsRented: true, RentedByEmail: "tenant@example.com",
+			Coordinates: models.NewGeoPoint(72.88, 19.08),
+			Attributes:  models.PropertyAttributes{Type: models.PropertyVilla, Bedrooms: 3, PetsAllowed: true},
+		},
+		models.Property{
+			Title: "Studio", Location: "Pune Camp", Price: 200,
+			Attributes: models.PropertyAttributes{Type: models.PropertyStudio, Bedrooms: 0},
+		},
+	)
+
+	tests := []struct {
+		name   string
+		search repository.PropertySearch
+		want   []string
+	}{
+		{"everything", repository.PropertySearch{}, []string{"Garden flat", "Villa", "Studio"}},
+		{"available", repository.PropertySearch{Available: true}, []string{"Garden flat", "Studio"}},
+		{"rented by", repository.PropertySearch{RentedBy: "tenant@example.com"}, []string{"Villa"}},
+		{"not owned by", repository.PropertySearch{NotOwnedBy: "owner@example.com"}, []string{"Villa", "Studio"}},
+		{"location contains", repository.PropertySearch{Location: "pune"}, []string{"Garden flat", "Studio"}},
+		{"exact locations", repository.PropertySearch{Locations: []string{"Pune"}}, []string{"Garden flat"}},
+		{"price range", repository.PropertySearch{MinPrice: &cheap, MaxPrice: &dear}, []string{"Studio"}},
+		{"types", repository.PropertySearch{Types: []models.PropertyType{models.PropertyVilla, models.PropertyStudio}}, []string{"Villa", "Studio"}},
+		{"bedrooms", repository.PropertySearch{MinBedrooms: &two, MaxBedrooms: &three}, []string{"Garden flat", "Villa"}},
+		{"furnishing", repository.PropertySearch{Furnishing: []models.Furnishing{models.Furnished}}, []string{"Garden flat"}},
+		{"pets", repository.PropertySearch{PetsAllowed: &pets}, []string{"Villa"}},
+		{"every amenity", repository.PropertySearch{Amenities: []string{"parking", "lift"}}, []string{"Garden flat"}},
+		{"missing amenity", repository.PropertySearch{Amenities: []string{"parking", "pool"}}, []string{}},
+		{"query", repository.PropertySearch{Query: "garden"}, []string{"Garden flat"}},
+		{
+			"box", repository.PropertySearch{Box: &models.GeoBox{MinLng: 73, MinLat: 18, MaxLng: 74, MaxLat: 19}},
+			[]string{"Garden flat"},
+		},
+		{
+			"near", repository.PropertySearch{Near: models.NewGeoPoint(72.9, 19.1), MaxDistance: 10000},
+			[]string{"Villa"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := r.Search(context.Background(), tt.search, repository.Page{Sort: repository.SortCreatedAt})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(results); !slices.Equal(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+			count, err := r.Count(context.Background(), tt.search)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != int64(len(tt.want)) {
+				t.Errorf("Count() = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSearchRejectsNearWithQuery(t *testing.T) {
+	r := NewPropertyRepository()
+	search := repository.PropertySearch{Query: "garden", Near: models.NewGeoPoint(73.85, 18.52), MaxDistance: 1000}
+
+	if _, err := r.Search(context.Background(), search, repository.Page{}); !errors.Is(err, repository.ErrInvalidSearch) {
+		t.Errorf("Search() error = %v, want ErrInvalidSearch", err)
+	}
+	if _, err := r.Count(context.Background(), search); !errors.Is(err, repository.ErrInvalidSearch) {
+		t.Errorf("Count() error = %v, want ErrInvalidSearch", err)
+	}
+	if _, err := r.Facets(context.Background(), search); !errors.Is(err, repository.ErrInvalidSearch) {
+		t.Errorf("Facets() error = %v, want ErrInvalidSearch", err)
+	}
+}
+
+func TestMarkRented(t *testing.T) {
+	tests := []struct {
+		name     string
+		property *models.Property
+		wantErr  error
+	}{
+		{"available", &models.Property{Title: "Flat"}, nil},
+		{"already rented", &models.Property{Title: "Flat", IsRented: true, RentedByEmail: "first@example.com"}, repository.ErrConflict},
+		{"missing", nil, repository.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewPropertyRepository()
+			id := primitive.NewObjectID()
+			if tt.property != nil {
+				tt.property.ID = id
+				if err := r.Create(context.Background(), tt.property); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := r.MarkRented(context.Background(), id, "tenant@example.com")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MarkRented() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			stored, err := r.FindByID(context.Background(), id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !stored.IsRented || stored.RentedByEmail != "tenant@example.com" {
+				t.Errorf("stored = rented %v by %q, want rented by tenant@example.com", stored.IsRented, stored.RentedByEmail)
+			}
+		})
+	}
+}
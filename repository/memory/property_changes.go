@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PropertyChangeRepository struct {
+	mu      sync.RWMutex
+	changes map[primitive.ObjectID]*models.PropertyChange
+}
+
+func NewPropertyChangeRepository() *PropertyChangeRepository {
+	return &PropertyChangeRepository{changes: map[primitive.ObjectID]*models.PropertyChange{}}
+}
+
+func (r *PropertyChangeRepository) Create(_ context.Context, change *models.PropertyChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if change.ID.IsZero() {
+		change.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.changes[change.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.changes[change.ID] = clonePropertyChange(change)
+	return nil
+}
+
+func (r *PropertyChangeRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.changes[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.changes, id)
+	return nil
+}
+
+func (r *PropertyChangeRepository) List(_ context.Context, filter repository.PropertyChangeFilter, page repository.Page) ([]models.PropertyChange, error) {
+	changes := r.list(filter)
+	slices.Reverse(changes) // newest first
+	return paginate(changes, page, func(c *models.PropertyChange) (float64, primitive.ObjectID) { return repository.ByCreation(c.ID) }), nil
+}
+
+func (r *PropertyChangeRepository) Count(_ context.Context, filter repository.PropertyChangeFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *PropertyChangeRepository) list(filter repository.PropertyChangeFilter) []models.PropertyChange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := []models.PropertyChange{}
+	for _, id := range sortedIDs(r.changes) {
+		change := r.changes[id]
+		if !filter.PropertyID.IsZero() && change.PropertyID != filter.PropertyID {
+			continue
+		}
+		changes = append(changes, *clonePropertyChange(change))
+	}
+	return changes
+}
+
+func clonePropertyChange(change *models.PropertyChange) *models.PropertyChange {
+	c := *change
+	c.Changes = slices.Clone(change.Changes)
+	return &c
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RentalRequestRepository struct {
+	mu       sync.RWMutex
+	requests map[primitive.ObjectID]*models.RentalRequest
+}
+
+func NewRentalRequestRepository() *RentalRequestRepository {
+	return &RentalRequestRepository{requests: map[primitive.ObjectID]*models.RentalRequest{}}
+}
+
+func (r *RentalRequestRepository) Create(_ context.Context, request *models.RentalRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.requests {
+		if existing.PropertyID == request.PropertyID && existing.ApplicantID == request.ApplicantID &&
+			existing.Status == models.RentalRequestPending {
+			return repository.ErrDuplicate
+		}
+	}
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	r.requests[request.ID] = cloneRentalRequest(request)
+	return nil
+}
+
+func (r *RentalRequestRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.RentalRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneRentalRequest(request), nil
+}
+
+func (r *RentalRequestRepository) List(_ context.Context, filter repository.RentalRequestFilter, page repository.Page) ([]models.RentalRequest, error) {
+	return paginate(r.list(filter), page, func(req *models.RentalRequest) (float64, primitive.ObjectID) { return repository.ByCreation(req.ID) }), nil
+}
+
+func (r *RentalRequestRepository) Count(_ context.Context, filter repository.RentalRequestFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+// list returns the matching requests, newest first
+func (r *RentalRequestRepository) list(filter repository.RentalRequestFilter) []models.RentalRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := sortedIDs(r.requests)
+	slices.Reverse(ids) // newest first
+
+	requests := []models.RentalRequest{}
+	for _, id := range ids {
+		request := r.requests[id]
+		if !filter.ApplicantID.IsZero() && request.ApplicantID != filter.ApplicantID {
+			continue
+		}
+		if filter.OwnerEmail != "" && request.OwnerEmail != filter.OwnerEmail {
+			continue
+		}
+		if !filter.PropertyID.IsZero() && request.PropertyID != filter.PropertyID {
+			continue
+		}
+		if filter.Status != "" && request.Status != filter.Status {
+			continue
+		}
+		requests = append(requests, *cloneRentalRequest(request))
+	}
+	return requests
+}
+
+func (r *RentalRequestRepository) Transition(_ context.Context, id primitive.ObjectID, from models.RentalRequestStatus, change models.StatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if request.Status != from {
+		return repository.ErrConflict
+	}
+	applyStatusChange(request, change)
+	return nil
+}
+
+func (r *RentalRequestRepository) ExpirePending(_ context.Context, createdBefore, now time.Time) ([]models.RentalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := primitive.NewDateTimeFromTime(createdBefore)
+	at := primitive.NewDateTimeFromTime(now)
+
+	expired := []models.RentalRequest{}
+	for _, id := range sortedIDs(r.requests) {
+		request := r.requests[id]
+		if request.Status != models.RentalRequestPending {
+			continue
+		}
+		stale := request.CreatedAt < cutoff
+		pastMoveIn := request.MoveInDate != 0 && request.MoveInDate < at
+		if stale || pastMoveIn {
+			applyStatusChange(request, models.StatusChange{Status: models.RentalRequestExpired, At: at})
+			expired = append(expired, *cloneRentalRequest(request))
+		}
+	}
+	return expired, nil
+}
+
+func applyStatusChange(request *models.RentalRequest, change models.StatusChange) {
+	request.Status = change.Status
+	request.UpdatedAt = change.At
+	request.History = append(request.History, change)
+}
+
+// cloneRentalRequest copies the request so callers never share slices with the store
+func cloneRentalRequest(request *models.RentalRequest) *models.RentalRequest {
+	c := *request
+	c.History = slices.Clone(request.History)
+	return &c
+}
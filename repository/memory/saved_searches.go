@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SavedSearchRepository struct {
+	mu       sync.RWMutex
+	searches map[primitive.ObjectID]*models.SavedSearch
+}
+
+func NewSavedSearchRepository() *SavedSearchRepository {
+	return &SavedSearchRepository{searches: map[primitive.ObjectID]*models.SavedSearch{}}
+}
+
+func (r *SavedSearchRepository) Create(_ context.Context, search *models.SavedSearch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if search.ID.IsZero() {
+		search.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.searches[search.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.searches[search.ID] = cloneSavedSearch(search)
+	return nil
+}
+
+func (r *SavedSearchRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	search, ok := r.searches[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneSavedSearch(search), nil
+}
+
+func (r *SavedSearchRepository) List(_ context.Context, filter repository.SavedSearchFilter, page repository.Page) ([]models.SavedSearch, error) {
+	return paginate(r.list(filter), page, func(s *models.SavedSearch) (float64, primitive.ObjectID) { return repository.ByCreation(s.ID) }), nil
+}
+
+func (r *SavedSearchRepository) Count(_ context.Context, filter repository.SavedSearchFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *SavedSearchRepository) list(filter repository.SavedSearchFilter) []models.SavedSearch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	searches := []models.SavedSearch{}
+	for _, id := range sortedIDs(r.searches) {
+		search := r.searches[id]
+		if !filter.UserID.IsZero() && search.UserID != filter.UserID {
+			continue
+		}
+		searches = append(searches, *cloneSavedSearch(search))
+	}
+	return searches
+}
+
+func (r *SavedSearchRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.searches[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.searches, id)
+	return nil
+}
+
+func (r *SavedSearchRepository) SetCheckedUntil(_ context.Context, id primitive.ObjectID, until primitive.DateTime) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	search, ok := r.searches[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	search.CheckedUntil = until
+	return nil
+}
+
+// cloneSavedSearch copies the search so callers never share pointers or slices with the store
+func cloneSavedSearch(search *models.SavedSearch) *models.SavedSearch {
+	c := *search
+	f := &c.Filters
+	f.MinPrice, f.MaxPrice = clonePtr(f.MinPrice), clonePtr(f.MaxPrice)
+	f.MinBedrooms, f.MaxBedrooms, f.MinBathrooms = clonePtr(f.MinBedrooms), clonePtr(f.MaxBedrooms), clonePtr(f.MinBathrooms)
+	f.MinArea, f.MaxArea = clonePtr(f.MinArea), clonePtr(f.MaxArea)
+	f.PetsAllowed = clonePtr(f.PetsAllowed)
+	f.Types = slices.Clone(f.Types)
+	f.Furnishing = slices.Clone(f.Furnishing)
+	f.Amenities = slices.Clone(f.Amenities)
+	f.Box = clonePtr(f.Box)
+	return &c
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+type AlertRepository struct {
+	mu     sync.RWMutex
+	alerts map[primitive.ObjectID]*models.Alert
+}
+
+func NewAlertRepository() *AlertRepository {
+	return &AlertRepository{alerts: map[primitive.ObjectID]*models.Alert{}}
+}
+
+func (r *AlertRepository) Create(_ context.Context, alert *models.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.alerts {
+		if existing.SavedSearchID == alert.SavedSearchID && existing.PropertyID == alert.PropertyID {
+			return repository.ErrDuplicate
+		}
+	}
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+	r.alerts[alert.ID] = cloneAlert(alert)
+	return nil
+}
+
+func (r *AlertRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneAlert(alert), nil
+}
+
+func (r *AlertRepository) List(_ context.Context, filter repository.AlertFilter, page repository.Page) ([]models.Alert, error) {
+	alerts := r.list(filter)
+	slices.Reverse(alerts) // newest first
+	return paginate(alerts, page, func(a *models.Alert) (float64, primitive.ObjectID) { return repository.ByCreation(a.ID) }), nil
+}
+
+func (r *AlertRepository) Count(_ context.Context, filter repository.AlertFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *AlertRepository) list(filter repository.AlertFilter) []models.Alert {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alerts := []models.Alert{}
+	for _, id := range sortedIDs(r.alerts) {
+		alert := r.alerts[id]
+		if !filter.UserID.IsZero() && alert.UserID != filter.UserID {
+			continue
+		}
+		if filter.Unread && alert.ReadAt != nil {
+			continue
+		}
+		alerts = append(alerts, *cloneAlert(alert))
+	}
+	return alerts
+}
+
+func (r *AlertRepository) MarkRead(_ context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if alert.ReadAt == nil {
+		readAt := primitive.NewDateTimeFromTime(at)
+		alert.ReadAt = &readAt
+	}
+	return nil
+}
+
+func (r *AlertRepository) MarkAllRead(_ context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var marked int64
+	readAt := primitive.NewDateTimeFromTime(at)
+	for _, alert := range r.alerts {
+		if alert.UserID == userID && alert.ReadAt == nil {
+			alert.ReadAt = &readAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// cloneAlert copies the alert so callers never share pointers with the store
+func cloneAlert(alert *models.Alert) *models.Alert {
+	c := *alert
+	c.ReadAt = clonePtr(alert.ReadAt)
+	return &c
+}
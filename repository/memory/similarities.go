@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SimilarityRepository struct {
+	mu      sync.RWMutex
+	similar map[primitive.ObjectID]*models.SimilarProperties
+}
+
+func NewSimilarityRepository() *SimilarityRepository {
+	return &SimilarityRepository{similar: map[primitive.ObjectID]*models.SimilarProperties{}}
+}
+
+func (r *SimilarityRepository) Find(_ context.Context, propertyID primitive.ObjectID) (*models.SimilarProperties, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	similar, ok := r.similar[propertyID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneSimilarProperties(similar), nil
+}
+
+func (r *SimilarityRepository) Save(_ context.Context, similar *models.SimilarProperties) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.similar[similar.PropertyID] = cloneSimilarProperties(similar)
+	return nil
+}
+
+func (r *SimilarityRepository) DeleteComputedBefore(_ context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := primitive.NewDateTimeFromTime(t)
+	var deleted int64
+	for id, similar := range r.similar {
+		if similar.ComputedAt < before {
+			delete(r.similar, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// cloneSimilarProperties copies the list so callers never share slices with the store
+func cloneSimilarProperties(similar *models.SimilarProperties) *models.SimilarProperties {
+	c := *similar
+	c.Similar = slices.Clone(c.Similar)
+	return &c
+}
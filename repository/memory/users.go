@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]*models.User
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: map[primitive.ObjectID]*models.User{}}
+}
+
+func (r *UserRepository) Create(_ context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.byEmail(user.Email) != nil {
+		return repository.ErrDuplicate
+	}
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.users[user.ID] = cloneUser(user)
+	return nil
+}
+
+func (r *UserRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneUser(user), nil
+}
+
+func (r *UserRepository) FindByEmail(_ context.Context, email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user := r.byEmail(email)
+	if user == nil {
+		return nil, repository.ErrNotFound
+	}
+	return cloneUser(user), nil
+}
+
+func (r *UserRepository) FindByCalendarToken(_ context.Context, hash string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if hash != "" && user.Credentials.CalendarTokenHash == hash {
+			return cloneUser(user), nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *UserRepository) List(_ context.Context, filter repository.UserFilter, page repository.Page) ([]models.User, error) {
+	return paginate(r.list(filter), page, func(u *models.User) (float64, primitive.ObjectID) { return repository.ByCreation(u.ID) }), nil
+}
+
+func (r *UserRepository) Count(_ context.Context, filter repository.UserFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *UserRepository) list(filter repository.UserFilter) []models.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.users {
+		if filter.Role != "" && user.Role != filter.Role {
+			continue
+		}
+		users = append(users, *cloneUser(user))
+	}
+	return users
+}
+
+func (r *UserRepository) UpdateLocation(_ context.Context, email, location string, coordinates *models.GeoPoint) error {
+	return r.updateByEmail(email, func(u *models.User) {
+		u.Location = location
+		u.Coordinates = cloneGeoPoint(coordinates)
+	})
+}
+
+func (r *UserRepository) UpdatePreferredLocations(_ context.Context, email string, locations []string) error {
+	return r.updateByEmail(email, func(u *models.User) { u.PreferredLocations = slices.Clone(locations) })
+}
+
+func (r *UserRepository) SetRole(_ context.Context, email string, role models.Role) error {
+	return r.updateByEmail(email, func(u *models.User) { u.Role = role })
+}
+
+func (r *UserRepository) SetProfileImage(_ context.Context, id primitive.ObjectID, image *models.Image) error {
+	return r.update(id, func(u *models.User) {
+		img := cloneImage(*image)
+		u.ProfileImage = &img
+		u.ProfilePic = img.Variants[models.ImageMedium]
+	})
+}
+
+func (r *UserRepository) SetNotificationPreferences(_ context.Context, id primitive.ObjectID, preferences models.NotificationPreferences) error {
+	return r.update(id, func(u *models.User) { u.NotificationPreferences = clonePreferences(preferences) })
+}
+
+func (r *UserRepository) AddPushToken(_ context.Context, id primitive.ObjectID, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for _, other := range r.users {
+		other.PushTokens = pull(other.PushTokens, token)
+	}
+	user.PushTokens = addToSet(user.PushTokens, token)
+	return nil
+}
+
+func (r *UserRepository) RemovePushToken(_ context.Context, id primitive.ObjectID, token string) error {
+	return r.update(id, func(u *models.User) { u.PushTokens = pull(u.PushTokens, token) })
+}
+
+func (r *UserRepository) AddPostedProperty(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.PostedProperties = append(u.PostedProperties, propertyID) })
+}
+
+func (r *UserRepository) RemovePostedProperty(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.PostedProperties = pull(u.PostedProperties, propertyID) })
+}
+
+func (r *UserRepository) AddLike(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.LikedProperties = addToSet(u.LikedProperties, propertyID) })
+}
+
+func (r *UserRepository) RemoveLike(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.LikedProperties = pull(u.LikedProperties, propertyID) })
+}
+
+func (r *UserRepository) AddRentedProperty(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.RentedProperties = addToSet(u.RentedProperties, propertyID) })
+}
+
+func (r *UserRepository) RemoveRentedProperty(_ context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.update(userID, func(u *models.User) { u.RentedProperties = pull(u.RentedProperties, propertyID) })
+}
+
+func (r *UserRepository) SetPassword(_ context.Context, id primitive.ObjectID, hash string) error {
+	return r.update(id, func(u *models.User) {
+		u.Credentials.PasswordHash = hash
+		u.Credentials.ResetTokenHash = ""
+		u.Credentials.ResetExpiresAt = 0
+		u.Credentials.FailedAttempts = 0
+		u.Credentials.LockedUntil = 0
+		u.Credentials.TokenVersion++
+		u.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+	})
+}
+
+func (r *UserRepository) SetOTP(_ context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error {
+	return r.update(id, func(u *models.User) {
+		u.Credentials.OTPHash = hash
+		u.Credentials.OTPExpiresAt = primitive.NewDateTimeFromTime(expiresAt)
+	})
+}
+
+func (r *UserRepository) SetResetToken(_ context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error {
+	return r.update(id, func(u *models.User) {
+		u.Credentials.ResetTokenHash = hash
+		u.Credentials.ResetExpiresAt = primitive.NewDateTimeFromTime(expiresAt)
+	})
+}
+
+func (r *UserRepository) SetCalendarToken(_ context.Context, id primitive.ObjectID, hash string) error {
+	return r.update(id, func(u *models.User) { u.Credentials.CalendarTokenHash = hash })
+}
+
+func (r *UserRepository) IncrementFailedAttempts(_ context.Context, id primitive.ObjectID) error {
+	return r.update(id, func(u *models.User) { u.Credentials.FailedAttempts++ })
+}
+
+func (r *UserRepository) Lock(_ context.Context, id primitive.ObjectID, until time.Time) error {
+	return r.update(id, func(u *models.User) {
+		u.Credentials.FailedAttempts = 0
+		u.Credentials.LockedUntil = primitive.NewDateTimeFromTime(until)
+	})
+}
+
+func (r *UserRepository) ClearLockout(_ context.Context, id primitive.ObjectID, clearOTP bool) error {
+	return r.update(id, func(u *models.User) {
+		u.Credentials.FailedAttempts = 0
+		u.Credentials.LockedUntil = 0
+		if clearOTP {
+			u.Credentials.OTPHash = ""
+			u.Credentials.OTPExpiresAt = 0
+		}
+	})
+}
+
+// byEmail must be called with the lock held
+func (r *UserRepository) byEmail(email string) *models.User {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) update(id primitive.ObjectID, fn func(*models.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	fn(user)
+	return nil
+}
+
+func (r *UserRepository) updateByEmail(email string, fn func(*models.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.byEmail(email)
+	if user == nil {
+		return repository.ErrNotFound
+	}
+	fn(user)
+	user.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+	return nil
+}
+
+// cloneUser copies the user so callers never share slices with the store
+func cloneUser(user *models.User) *models.User {
+	c := *user
+	c.PreferredLocations = slices.Clone(user.PreferredLocations)
+	c.PostedProperties = slices.Clone(user.PostedProperties)
+	c.LikedProperties = slices.Clone(user.LikedProperties)
+	c.RentedProperties = slices.Clone(user.RentedProperties)
+	c.Coordinates = cloneGeoPoint(user.Coordinates)
+	c.NotificationPreferences = clonePreferences(user.NotificationPreferences)
+	c.PushTokens = slices.Clone(user.PushTokens)
+	if user.ProfileImage != nil {
+		img := cloneImage(*user.ProfileImage)
+		c.ProfileImage = &img
+	}
+	return &c
+}
+
+func clonePreferences(preferences models.NotificationPreferences) models.NotificationPreferences {
+	if preferences == nil {
+		return nil
+	}
+	c := models.NotificationPreferences{}
+	for t, channels := range preferences {
+		c[t] = slices.Clone(channels)
+	}
+	return c
+}
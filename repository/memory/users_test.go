@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserCreate(t *testing.T) {
+	r := NewUserRepository()
+	if err := r.Create(context.Background(), &models.User{Email: "tenant@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{"new email", "owner@example.com", nil},
+		{"taken email", "tenant@example.com", repository.ErrDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{Email: tt.email}
+			err := r.Create(context.Background(), user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && user.ID.IsZero() {
+				t.Error("Create() did not set the ID")
+			}
+		})
+	}
+}
+
+func TestUserCredentials(t *testing.T) {
+	ctx := context.Background()
+	until := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		update func(r *UserRepository, id primitive.ObjectID) error
+		want   models.Credentials
+	}{
+		{
+			"failed attempt counted",
+			func(r *UserRepository, id primitive.ObjectID) error { return r.IncrementFailedAttempts(ctx, id) },
+			models.Credentials{PasswordHash: "old", FailedAttempts: 3, OTPHash: "otp"},
+		},
+		{
+			"lock resets the count",
+			func(r *UserRepository, id primitive.ObjectID) error { return r.Lock(ctx, id, until) },
+			models.Credentials{PasswordHash: "old", LockedUntil: primitive.NewDateTimeFromTime(until), OTPHash: "otp"},
+		},
+		{
+			"login clears the lockout",
+			func(r *UserRepository, id primitive.ObjectID) error { return r.ClearLockout(ctx, id, false) },
+			models.Credentials{PasswordHash: "old", OTPHash: "otp"},
+		},
+		{
+			"code login consumes the code",
+			func(r *UserRepository, id primitive.ObjectID) error { return r.ClearLockout(ctx, id, true) },
+			models.Credentials{PasswordHash: "old"},
+		},
+		{
+			"new password revokes tokens",
+			func(r *UserRepository, id primitive.ObjectID) error { return r.SetPassword(ctx, id, "new") },
+			models.Credentials{PasswordHash: "new", OTPHash: "otp", TokenVersion: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewUserRepository()
+			user := &models.User{
+				Email:       "tenant@example.com",
+				Credentials: models.Credentials{PasswordHash: "old", FailedAttempts: 2, OTPHash: "otp"},
+			}
+			if err := r.Create(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.update(r, user.ID); err != nil {
+				t.Fatal(err)
+			}
+			stored, err := r.FindByID(ctx, user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Credentials != tt.want {
+				t.Errorf("credentials = %+v, want %+v", stored.Credentials, tt.want)
+			}
+		})
+	}
+}
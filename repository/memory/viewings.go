@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ViewingSlotRepository struct {
+	mu    sync.RWMutex
+	slots map[primitive.ObjectID]*models.ViewingSlot
+}
+
+func NewViewingSlotRepository() *ViewingSlotRepository {
+	return &ViewingSlotRepository{slots: map[primitive.ObjectID]*models.ViewingSlot{}}
+}
+
+func (r *ViewingSlotRepository) Create(_ context.Context, slot *models.ViewingSlot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slot.ID.IsZero() {
+		slot.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.slots[slot.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.slots[slot.ID] = cloneSlot(slot)
+	return nil
+}
+
+func (r *ViewingSlotRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.ViewingSlot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	slot, ok := r.slots[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneSlot(slot), nil
+}
+
+func (r *ViewingSlotRepository) List(_ context.Context, filter repository.ViewingSlotFilter, page repository.Page) ([]models.ViewingSlot, error) {
+	return paginate(r.list(filter), page, func(s *models.ViewingSlot) (float64, primitive.ObjectID) { return repository.ByCreation(s.ID) }), nil
+}
+
+func (r *ViewingSlotRepository) Count(_ context.Context, filter repository.ViewingSlotFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *ViewingSlotRepository) list(filter repository.ViewingSlotFilter) []models.ViewingSlot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	slots := []models.ViewingSlot{}
+	for _, id := range sortedIDs(r.slots) {
+		slot := r.slots[id]
+		if !filter.PropertyID.IsZero() && slot.PropertyID != filter.PropertyID {
+			continue
+		}
+		if filter.OwnerEmail != "" && slot.OwnerEmail != filter.OwnerEmail {
+			continue
+		}
+		if !overlaps(slot.Start, slot.End, filter.From, filter.To) {
+			continue
+		}
+		if filter.Available && slot.Booked() {
+			continue
+		}
+		slots = append(slots, *cloneSlot(slot))
+	}
+	slices.SortStableFunc(slots, func(a, b models.ViewingSlot) int { return cmp.Compare(a.Start, b.Start) })
+	return slots
+}
+
+func (r *ViewingSlotRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot, ok := r.slots[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if slot.Booked() {
+		return repository.ErrConflict
+	}
+	delete(r.slots, id)
+	return nil
+}
+
+func (r *ViewingSlotRepository) Book(_ context.Context, id, viewingID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot, ok := r.slots[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if slot.Booked() {
+		return repository.ErrConflict
+	}
+	slot.ViewingID = &viewingID
+	return nil
+}
+
+func (r *ViewingSlotRepository) Release(_ context.Context, id, viewingID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot, ok := r.slots[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if slot.ViewingID != nil && *slot.ViewingID == viewingID {
+		slot.ViewingID = nil
+	}
+	return nil
+}
+
+// cloneSlot copies the slot so callers never share pointers with the store
+func cloneSlot(slot *models.ViewingSlot) *models.ViewingSlot {
+	c := *slot
+	c.ViewingID = clonePtr(slot.ViewingID)
+	return &c
+}
+
+type ViewingRepository struct {
+	mu       sync.RWMutex
+	viewings map[primitive.ObjectID]*models.Viewing
+}
+
+func NewViewingRepository() *ViewingRepository {
+	return &ViewingRepository{viewings: map[primitive.ObjectID]*models.Viewing{}}
+}
+
+func (r *ViewingRepository) Create(_ context.Context, viewing *models.Viewing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if viewing.ID.IsZero() {
+		viewing.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.viewings[viewing.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.viewings[viewing.ID] = cloneViewing(viewing)
+	return nil
+}
+
+func (r *ViewingRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Viewing, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	viewing, ok := r.viewings[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneViewing(viewing), nil
+}
+
+func (r *ViewingRepository) List(_ context.Context, filter repository.ViewingFilter, page repository.Page) ([]models.Viewing, error) {
+	return paginate(r.list(filter), page, func(v *models.Viewing) (float64, primitive.ObjectID) { return repository.ByCreation(v.ID) }), nil
+}
+
+func (r *ViewingRepository) Count(_ context.Context, filter repository.ViewingFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *ViewingRepository) list(filter repository.ViewingFilter) []models.Viewing {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	viewings := []models.Viewing{}
+	for _, id := range sortedIDs(r.viewings) {
+		viewing := r.viewings[id]
+		if !filter.VisitorID.IsZero() && viewing.VisitorID != filter.VisitorID {
+			continue
+		}
+		if filter.OwnerEmail != "" && viewing.OwnerEmail != filter.OwnerEmail {
+			continue
+		}
+		if !filter.PropertyID.IsZero() && viewing.PropertyID != filter.PropertyID {
+			continue
+		}
+		if filter.Status != "" && viewing.Status != filter.Status {
+			continue
+		}
+		if !overlaps(viewing.Start, viewing.End, filter.From, filter.To) {
+			continue
+		}
+		viewings = append(viewings, *cloneViewing(viewing))
+	}
+	slices.SortStableFunc(viewings, func(a, b models.Viewing) int { return cmp.Compare(a.Start, b.Start) })
+	return viewings
+}
+
+func (r *ViewingRepository) Reschedule(_ context.Context, viewing *models.Viewing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.viewings[viewing.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if existing.Status != models.ViewingScheduled {
+		return repository.ErrConflict
+	}
+	existing.SlotID = viewing.SlotID
+	existing.Start = viewing.Start
+	existing.End = viewing.End
+	existing.RemindedAt = nil
+	existing.UpdatedAt = viewing.UpdatedAt
+	return nil
+}
+
+func (r *ViewingRepository) Cancel(_ context.Context, id primitive.ObjectID, cancellation models.ViewingCancellation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	viewing, ok := r.viewings[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if viewing.Status != models.ViewingScheduled {
+		return repository.ErrConflict
+	}
+	viewing.Status = models.ViewingCancelled
+	viewing.Cancellation = &cancellation
+	viewing.UpdatedAt = cancellation.At
+	return nil
+}
+
+func (r *ViewingRepository) ClaimReminders(_ context.Context, now, startBefore time.Time) ([]models.Viewing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, until := primitive.NewDateTimeFromTime(now), primitive.NewDateTimeFromTime(startBefore)
+	reminded := primitive.NewDateTimeFromTime(now)
+	viewings := []models.Viewing{}
+	for _, id := range sortedIDs(r.viewings) {
+		viewing := r.viewings[id]
+		if viewing.Status != models.ViewingScheduled || viewing.RemindedAt != nil || viewing.Start < from || viewing.Start >= until {
+			continue
+		}
+		viewing.RemindedAt = &reminded
+		viewings = append(viewings, *cloneViewing(viewing))
+	}
+	return viewings, nil
+}
+
+// cloneViewing copies the viewing so callers never share pointers with the store
+func cloneViewing(viewing *models.Viewing) *models.Viewing {
+	c := *viewing
+	c.Cancellation = clonePtr(viewing.Cancellation)
+	c.RemindedAt = clonePtr(viewing.RemindedAt)
+	return &c
+}
+
+// overlaps reports whether the period from start to end overlaps the one
+// from from to to. Zero bounds are open.
+func overlaps(start, end primitive.DateTime, from, to time.Time) bool {
+	if !from.IsZero() && end <= primitive.NewDateTimeFromTime(from) {
+		return false
+	}
+	if !to.IsZero() && start >= primitive.NewDateTimeFromTime(to) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks map[primitive.ObjectID]*models.Webhook
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{webhooks: map[primitive.ObjectID]*models.Webhook{}}
+}
+
+func (r *WebhookRepository) Create(_ context.Context, webhook *models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if webhook.ID.IsZero() {
+		webhook.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.webhooks[webhook.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.webhooks[webhook.ID] = cloneWebhook(webhook)
+	return nil
+}
+
+func (r *WebhookRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneWebhook(webhook), nil
+}
+
+func (r *WebhookRepository) List(_ context.Context, filter repository.WebhookFilter, page repository.Page) ([]models.Webhook, error) {
+	return paginate(r.list(filter), page, func(w *models.Webhook) (float64, primitive.ObjectID) { return repository.ByCreation(w.ID) }), nil
+}
+
+func (r *WebhookRepository) Count(_ context.Context, filter repository.WebhookFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *WebhookRepository) list(filter repository.WebhookFilter) []models.Webhook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := []models.Webhook{}
+	for _, id := range sortedIDs(r.webhooks) {
+		webhook := r.webhooks[id]
+		if filter.Event != "" && !webhook.Subscribes(filter.Event) {
+			continue
+		}
+		webhooks = append(webhooks, *cloneWebhook(webhook))
+	}
+	return webhooks
+}
+
+func (r *WebhookRepository) Update(_ context.Context, webhook *models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.webhooks[webhook.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	existing.URL = webhook.URL
+	existing.Description = webhook.Description
+	existing.Events = slices.Clone(webhook.Events)
+	existing.Active = webhook.Active
+	existing.UpdatedAt = webhook.UpdatedAt
+	return nil
+}
+
+func (r *WebhookRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.webhooks, id)
+	return nil
+}
+
+// cloneWebhook copies the webhook so callers never share slices with the store
+func cloneWebhook(webhook *models.Webhook) *models.Webhook {
+	c := *webhook
+	c.Events = slices.Clone(webhook.Events)
+	return &c
+}
+
+type WebhookDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries map[primitive.ObjectID]*models.WebhookDelivery
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{deliveries: map[primitive.ObjectID]*models.WebhookDelivery{}}
+}
+
+func (r *WebhookDeliveryRepository) Create(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	if _, exists := r.deliveries[delivery.ID]; exists {
+		return repository.ErrDuplicate
+	}
+	r.deliveries[delivery.ID] = cloneDelivery(delivery)
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) FindByID(_ context.Context, id primitive.ObjectID) (*models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneDelivery(delivery), nil
+}
+
+func (r *WebhookDeliveryRepository) List(_ context.Context, filter repository.WebhookDeliveryFilter, page repository.Page) ([]models.WebhookDelivery, error) {
+	deliveries := r.list(filter)
+	slices.Reverse(deliveries) // newest first
+	return paginate(deliveries, page, func(d *models.WebhookDelivery) (float64, primitive.ObjectID) { return repository.ByCreation(d.ID) }), nil
+}
+
+func (r *WebhookDeliveryRepository) Count(_ context.Context, filter repository.WebhookDeliveryFilter) (int64, error) {
+	return int64(len(r.list(filter))), nil
+}
+
+func (r *WebhookDeliveryRepository) list(filter repository.WebhookDeliveryFilter) []models.WebhookDelivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, id := range sortedIDs(r.deliveries) {
+		delivery := r.deliveries[id]
+		if !filter.WebhookID.IsZero() && delivery.WebhookID != filter.WebhookID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, *cloneDelivery(delivery))
+	}
+	return deliveries
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(_ context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := primitive.NewDateTimeFromTime(now)
+	var claimed []*models.WebhookDelivery
+	for _, id := range sortedIDs(r.deliveries) {
+		delivery := r.deliveries[id]
+		if delivery.Status == models.DeliveryPending && delivery.NextAttemptAt != nil && *delivery.NextAttemptAt <= due {
+			claimed = append(claimed, delivery)
+		}
+	}
+	slices.SortStableFunc(claimed, func(a, b *models.WebhookDelivery) int { return cmp.Compare(*a.NextAttemptAt, *b.NextAttemptAt) })
+	if len(claimed) > limit {
+		claimed = claimed[:limit]
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	postponed := primitive.NewDateTimeFromTime(until)
+	for _, delivery := range claimed {
+		delivery.NextAttemptAt = &postponed
+		deliveries = append(deliveries, *cloneDelivery(delivery))
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) RecordAttempt(_ context.Context, id primitive.ObjectID, attempt models.WebhookAttempt, status models.WebhookDeliveryStatus, next *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Status = status
+	delivery.NextAttemptAt = nil
+	if next != nil {
+		at := primitive.NewDateTimeFromTime(*next)
+		delivery.NextAttemptAt = &at
+	}
+	delivery.UpdatedAt = attempt.At
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) DeleteByWebhook(_ context.Context, webhookID primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			delete(r.deliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// cloneDelivery copies the delivery so callers never share pointers or slices with the store
+func cloneDelivery(delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	c := *delivery
+	c.RedeliveryOf = clonePtr(delivery.RedeliveryOf)
+	c.NextAttemptAt = clonePtr(delivery.NextAttemptAt)
+	c.Attempts = slices.Clone(delivery.Attempts)
+	return &c
+}
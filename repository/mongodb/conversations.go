@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ConversationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewConversationRepository(db *mongo.Database) *ConversationRepository {
+	return &ConversationRepository{collection: db.Collection(conversationsCollection)}
+}
+
+func (r *ConversationRepository) Create(ctx context.Context, conversation *models.Conversation) error {
+	if conversation.ID.IsZero() {
+		conversation.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, conversation)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *ConversationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Conversation, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *ConversationRepository) FindByPropertyAndTenant(ctx context.Context, propertyID, tenantID primitive.ObjectID) (*models.Conversation, error) {
+	return r.findOne(ctx, bson.M{"property_id": propertyID, "tenant_id": tenantID})
+}
+
+func (r *ConversationRepository) findOne(ctx context.Context, filter bson.M) (*models.Conversation, error) {
+	var conversation models.Conversation
+	if err := r.collection.FindOne(ctx, filter).Decode(&conversation); err != nil {
+		return nil, notFound(err)
+	}
+	return &conversation, nil
+}
+
+func (r *ConversationRepository) List(ctx context.Context, filter repository.ConversationFilter, page repository.Page) ([]models.Conversation, error) {
+	query, opts := pageQuery(conversationQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := []models.Conversation{}
+	if err := cursor.All(ctx, &conversations); err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+func (r *ConversationRepository) Count(ctx context.Context, filter repository.ConversationFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, conversationQuery(filter))
+}
+
+func conversationQuery(filter repository.ConversationFilter) bson.M {
+	query := bson.M{}
+	if !filter.UserID.IsZero() {
+		query["$or"] = bson.A{bson.M{"owner_id": filter.UserID}, bson.M{"tenant_id": filter.UserID}}
+	}
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	return query
+}
+
+func (r *ConversationRepository) RecordMessage(ctx context.Context, message *models.Message, preview string) error {
+	conversation, err := r.FindByID(ctx, message.ConversationID)
+	if err != nil {
+		return err
+	}
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": conversation.ID}, bson.M{
+		"$set": bson.M{
+			"last_message":    preview,
+			"last_message_at": message.CreatedAt,
+			"updated_at":      message.CreatedAt,
+		},
+		"$max": bson.M{readAtField(conversation, message.SenderID): message.CreatedAt},
+	}))
+}
+
+func (r *ConversationRepository) MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) error {
+	conversation, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !conversation.IsParticipant(userID) {
+		return repository.ErrNotFound
+	}
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$max": bson.M{readAtField(conversation, userID): primitive.NewDateTimeFromTime(at)},
+	}))
+}
+
+// readAtField returns the field holding the participant's read receipt
+func readAtField(conversation *models.Conversation, userID primitive.ObjectID) string {
+	if userID == conversation.OwnerID {
+		return "owner_read_at"
+	}
+	return "tenant_read_at"
+}
+
+type MessageRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMessageRepository(db *mongo.Database) *MessageRepository {
+	return &MessageRepository{collection: db.Collection(messagesCollection)}
+}
+
+func (r *MessageRepository) Create(ctx context.Context, message *models.Message) error {
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, message)
+	return err
+}
+
+func (r *MessageRepository) List(ctx context.Context, filter repository.MessageFilter, page repository.Page) ([]models.Message, error) {
+	query, opts := pageQuery(messageQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []models.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) Count(ctx context.Context, filter repository.MessageFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, messageQuery(filter))
+}
+
+func messageQuery(filter repository.MessageFilter) bson.M {
+	query := bson.M{}
+	if !filter.ConversationID.IsZero() {
+		query["conversation_id"] = filter.ConversationID
+	}
+	if !filter.SentAfter.IsZero() {
+		query["created_at"] = bson.M{"$gt": primitive.NewDateTimeFromTime(filter.SentAfter)}
+	}
+	if !filter.NotFrom.IsZero() {
+		query["sender_id"] = bson.M{"$ne": filter.NotFrom}
+	}
+	return query
+}
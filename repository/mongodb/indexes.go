@@ -0,0 +1,162 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"dwello-api/models"
+	"dwello-api/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the repositories rely on. Creating an
+// index that already exists is a no-op, so it is safe to run on each start.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(propertiesCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "location", Value: "text"},
+		},
+		Options: options.Index().SetName("property_text").SetWeights(bson.M{
+			"title":       textsearch.TitleWeight,
+			"location":    textsearch.LocationWeight,
+			"description": textsearch.DescriptionWeight,
+		}),
+	}, {
+		// Used by nearby searches
+		Keys: bson.D{{Key: "coordinates", Value: "2dsphere"}},
+	}, {
+		// Used by the saved search matcher to find new listings
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(alertsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		// One alert per saved search and property, even when two matcher runs overlap
+		Keys:    bson.D{{Key: "saved_search_id", Value: 1}, {Key: "property_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("alert_unique"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(conversationsCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		// One conversation per property and tenant
+		Keys:    bson.D{{Key: "property_id", Value: 1}, {Key: "tenant_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bson.D{{Key: "owner_id", Value: 1}},
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(leasesCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		// One active lease per property, even when two requests are accepted at once
+		Keys: bson.D{{Key: "property_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("active_lease_unique").
+			SetPartialFilterExpression(bson.M{"status": models.LeaseActive}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(invoicesCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		// One invoice per lease and billing period, even when two ledger runs overlap
+		Keys:    bson.D{{Key: "lease_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("invoice_key_unique"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Used to page through the messages of a conversation
+	_, err = db.Collection(messagesCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "conversation_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Used to page through a user's notifications
+	_, err = db.Collection(notificationsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(rentalRequestsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		// One pending request per applicant and property
+		Keys: bson.D{{Key: "property_id", Value: 1}, {Key: "applicant_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("pending_request_unique").
+			SetPartialFilterExpression(bson.M{"status": models.RentalRequestPending}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(usersCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		// One account per email, which users are looked up by
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("email_unique"),
+	}, {
+		// Used to move a device's push token to the account that registers it
+		Keys: bson.D{{Key: "push_tokens", Value: 1}},
+	}, {
+		// Used to find the user a calendar feed belongs to
+		Keys:    bson.D{{Key: "credentials.calendar_token_hash", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}})
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("several users share an email, merge or remove the extra accounts before starting (see the readme): %w", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Used to list the upcoming slots of a property and find an owner's overlapping slots
+	_, err = db.Collection(viewingSlotsCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		Keys: bson.D{{Key: "property_id", Value: 1}, {Key: "start", Value: 1}},
+	}, {
+		Keys: bson.D{{Key: "owner_email", Value: 1}, {Key: "start", Value: 1}},
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(viewingsCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		Keys: bson.D{{Key: "visitor_id", Value: 1}, {Key: "start", Value: 1}},
+	}, {
+		Keys: bson.D{{Key: "owner_email", Value: 1}, {Key: "start", Value: 1}},
+	}, {
+		// Used by the reminder job
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "start", Value: 1}},
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(propertyChangesCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		// Used to page through the changes of a property
+		Keys: bson.D{{Key: "property_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(webhookDeliveriesCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{{
+		// Used by the delivery worker to find what is due
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+	}, {
+		// Used to page through the delivery log of a webhook
+		Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "_id", Value: -1}},
+	}})
+	return err
+}
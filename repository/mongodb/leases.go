@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LeaseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLeaseRepository(db *mongo.Database) *LeaseRepository {
+	return &LeaseRepository{collection: db.Collection(leasesCollection)}
+}
+
+func (r *LeaseRepository) Create(ctx context.Context, lease *models.Lease) error {
+	if lease.ID.IsZero() {
+		lease.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, lease)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *LeaseRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *LeaseRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Lease, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *LeaseRepository) FindActiveByProperty(ctx context.Context, propertyID primitive.ObjectID) (*models.Lease, error) {
+	return r.findOne(ctx, bson.M{"property_id": propertyID, "status": models.LeaseActive})
+}
+
+func (r *LeaseRepository) List(ctx context.Context, filter repository.LeaseFilter, page repository.Page) ([]models.Lease, error) {
+	query, opts := pageQuery(leaseQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	return r.find(ctx, query, opts)
+}
+
+func (r *LeaseRepository) Count(ctx context.Context, filter repository.LeaseFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, leaseQuery(filter))
+}
+
+func leaseQuery(filter repository.LeaseFilter) bson.M {
+	query := bson.M{}
+	if !filter.TenantID.IsZero() {
+		query["tenant_id"] = filter.TenantID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
+func (r *LeaseRepository) FindDue(ctx context.Context, now time.Time) ([]models.Lease, error) {
+	return r.find(ctx, bson.M{
+		"status":   models.LeaseActive,
+		"end_date": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	})
+}
+
+func (r *LeaseRepository) Update(ctx context.Context, lease *models.Lease) error {
+	version := lease.Version
+	lease.Version++
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": lease.ID, "version": version}, lease)
+	if err != nil {
+		lease.Version = version
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	lease.Version = version
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": lease.ID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrConflict
+}
+
+func (r *LeaseRepository) findOne(ctx context.Context, filter bson.M) (*models.Lease, error) {
+	var lease models.Lease
+	if err := r.collection.FindOne(ctx, filter).Decode(&lease); err != nil {
+		return nil, notFound(err)
+	}
+	return &lease, nil
+}
+
+func (r *LeaseRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]models.Lease, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	leases := []models.Lease{}
+	if err := cursor.All(ctx, &leases); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
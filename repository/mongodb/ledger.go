@@ -0,0 +1,184 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InvoiceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInvoiceRepository(db *mongo.Database) *InvoiceRepository {
+	return &InvoiceRepository{collection: db.Collection(invoicesCollection)}
+}
+
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	if invoice.ID.IsZero() {
+		invoice.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, invoice)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *InvoiceRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&invoice); err != nil {
+		return nil, notFound(err)
+	}
+	return &invoice, nil
+}
+
+func (r *InvoiceRepository) List(ctx context.Context, filter repository.InvoiceFilter, page repository.Page) ([]models.Invoice, error) {
+	query, opts := pageQuery(invoiceQuery(filter), page, invoiceOrder)
+	return r.find(ctx, query, opts)
+}
+
+func (r *InvoiceRepository) Count(ctx context.Context, filter repository.InvoiceFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, invoiceQuery(filter))
+}
+
+func invoiceQuery(filter repository.InvoiceFilter) bson.M {
+	query := bson.M{}
+	if !filter.LeaseID.IsZero() {
+		query["lease_id"] = filter.LeaseID
+	}
+	if !filter.TenantID.IsZero() {
+		query["tenant_id"] = filter.TenantID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
+func (r *InvoiceRepository) FindOverdue(ctx context.Context, dueBefore time.Time) ([]models.Invoice, error) {
+	return r.find(ctx, bson.M{
+		"kind":             models.InvoiceRent,
+		"status":           models.InvoiceOpen,
+		"late_fee_applied": bson.M{"$ne": true},
+		"due_date":         bson.M{"$lt": primitive.NewDateTimeFromTime(dueBefore)},
+	}, options.Find().SetSort(invoiceOrder))
+}
+
+func (r *InvoiceRepository) MarkLateFeeApplied(ctx context.Context, id primitive.ObjectID) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"late_fee_applied": true}}))
+}
+
+func (r *InvoiceRepository) ApplyPayment(ctx context.Context, invoice *models.Invoice, amount float64) error {
+	updated := *invoice
+	updated.AmountPaid += amount
+	updated.Status = models.InvoiceOpen
+	if updated.Settled() {
+		updated.Status = models.InvoicePaid
+	}
+	updated.UpdatedAt = primitive.NewDateTimeFromTime(utils.Now())
+
+	// Only apply the payment on top of the amount the caller has seen
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": invoice.ID, "amount_paid": invoice.AmountPaid},
+		bson.M{"$set": bson.M{
+			"amount_paid": updated.AmountPaid,
+			"status":      updated.Status,
+			"updated_at":  updated.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		*invoice = updated
+		return nil
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": invoice.ID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrConflict
+}
+
+// invoiceOrder is the natural order of invoices, by due date
+var invoiceOrder = bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}
+
+func (r *InvoiceRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Invoice, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := []models.Invoice{}
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+type PaymentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPaymentRepository(db *mongo.Database) *PaymentRepository {
+	return &PaymentRepository{collection: db.Collection(paymentsCollection)}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, payment)
+	return err
+}
+
+func (r *PaymentRepository) List(ctx context.Context, filter repository.PaymentFilter, page repository.Page) ([]models.Payment, error) {
+	query, opts := pageQuery(paymentQuery(filter), page, bson.D{{Key: "paid_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := []models.Payment{}
+	if err := cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) Count(ctx context.Context, filter repository.PaymentFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, paymentQuery(filter))
+}
+
+func paymentQuery(filter repository.PaymentFilter) bson.M {
+	query := bson.M{}
+	if !filter.LeaseID.IsZero() {
+		query["lease_id"] = filter.LeaseID
+	}
+	if !filter.InvoiceID.IsZero() {
+		query["invoice_id"] = filter.InvoiceID
+	}
+	if !filter.TenantID.IsZero() {
+		query["tenant_id"] = filter.TenantID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	return query
+}
@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migrate upgrades documents written by older versions of the API. Every
+// step is idempotent, so it is safe to run on each start.
+func Migrate(ctx context.Context, db *mongo.Database) error {
+	if err := migrateLegacyRentalRequests(ctx, db); err != nil {
+		return err
+	}
+	return migrateRentedByID(ctx, db)
+}
+
+// migrateLegacyRentalRequests turns the rental_requests ID arrays that used to
+// live on properties and users into pending documents in the rental_requests
+// collection, then drops the arrays.
+func migrateLegacyRentalRequests(ctx context.Context, db *mongo.Database) error {
+	properties := db.Collection(propertiesCollection)
+	users := NewUserRepository(db)
+	requests := NewRentalRequestRepository(db)
+
+	cursor, err := properties.Find(ctx, bson.M{"rental_requests.0": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+
+	var legacy []struct {
+		ID             primitive.ObjectID   `bson:"_id"`
+		OwnerEmail     string               `bson:"owner_email"`
+		RentalRequests []primitive.ObjectID `bson:"rental_requests"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	imported := 0
+	for _, property := range legacy {
+		for _, applicantID := range property.RentalRequests {
+			applicant, err := users.FindByID(ctx, applicantID)
+			if err != nil {
+				continue // the applicant no longer exists
+			}
+
+			err = requests.Create(ctx, &models.RentalRequest{
+				PropertyID:     property.ID,
+				OwnerEmail:     property.OwnerEmail,
+				ApplicantID:    applicant.ID,
+				ApplicantEmail: applicant.Email,
+				ApplicantName:  applicant.Name,
+				Status:         models.RentalRequestPending,
+				History: []models.StatusChange{{
+					Status: models.RentalRequestPending,
+					By:     applicant.Email,
+					Note:   "Imported from an earlier version",
+					At:     now,
+				}},
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if errors.Is(err, repository.ErrDuplicate) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			imported++
+		}
+	}
+
+	unset := bson.M{"$unset": bson.M{"rental_requests": ""}}
+	filter := bson.M{"rental_requests": bson.M{"$exists": true}}
+	if _, err := properties.UpdateMany(ctx, filter, unset); err != nil {
+		return err
+	}
+	if _, err := db.Collection(usersCollection).UpdateMany(ctx, filter, unset); err != nil {
+		return err
+	}
+
+	if imported > 0 {
+		log.Printf("Imported %d legacy rental requests", imported)
+	}
+	return nil
+}
+
+// migrateRentedByID replaces the rented_by_id field written by earlier
+// versions with the rented_by_email field of models.Property.
+func migrateRentedByID(ctx context.Context, db *mongo.Database) error {
+	properties := db.Collection(propertiesCollection)
+	users := NewUserRepository(db)
+
+	cursor, err := properties.Find(ctx, bson.M{"rented_by_id": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+
+	var legacy []struct {
+		ID         primitive.ObjectID `bson:"_id"`
+		RentedByID primitive.ObjectID `bson:"rented_by_id"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return err
+	}
+
+	for _, property := range legacy {
+		update := bson.M{"$unset": bson.M{"rented_by_id": ""}}
+		if renter, err := users.FindByID(ctx, property.RentedByID); err == nil {
+			update["$set"] = bson.M{"rented_by_email": renter.Email}
+		}
+		if _, err := properties.UpdateOne(ctx, bson.M{"_id": property.ID}, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
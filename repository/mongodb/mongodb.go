@@ -0,0 +1,71 @@
+// Package mongodb implements the repository interfaces on top of MongoDB.
+package mongodb
+
+import (
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	usersCollection             = "users"
+	propertiesCollection        = "properties"
+	propertyChangesCollection   = "property_changes"
+	rentalRequestsCollection    = "rental_requests"
+	leasesCollection            = "leases"
+	invoicesCollection          = "invoices"
+	paymentsCollection          = "payments"
+	savedSearchesCollection     = "saved_searches"
+	alertsCollection            = "alerts"
+	similaritiesCollection      = "similar_properties"
+	conversationsCollection     = "conversations"
+	messagesCollection          = "messages"
+	notificationsCollection     = "notifications"
+	webhooksCollection          = "webhooks"
+	webhookDeliveriesCollection = "webhook_deliveries"
+	viewingSlotsCollection      = "viewing_slots"
+	viewingsCollection          = "viewings"
+)
+
+// NewStore returns a repository.Store backed by the given database.
+func NewStore(db *mongo.Database) repository.Store {
+	return repository.Store{
+		Transactor:      NewTransactor(db),
+		Users:           NewUserRepository(db),
+		Properties:      NewPropertyRepository(db),
+		PropertyChanges: NewPropertyChangeRepository(db),
+		RentalRequests:  NewRentalRequestRepository(db),
+		Leases:          NewLeaseRepository(db),
+		Invoices:        NewInvoiceRepository(db),
+		Payments:        NewPaymentRepository(db),
+		SavedSearches:   NewSavedSearchRepository(db),
+		Alerts:          NewAlertRepository(db),
+		Similarities:    NewSimilarityRepository(db),
+		Conversations:   NewConversationRepository(db),
+		Messages:        NewMessageRepository(db),
+		Notifications:   NewNotificationRepository(db),
+		Webhooks:        NewWebhookRepository(db),
+		Deliveries:      NewWebhookDeliveryRepository(db),
+		ViewingSlots:    NewViewingSlotRepository(db),
+		Viewings:        NewViewingRepository(db),
+	}
+}
+
+// notFound maps the driver's "no documents" error to repository.ErrNotFound
+func notFound(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// matched returns repository.ErrNotFound when an update matched nothing
+func matched(result *mongo.UpdateResult, err error) error {
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
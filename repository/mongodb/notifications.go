@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{collection: db.Collection(notificationsCollection)}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, notification)
+	return err
+}
+
+func (r *NotificationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&notification); err != nil {
+		return nil, notFound(err)
+	}
+	return &notification, nil
+}
+
+func (r *NotificationRepository) List(ctx context.Context, filter repository.NotificationFilter, page repository.Page) ([]models.Notification, error) {
+	query, opts := pageQuery(notificationQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) Count(ctx context.Context, filter repository.NotificationFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, notificationQuery(filter))
+}
+
+func notificationQuery(filter repository.NotificationFilter) bson.M {
+	query := bson.M{}
+	if !filter.UserID.IsZero() {
+		query["user_id"] = filter.UserID
+	}
+	if filter.Unread {
+		query["read_at"] = bson.M{"$exists": false}
+	}
+	return query
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": primitive.NewDateTimeFromTime(at)}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		// Either already read or missing
+		_, err = r.FindByID(ctx, id)
+	}
+	return err
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": primitive.NewDateTimeFromTime(at)}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pageQuery narrows the query to the items following the page's cursor and
+// returns the options sorting and limiting it. Lists other than properties
+// only sort by creation time, which the ObjectID follows. Pages without a
+// sort use natural, the list's usual order.
+func pageQuery(query bson.M, page repository.Page, natural bson.D) (bson.M, *options.FindOptions) {
+	opts := options.Find().SetLimit(page.Limit)
+	if page.Sort == "" {
+		if natural != nil {
+			opts.SetSort(natural)
+		}
+		return query, opts
+	}
+
+	direction, after := pageDirection(page)
+	if page.After != nil {
+		query = bson.M{"$and": bson.A{query, bson.M{"_id": bson.M{after: page.After.ID}}}}
+	}
+	return query, opts.SetSort(bson.D{{Key: "_id", Value: direction}})
+}
+
+// pageDirection returns the sort direction of the page and the operator
+// selecting the items after its cursor
+func pageDirection(page repository.Page) (int, string) {
+	if page.Desc {
+		return -1, "$lt"
+	}
+	return 1, "$gt"
+}
+
+// propertySortKey returns the expression computing the sort value of a
+// property, or nil when sorting by creation time
+func propertySortKey(sort repository.SortField) any {
+	switch sort {
+	case repository.SortPrice:
+		return bson.M{"$ifNull": bson.A{"$price", 0}}
+	case repository.SortLikes:
+		return bson.M{"$size": bson.M{"$ifNull": bson.A{"$liked_by", bson.A{}}}}
+	case repository.SortRelevance:
+		return "$score"
+	case repository.SortDistance:
+		return "$distance"
+	}
+	return nil
+}
+
+// propertyPageStages returns the aggregation stages that sort a property
+// search, skip to the page's cursor and apply its limit
+func propertyPageStages(page repository.Page) mongo.Pipeline {
+	direction, after := pageDirection(page)
+
+	var stages mongo.Pipeline
+	sort := bson.D{{Key: "_id", Value: direction}}
+	if key := propertySortKey(page.Sort); key != nil {
+		stages = append(stages, bson.D{{Key: "$addFields", Value: bson.M{"sort_key": key}}})
+		sort = bson.D{{Key: "sort_key", Value: direction}, {Key: "_id", Value: direction}}
+		if page.After != nil {
+			stages = append(stages, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+				bson.M{"sort_key": bson.M{after: page.After.Value}},
+				bson.M{"sort_key": page.After.Value, "_id": bson.M{after: page.After.ID}},
+			}}}})
+		}
+	} else if page.After != nil {
+		stages = append(stages, bson.D{{Key: "$match", Value: bson.M{"_id": bson.M{after: page.After.ID}}}})
+	}
+
+	stages = append(stages, bson.D{{Key: "$sort", Value: sort}})
+	if page.Limit > 0 {
+		stages = append(stages, bson.D{{Key: "$limit", Value: page.Limit}})
+	}
+	return stages
+}
@@ -0,0 +1,431 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PropertyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPropertyRepository(db *mongo.Database) *PropertyRepository {
+	return &PropertyRepository{collection: db.Collection(propertiesCollection)}
+}
+
+func (r *PropertyRepository) Create(ctx context.Context, property *models.Property) error {
+	_, err := r.collection.InsertOne(ctx, property)
+	return err
+}
+
+func (r *PropertyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Property, error) {
+	var property models.Property
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&property); err != nil {
+		return nil, notFound(err)
+	}
+	return &property, nil
+}
+
+// UpdateListing sets only the named fields so that server-owned ones, such
+// as likes changed meanwhile, are never overwritten. Fields the property
+// leaves out of its document are unset.
+func (r *PropertyRepository) UpdateListing(ctx context.Context, property *models.Property, fields []string, since primitive.DateTime) error {
+	data, err := bson.Marshal(property)
+	if err != nil {
+		return err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	set, unset := bson.M{"updated_at": property.UpdatedAt}, bson.M{}
+	for _, field := range fields {
+		if !slices.Contains(repository.ListingFields, field) {
+			return fmt.Errorf("%s is not a listing field", field)
+		}
+		if value, ok := doc[field]; ok {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	// Listings created before updated_at existed have none
+	filter := bson.M{"_id": property.ID, "updated_at": since}
+	if since == 0 {
+		filter["updated_at"] = nil
+	}
+	return r.updateIf(ctx, property.ID, filter, update)
+}
+
+// updateIf applies update to the property if it matches filter. It returns
+// ErrNotFound when the property does not exist and ErrConflict when it does
+// not match.
+func (r *PropertyRepository) updateIf(ctx context.Context, id primitive.ObjectID, filter, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrConflict
+}
+
+func (r *PropertyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PropertyRepository) All(ctx context.Context) ([]models.Property, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *PropertyRepository) Search(ctx context.Context, search repository.PropertySearch, page repository.Page) ([]models.PropertySearchResult, error) {
+	stages, err := searchStages(search)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := append(stages, propertyPageStages(page)...)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []models.PropertySearchResult{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *PropertyRepository) Count(ctx context.Context, search repository.PropertySearch) (int64, error) {
+	stages, err := searchStages(search)
+	if err != nil {
+		return 0, err
+	}
+	pipeline := append(stages, bson.D{{Key: "$count", Value: "count"}})
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var result []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &result); err != nil || len(result) == 0 {
+		return 0, err
+	}
+	return result[0].Count, nil
+}
+
+func (r *PropertyRepository) Facets(ctx context.Context, search repository.PropertySearch) (*models.PropertyFacets, error) {
+	stages, err := searchStages(search)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy listings have no attributes, count them like zero values
+	bedrooms := bson.M{"$ifNull": bson.A{"$attributes.bedrooms", 0}}
+	count := func(field any) bson.A { return bson.A{bson.M{"$sortByCount": field}} }
+	pipeline := append(stages, bson.D{{Key: "$facet", Value: bson.M{
+		"type":         count("$attributes.type"),
+		"bedrooms":     count(bedrooms),
+		"bathrooms":    count(bson.M{"$ifNull": bson.A{"$attributes.bathrooms", 0}}),
+		"furnishing":   count("$attributes.furnishing"),
+		"pets_allowed": count(bson.M{"$ifNull": bson.A{"$attributes.pets_allowed", false}}),
+		"amenities":    bson.A{bson.M{"$unwind": "$attributes.amenities"}, bson.M{"$sortByCount": "$attributes.amenities"}},
+		"location":     count("$location"),
+		"bedrooms_by_location": bson.A{bson.M{"$group": bson.M{
+			"_id":   bson.M{"location": "$location", "bedrooms": bedrooms},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucket struct {
+		ID    any   `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	var result []struct {
+		Type               []bucket `bson:"type"`
+		Bedrooms           []bucket `bson:"bedrooms"`
+		Bathrooms          []bucket `bson:"bathrooms"`
+		Furnishing         []bucket `bson:"furnishing"`
+		PetsAllowed        []bucket `bson:"pets_allowed"`
+		Amenities          []bucket `bson:"amenities"`
+		Location           []bucket `bson:"location"`
+		BedroomsByLocation []struct {
+			ID struct {
+				Location string `bson:"location"`
+				Bedrooms int    `bson:"bedrooms"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		} `bson:"bedrooms_by_location"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	facets := models.NewPropertyFacets()
+	if len(result) == 0 {
+		return facets, nil
+	}
+	counts := func(into map[string]int64, buckets []bucket) {
+		for _, b := range buckets {
+			if b.ID != nil && b.ID != "" {
+				into[fmt.Sprint(b.ID)] = b.Count
+			}
+		}
+	}
+	counts(facets.Type, result[0].Type)
+	counts(facets.Bedrooms, result[0].Bedrooms)
+	counts(facets.Bathrooms, result[0].Bathrooms)
+	counts(facets.Furnishing, result[0].Furnishing)
+	counts(facets.PetsAllowed, result[0].PetsAllowed)
+	counts(facets.Amenities, result[0].Amenities)
+	counts(facets.Location, result[0].Location)
+	for _, b := range result[0].BedroomsByLocation {
+		facets.AddBedroomsByLocation(b.ID.Location, b.ID.Bedrooms, b.Count)
+	}
+	return facets, nil
+}
+
+// searchStages returns the aggregation stages selecting the properties
+// matching the search, with their score or distance when searching with a
+// query or near a point. $geoNear has to be the first stage of an
+// aggregation, so the rest of the filter moves into it. Its query cannot
+// hold $text, which is why a search near a point cannot have a Query.
+func searchStages(search repository.PropertySearch) (mongo.Pipeline, error) {
+	if err := search.Check(); err != nil {
+		return nil, err
+	}
+	filter := searchFilter(search)
+	if search.Near != nil {
+		return mongo.Pipeline{{{Key: "$geoNear", Value: bson.M{
+			"near":          search.Near,
+			"distanceField": "distance",
+			"maxDistance":   search.MaxDistance,
+			"query":         filter,
+			"spherical":     true,
+		}}}}, nil
+	}
+
+	stages := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if search.Query != "" {
+		stages = append(stages, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	return stages, nil
+}
+
+// searchFilter turns the search into a query. Near is left to searchStages
+// because it needs a $geoNear stage.
+func searchFilter(search repository.PropertySearch) bson.M {
+	filter := bson.M{}
+	if len(search.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": search.IDs}
+	}
+	if search.RentedBy != "" {
+		filter["rented_by_email"] = search.RentedBy
+	}
+	if search.Available {
+		filter["is_rented"] = bson.M{"$ne": true}
+	}
+	if search.NotOwnedBy != "" {
+		filter["owner_email"] = bson.M{"$ne": search.NotOwnedBy}
+	}
+	if !search.CreatedAfter.IsZero() {
+		filter["created_at"] = bson.M{"$gt": primitive.NewDateTimeFromTime(search.CreatedAfter)}
+	}
+	location := bson.M{}
+	if len(search.Locations) > 0 {
+		location["$in"] = search.Locations
+	}
+	if search.Location != "" {
+		location["$regex"] = regexp.QuoteMeta(search.Location)
+		location["$options"] = "i"
+	}
+	if len(location) > 0 {
+		filter["location"] = location
+	}
+	if search.Query != "" {
+		// Uses the text index created by EnsureIndexes
+		filter["$text"] = bson.M{"$search": search.Query}
+	}
+	if r := numberRange(search.MinPrice, search.MaxPrice); r != nil {
+		filter["price"] = r
+	}
+
+	if len(search.Types) > 0 {
+		filter["attributes.type"] = bson.M{"$in": search.Types}
+	}
+	if r := numberRange(search.MinBedrooms, search.MaxBedrooms); r != nil {
+		filter["attributes.bedrooms"] = r
+	}
+	if r := numberRange(search.MinBathrooms, nil); r != nil {
+		filter["attributes.bathrooms"] = r
+	}
+	if r := numberRange(search.MinArea, search.MaxArea); r != nil {
+		filter["attributes.area_sqft"] = r
+	}
+	if len(search.Furnishing) > 0 {
+		filter["attributes.furnishing"] = bson.M{"$in": search.Furnishing}
+	}
+	if search.PetsAllowed != nil {
+		if *search.PetsAllowed {
+			filter["attributes.pets_allowed"] = true
+		} else {
+			filter["attributes.pets_allowed"] = bson.M{"$ne": true}
+		}
+	}
+	if len(search.Amenities) > 0 {
+		filter["attributes.amenities"] = bson.M{"$all": search.Amenities}
+	}
+
+	if search.Box != nil {
+		filter["coordinates"] = bson.M{"$geoWithin": bson.M{"$box": legacyBox(search.Box)}}
+	}
+	return filter
+}
+
+// numberRange returns a $gte/$lte condition, or nil when both bounds are nil
+func numberRange[T int | float64](lo, hi *T) bson.M {
+	r := bson.M{}
+	if lo != nil {
+		r["$gte"] = *lo
+	}
+	if hi != nil {
+		r["$lte"] = *hi
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+// legacyBox returns the box as legacy coordinate pairs. Unlike a GeoJSON
+// polygon, whose edges follow great circles, $box is flat: its edges follow
+// the lines of latitude and longitude, as GeoBox.Contains does.
+func legacyBox(box *models.GeoBox) bson.A {
+	return bson.A{
+		bson.A{box.MinLng, box.MinLat},
+		bson.A{box.MaxLng, box.MaxLat},
+	}
+}
+
+func (r *PropertyRepository) AddLike(ctx context.Context, propertyID primitive.ObjectID, email string) error {
+	return r.updateByID(ctx, propertyID, bson.M{"$addToSet": bson.M{"liked_by": email}})
+}
+
+func (r *PropertyRepository) RemoveLike(ctx context.Context, propertyID primitive.ObjectID, email string) error {
+	return r.updateByID(ctx, propertyID, bson.M{"$pull": bson.M{"liked_by": email}})
+}
+
+func (r *PropertyRepository) MarkRented(ctx context.Context, propertyID primitive.ObjectID, renterEmail string) error {
+	filter := bson.M{"_id": propertyID, "is_rented": bson.M{"$ne": true}}
+	return r.updateIf(ctx, propertyID, filter, bson.M{
+		"$set": bson.M{
+			"is_rented":       true,
+			"rented_by_email": renterEmail,
+		},
+	})
+}
+
+func (r *PropertyRepository) MarkAvailable(ctx context.Context, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, propertyID, bson.M{
+		"$set":   bson.M{"is_rented": false},
+		"$unset": bson.M{"rented_by_email": ""},
+	})
+}
+
+func (r *PropertyRepository) AddImage(ctx context.Context, propertyID primitive.ObjectID, image *models.Image) error {
+	err := r.updateByID(ctx, propertyID, bson.M{"$push": bson.M{
+		"images":   image,
+		"pictures": image.Variants[models.ImageLarge],
+	}})
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": propertyID, "thumbnail": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"thumbnail": image.Variants[models.ImageThumbnail]}},
+	)
+	return err
+}
+
+func (r *PropertyRepository) RemoveImage(ctx context.Context, propertyID, imageID primitive.ObjectID) error {
+	property, err := r.FindByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	thumbnail := property.Thumbnail
+	image, found := property.RemoveImage(imageID)
+	if !found {
+		return repository.ErrNotFound
+	}
+
+	// Pulling rather than setting the lists keeps images added meanwhile
+	update := bson.M{"$pull": bson.M{
+		"images":   bson.M{"_id": imageID},
+		"pictures": image.Variants[models.ImageLarge],
+	}}
+	if property.Thumbnail != thumbnail {
+		update["$set"] = bson.M{"thumbnail": property.Thumbnail}
+	}
+	return r.updateByID(ctx, propertyID, update)
+}
+
+func (r *PropertyRepository) SetOwnerPic(ctx context.Context, ownerEmail, pic string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"owner_email": ownerEmail}, bson.M{"$set": bson.M{"owner_pic": pic}})
+	return err
+}
+
+func (r *PropertyRepository) updateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, update))
+}
+
+func (r *PropertyRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]models.Property, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := []models.Property{}
+	if err := cursor.All(ctx, &properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"context"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PropertyChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPropertyChangeRepository(db *mongo.Database) *PropertyChangeRepository {
+	return &PropertyChangeRepository{collection: db.Collection(propertyChangesCollection)}
+}
+
+func (r *PropertyChangeRepository) Create(ctx context.Context, change *models.PropertyChange) error {
+	if change.ID.IsZero() {
+		change.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, change)
+	return err
+}
+
+func (r *PropertyChangeRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PropertyChangeRepository) List(ctx context.Context, filter repository.PropertyChangeFilter, page repository.Page) ([]models.PropertyChange, error) {
+	query, opts := pageQuery(propertyChangeQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []models.PropertyChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *PropertyChangeRepository) Count(ctx context.Context, filter repository.PropertyChangeFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, propertyChangeQuery(filter))
+}
+
+func propertyChangeQuery(filter repository.PropertyChangeFilter) bson.M {
+	query := bson.M{}
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	return query
+}
@@ -0,0 +1,139 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RentalRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRentalRequestRepository(db *mongo.Database) *RentalRequestRepository {
+	return &RentalRequestRepository{collection: db.Collection(rentalRequestsCollection)}
+}
+
+func (r *RentalRequestRepository) Create(ctx context.Context, request *models.RentalRequest) error {
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, request)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *RentalRequestRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.RentalRequest, error) {
+	var request models.RentalRequest
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&request); err != nil {
+		return nil, notFound(err)
+	}
+	return &request, nil
+}
+
+func (r *RentalRequestRepository) List(ctx context.Context, filter repository.RentalRequestFilter, page repository.Page) ([]models.RentalRequest, error) {
+	query, opts := pageQuery(rentalRequestQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := []models.RentalRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *RentalRequestRepository) Count(ctx context.Context, filter repository.RentalRequestFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, rentalRequestQuery(filter))
+}
+
+func rentalRequestQuery(filter repository.RentalRequestFilter) bson.M {
+	query := bson.M{}
+	if !filter.ApplicantID.IsZero() {
+		query["applicant_id"] = filter.ApplicantID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
+func (r *RentalRequestRepository) Transition(ctx context.Context, id primitive.ObjectID, from models.RentalRequestStatus, change models.StatusChange) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": from},
+		bson.M{
+			"$set":  bson.M{"status": change.Status, "updated_at": change.At},
+			"$push": bson.M{"history": change},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Tell a missing request apart from one that has already moved on
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrConflict
+}
+
+func (r *RentalRequestRepository) ExpirePending(ctx context.Context, createdBefore, now time.Time) ([]models.RentalRequest, error) {
+	at := primitive.NewDateTimeFromTime(now)
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"status": models.RentalRequestPending,
+		"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": primitive.NewDateTimeFromTime(createdBefore)}},
+			bson.M{"move_in_date": bson.M{"$lt": at}},
+		},
+	}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	candidates := []models.RentalRequest{}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	// Requests decided or withdrawn in the meantime are skipped
+	change := models.StatusChange{Status: models.RentalRequestExpired, At: at}
+	expired := []models.RentalRequest{}
+	for _, request := range candidates {
+		err := r.Transition(ctx, request.ID, models.RentalRequestPending, change)
+		if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		request.Status = change.Status
+		request.UpdatedAt = change.At
+		request.History = append(request.History, change)
+		expired = append(expired, request)
+	}
+	return expired, nil
+}
@@ -0,0 +1,164 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SavedSearchRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSavedSearchRepository(db *mongo.Database) *SavedSearchRepository {
+	return &SavedSearchRepository{collection: db.Collection(savedSearchesCollection)}
+}
+
+func (r *SavedSearchRepository) Create(ctx context.Context, search *models.SavedSearch) error {
+	if search.ID.IsZero() {
+		search.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, search)
+	return err
+}
+
+func (r *SavedSearchRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&search); err != nil {
+		return nil, notFound(err)
+	}
+	return &search, nil
+}
+
+func (r *SavedSearchRepository) List(ctx context.Context, filter repository.SavedSearchFilter, page repository.Page) ([]models.SavedSearch, error) {
+	query, opts := pageQuery(savedSearchQuery(filter), page, bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := []models.SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (r *SavedSearchRepository) Count(ctx context.Context, filter repository.SavedSearchFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, savedSearchQuery(filter))
+}
+
+func savedSearchQuery(filter repository.SavedSearchFilter) bson.M {
+	query := bson.M{}
+	if !filter.UserID.IsZero() {
+		query["user_id"] = filter.UserID
+	}
+	return query
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SavedSearchRepository) SetCheckedUntil(ctx context.Context, id primitive.ObjectID, until primitive.DateTime) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"checked_until": until,
+		"updated_at":    primitive.NewDateTimeFromTime(utils.Now()),
+	}}))
+}
+
+type AlertRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAlertRepository(db *mongo.Database) *AlertRepository {
+	return &AlertRepository{collection: db.Collection(alertsCollection)}
+}
+
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, alert)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *AlertRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Alert, error) {
+	var alert models.Alert
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&alert); err != nil {
+		return nil, notFound(err)
+	}
+	return &alert, nil
+}
+
+func (r *AlertRepository) List(ctx context.Context, filter repository.AlertFilter, page repository.Page) ([]models.Alert, error) {
+	query, opts := pageQuery(alertQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []models.Alert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *AlertRepository) Count(ctx context.Context, filter repository.AlertFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, alertQuery(filter))
+}
+
+func alertQuery(filter repository.AlertFilter) bson.M {
+	query := bson.M{}
+	if !filter.UserID.IsZero() {
+		query["user_id"] = filter.UserID
+	}
+	if filter.Unread {
+		query["read_at"] = bson.M{"$exists": false}
+	}
+	return query
+}
+
+func (r *AlertRepository) MarkRead(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": primitive.NewDateTimeFromTime(at)}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		// Either already read or missing
+		_, err = r.FindByID(ctx, id)
+	}
+	return err
+}
+
+func (r *AlertRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": primitive.NewDateTimeFromTime(at)}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
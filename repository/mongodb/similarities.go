@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SimilarityRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSimilarityRepository(db *mongo.Database) *SimilarityRepository {
+	return &SimilarityRepository{collection: db.Collection(similaritiesCollection)}
+}
+
+func (r *SimilarityRepository) Find(ctx context.Context, propertyID primitive.ObjectID) (*models.SimilarProperties, error) {
+	var similar models.SimilarProperties
+	if err := r.collection.FindOne(ctx, bson.M{"_id": propertyID}).Decode(&similar); err != nil {
+		return nil, notFound(err)
+	}
+	return &similar, nil
+}
+
+func (r *SimilarityRepository) Save(ctx context.Context, similar *models.SimilarProperties) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": similar.PropertyID}, similar, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *SimilarityRepository) DeleteComputedBefore(ctx context.Context, t time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"computed_at": bson.M{"$lt": primitive.NewDateTimeFromTime(t)}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
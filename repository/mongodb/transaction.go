@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor uses multi-document transactions when the server supports them
+// (replica sets and sharded clusters) and falls back to compensating actions
+// on standalone servers.
+type Transactor struct {
+	db *mongo.Database
+
+	mu        sync.Mutex
+	detected  bool
+	supported bool
+}
+
+func NewTransactor(db *mongo.Database) *Transactor {
+	return &Transactor{db: db}
+}
+
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx *repository.Tx) error) error {
+	if !t.transactionsSupported(ctx) {
+		return repository.WithCompensation(ctx, fn)
+	}
+
+	session, err := t.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		// The transaction is aborted on error, so compensating actions are not needed
+		return nil, fn(sessCtx, &repository.Tx{})
+	})
+	return err
+}
+
+// transactionsSupported asks the server whether it is part of a replica set
+// or sharded cluster. Only a successful answer is kept, so a detection that
+// failed, for example because the request's context was cancelled, is retried
+// by the next transaction.
+func (t *Transactor) transactionsSupported(ctx context.Context) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.detected {
+		return t.supported
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := t.db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		// Servers older than 4.4.2 only know the legacy command
+		err = t.db.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello)
+	}
+	if err != nil {
+		log.Println("Could not detect transaction support, using compensating actions:", err)
+		return false
+	}
+
+	t.detected = true
+	t.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !t.supported {
+		log.Println("MongoDB is a standalone server, using compensating actions instead of transactions")
+	}
+	return t.supported
+}
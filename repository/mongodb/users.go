@@ -0,0 +1,224 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{collection: db.Collection(usersCollection)}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *UserRepository) FindByCalendarToken(ctx context.Context, hash string) (*models.User, error) {
+	if hash == "" {
+		return nil, repository.ErrNotFound
+	}
+	return r.findOne(ctx, bson.M{"credentials.calendar_token_hash": hash})
+}
+
+func (r *UserRepository) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+	if err := r.collection.FindOne(ctx, filter).Decode(&user); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter, page repository.Page) ([]models.User, error) {
+	query, opts := pageQuery(userQuery(filter), page, nil)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Count(ctx context.Context, filter repository.UserFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, userQuery(filter))
+}
+
+func userQuery(filter repository.UserFilter) bson.M {
+	query := bson.M{}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	return query
+}
+
+func (r *UserRepository) UpdateLocation(ctx context.Context, email, location string, coordinates *models.GeoPoint) error {
+	if coordinates == nil {
+		return matched(r.collection.UpdateOne(ctx, bson.M{"email": email}, bson.M{
+			"$set":   bson.M{"location": location, "updated_at": primitive.NewDateTimeFromTime(utils.Now())},
+			"$unset": bson.M{"coordinates": ""},
+		}))
+	}
+	return r.setByEmail(ctx, email, bson.M{"location": location, "coordinates": coordinates})
+}
+
+func (r *UserRepository) UpdatePreferredLocations(ctx context.Context, email string, locations []string) error {
+	return r.setByEmail(ctx, email, bson.M{"preferred_locations": locations})
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, email string, role models.Role) error {
+	return r.setByEmail(ctx, email, bson.M{"role": role})
+}
+
+func (r *UserRepository) SetProfileImage(ctx context.Context, id primitive.ObjectID, image *models.Image) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"profile_image": image,
+		"profile_pic":   image.Variants[models.ImageMedium],
+		"updated_at":    primitive.NewDateTimeFromTime(utils.Now()),
+	}}))
+}
+
+func (r *UserRepository) SetNotificationPreferences(ctx context.Context, id primitive.ObjectID, preferences models.NotificationPreferences) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{
+		"notification_preferences": preferences,
+		"updated_at":               primitive.NewDateTimeFromTime(utils.Now()),
+	}})
+}
+
+func (r *UserRepository) AddPushToken(ctx context.Context, id primitive.ObjectID, token string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$ne": id}, "push_tokens": token},
+		bson.M{"$pull": bson.M{"push_tokens": token}},
+	)
+	if err != nil {
+		return err
+	}
+	return r.updateByID(ctx, id, bson.M{"$addToSet": bson.M{"push_tokens": token}})
+}
+
+func (r *UserRepository) RemovePushToken(ctx context.Context, id primitive.ObjectID, token string) error {
+	return r.updateByID(ctx, id, bson.M{"$pull": bson.M{"push_tokens": token}})
+}
+
+// setByEmail sets the given fields and bumps updated_at
+func (r *UserRepository) setByEmail(ctx context.Context, email string, fields bson.M) error {
+	fields["updated_at"] = primitive.NewDateTimeFromTime(utils.Now())
+	return matched(r.collection.UpdateOne(ctx, bson.M{"email": email}, bson.M{"$set": fields}))
+}
+
+func (r *UserRepository) AddPostedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$push": bson.M{"posted_properties": propertyID}})
+}
+
+func (r *UserRepository) RemovePostedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$pull": bson.M{"posted_properties": propertyID}})
+}
+
+func (r *UserRepository) AddLike(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$addToSet": bson.M{"liked_properties": propertyID}})
+}
+
+func (r *UserRepository) RemoveLike(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$pull": bson.M{"liked_properties": propertyID}})
+}
+
+func (r *UserRepository) AddRentedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$addToSet": bson.M{"rented_properties": propertyID}})
+}
+
+func (r *UserRepository) RemoveRentedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error {
+	return r.updateByID(ctx, userID, bson.M{"$pull": bson.M{"rented_properties": propertyID}})
+}
+
+func (r *UserRepository) SetPassword(ctx context.Context, id primitive.ObjectID, hash string) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"credentials.password_hash": hash,
+			"updated_at":                primitive.NewDateTimeFromTime(utils.Now()),
+		},
+		"$inc": bson.M{"credentials.token_version": 1},
+		"$unset": bson.M{
+			"credentials.reset_token_hash": "",
+			"credentials.reset_expires_at": "",
+			"credentials.failed_attempts":  "",
+			"credentials.locked_until":     "",
+		},
+	})
+}
+
+func (r *UserRepository) SetOTP(ctx context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"credentials.otp_hash":       hash,
+			"credentials.otp_expires_at": primitive.NewDateTimeFromTime(expiresAt),
+		},
+	})
+}
+
+func (r *UserRepository) SetResetToken(ctx context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"credentials.reset_token_hash": hash,
+			"credentials.reset_expires_at": primitive.NewDateTimeFromTime(expiresAt),
+		},
+	})
+}
+
+func (r *UserRepository) SetCalendarToken(ctx context.Context, id primitive.ObjectID, hash string) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{"credentials.calendar_token_hash": hash}})
+}
+
+func (r *UserRepository) IncrementFailedAttempts(ctx context.Context, id primitive.ObjectID) error {
+	return r.updateByID(ctx, id, bson.M{"$inc": bson.M{"credentials.failed_attempts": 1}})
+}
+
+func (r *UserRepository) Lock(ctx context.Context, id primitive.ObjectID, until time.Time) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"credentials.failed_attempts": 0,
+			"credentials.locked_until":    primitive.NewDateTimeFromTime(until),
+		},
+	})
+}
+
+func (r *UserRepository) ClearLockout(ctx context.Context, id primitive.ObjectID, clearOTP bool) error {
+	unset := bson.M{
+		"credentials.failed_attempts": "",
+		"credentials.locked_until":    "",
+	}
+	if clearOTP {
+		unset["credentials.otp_hash"] = ""
+		unset["credentials.otp_expires_at"] = ""
+	}
+	return r.updateByID(ctx, id, bson.M{"$unset": unset})
+}
+
+func (r *UserRepository) updateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, update))
+}
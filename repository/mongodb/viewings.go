@@ -0,0 +1,255 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// byStart is the natural order of slots and viewings
+var byStart = bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}
+
+type ViewingSlotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewViewingSlotRepository(db *mongo.Database) *ViewingSlotRepository {
+	return &ViewingSlotRepository{collection: db.Collection(viewingSlotsCollection)}
+}
+
+func (r *ViewingSlotRepository) Create(ctx context.Context, slot *models.ViewingSlot) error {
+	if slot.ID.IsZero() {
+		slot.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, slot)
+	return err
+}
+
+func (r *ViewingSlotRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ViewingSlot, error) {
+	var slot models.ViewingSlot
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&slot); err != nil {
+		return nil, notFound(err)
+	}
+	return &slot, nil
+}
+
+func (r *ViewingSlotRepository) List(ctx context.Context, filter repository.ViewingSlotFilter, page repository.Page) ([]models.ViewingSlot, error) {
+	query, opts := pageQuery(slotQuery(filter), page, byStart)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []models.ViewingSlot{}
+	if err := cursor.All(ctx, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (r *ViewingSlotRepository) Count(ctx context.Context, filter repository.ViewingSlotFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, slotQuery(filter))
+}
+
+func slotQuery(filter repository.ViewingSlotFilter) bson.M {
+	query := overlapQuery(filter.From, filter.To)
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	if filter.Available {
+		query["viewing_id"] = nil
+	}
+	return query
+}
+
+func (r *ViewingSlotRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "viewing_id": nil})
+	if err != nil {
+		return err
+	}
+	return conditional(ctx, r.collection, id, result.DeletedCount > 0)
+}
+
+func (r *ViewingSlotRepository) Book(ctx context.Context, id, viewingID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "viewing_id": nil},
+		bson.M{"$set": bson.M{"viewing_id": viewingID}},
+	)
+	if err != nil {
+		return err
+	}
+	return conditional(ctx, r.collection, id, result.MatchedCount > 0)
+}
+
+func (r *ViewingSlotRepository) Release(ctx context.Context, id, viewingID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "viewing_id": viewingID},
+		bson.M{"$unset": bson.M{"viewing_id": ""}},
+	)
+	return err
+}
+
+type ViewingRepository struct {
+	collection *mongo.Collection
+}
+
+func NewViewingRepository(db *mongo.Database) *ViewingRepository {
+	return &ViewingRepository{collection: db.Collection(viewingsCollection)}
+}
+
+func (r *ViewingRepository) Create(ctx context.Context, viewing *models.Viewing) error {
+	if viewing.ID.IsZero() {
+		viewing.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, viewing)
+	return err
+}
+
+func (r *ViewingRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Viewing, error) {
+	var viewing models.Viewing
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&viewing); err != nil {
+		return nil, notFound(err)
+	}
+	return &viewing, nil
+}
+
+func (r *ViewingRepository) List(ctx context.Context, filter repository.ViewingFilter, page repository.Page) ([]models.Viewing, error) {
+	query, opts := pageQuery(viewingQuery(filter), page, byStart)
+	return r.find(ctx, query, opts)
+}
+
+func (r *ViewingRepository) Count(ctx context.Context, filter repository.ViewingFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, viewingQuery(filter))
+}
+
+func viewingQuery(filter repository.ViewingFilter) bson.M {
+	query := overlapQuery(filter.From, filter.To)
+	if !filter.VisitorID.IsZero() {
+		query["visitor_id"] = filter.VisitorID
+	}
+	if filter.OwnerEmail != "" {
+		query["owner_email"] = filter.OwnerEmail
+	}
+	if !filter.PropertyID.IsZero() {
+		query["property_id"] = filter.PropertyID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
+func (r *ViewingRepository) find(ctx context.Context, query bson.M, opts *options.FindOptions) ([]models.Viewing, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	viewings := []models.Viewing{}
+	if err := cursor.All(ctx, &viewings); err != nil {
+		return nil, err
+	}
+	return viewings, nil
+}
+
+func (r *ViewingRepository) Reschedule(ctx context.Context, viewing *models.Viewing) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": viewing.ID, "status": models.ViewingScheduled},
+		bson.M{
+			"$set": bson.M{
+				"slot_id":    viewing.SlotID,
+				"start":      viewing.Start,
+				"end":        viewing.End,
+				"updated_at": viewing.UpdatedAt,
+			},
+			"$unset": bson.M{"reminded_at": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return conditional(ctx, r.collection, viewing.ID, result.MatchedCount > 0)
+}
+
+func (r *ViewingRepository) Cancel(ctx context.Context, id primitive.ObjectID, cancellation models.ViewingCancellation) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.ViewingScheduled},
+		bson.M{"$set": bson.M{
+			"status":       models.ViewingCancelled,
+			"cancellation": cancellation,
+			"updated_at":   cancellation.At,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	return conditional(ctx, r.collection, id, result.MatchedCount > 0)
+}
+
+// ClaimReminders marks the viewings one at a time so that each reminder is
+// sent by a single worker even when several instances run the job
+func (r *ViewingRepository) ClaimReminders(ctx context.Context, now, startBefore time.Time) ([]models.Viewing, error) {
+	query := bson.M{
+		"status":      models.ViewingScheduled,
+		"reminded_at": nil,
+		"start": bson.M{
+			"$gte": primitive.NewDateTimeFromTime(now),
+			"$lt":  primitive.NewDateTimeFromTime(startBefore),
+		},
+	}
+	update := bson.M{"$set": bson.M{"reminded_at": primitive.NewDateTimeFromTime(now)}}
+	opts := options.FindOneAndUpdate().SetSort(byStart).SetReturnDocument(options.After)
+
+	viewings := []models.Viewing{}
+	for {
+		var viewing models.Viewing
+		err := r.collection.FindOneAndUpdate(ctx, query, update, opts).Decode(&viewing)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return viewings, nil
+		}
+		if err != nil {
+			return viewings, err
+		}
+		viewings = append(viewings, viewing)
+	}
+}
+
+// overlapQuery selects the documents whose start and end overlap the period
+// from from to to. Zero bounds are open.
+func overlapQuery(from, to time.Time) bson.M {
+	query := bson.M{}
+	if !from.IsZero() {
+		query["end"] = bson.M{"$gt": primitive.NewDateTimeFromTime(from)}
+	}
+	if !to.IsZero() {
+		query["start"] = bson.M{"$lt": primitive.NewDateTimeFromTime(to)}
+	}
+	return query
+}
+
+// conditional returns nil when a conditional write matched, and otherwise
+// tells a missing document apart from one in another state
+func conditional(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, matched bool) error {
+	if matched {
+		return nil
+	}
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrConflict
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{collection: db.Collection(webhooksCollection)}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.ID.IsZero() {
+		webhook.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, webhook)
+	return err
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook); err != nil {
+		return nil, notFound(err)
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context, filter repository.WebhookFilter, page repository.Page) ([]models.Webhook, error) {
+	query, opts := pageQuery(webhookQuery(filter), page, bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Count(ctx context.Context, filter repository.WebhookFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, webhookQuery(filter))
+}
+
+func webhookQuery(filter repository.WebhookFilter) bson.M {
+	query := bson.M{}
+	if filter.Event != "" {
+		query["active"] = true
+		query["events"] = filter.Event
+	}
+	return query
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": webhook.ID}, bson.M{"$set": bson.M{
+		"url":         webhook.URL,
+		"description": webhook.Description,
+		"events":      webhook.Events,
+		"active":      webhook.Active,
+		"updated_at":  webhook.UpdatedAt,
+	}}))
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{collection: db.Collection(webhookDeliveriesCollection)}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, delivery)
+	return err
+}
+
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery); err != nil {
+		return nil, notFound(err)
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter, page repository.Page) ([]models.WebhookDelivery, error) {
+	query, opts := pageQuery(deliveryQuery(filter), page, bson.D{{Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) Count(ctx context.Context, filter repository.WebhookDeliveryFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, deliveryQuery(filter))
+}
+
+func deliveryQuery(filter repository.WebhookDeliveryFilter) bson.M {
+	query := bson.M{}
+	if !filter.WebhookID.IsZero() {
+		query["webhook_id"] = filter.WebhookID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
+
+// ClaimDue postpones the deliveries one at a time so that each is claimed by
+// a single worker even when several instances poll the queue
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := bson.M{
+		"status":          models.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": primitive.NewDateTimeFromTime(until)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	deliveries := []models.WebhookDelivery{}
+	for len(deliveries) < limit {
+		var delivery models.WebhookDelivery
+		err := r.collection.FindOneAndUpdate(ctx, query, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt models.WebhookAttempt, status models.WebhookDeliveryStatus, next *time.Time) error {
+	update := bson.M{
+		"$push": bson.M{"attempts": attempt},
+		"$set":  bson.M{"status": status, "updated_at": attempt.At},
+	}
+	if next != nil {
+		update["$set"].(bson.M)["next_attempt_at"] = primitive.NewDateTimeFromTime(*next)
+	} else {
+		update["$unset"] = bson.M{"next_attempt_at": ""}
+	}
+	return matched(r.collection.UpdateOne(ctx, bson.M{"_id": id}, update))
+}
+
+func (r *WebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"webhook_id": webhookID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for cursors it did not issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField is a key lists can be sorted by
+type SortField string
+
+const (
+	// SortCreatedAt sorts by creation time, following the ObjectID
+	SortCreatedAt SortField = "created_at"
+	SortPrice     SortField = "price"
+	// SortLikes sorts by the number of users who liked a property
+	SortLikes SortField = "likes"
+	// SortRelevance sorts by text score and requires a search query
+	SortRelevance SortField = "relevance"
+	// SortDistance sorts by distance and requires a point to search around
+	SortDistance SortField = "distance"
+	// SortRank sorts by homescreen score, see the ranking package
+	SortRank SortField = "rank"
+)
+
+// Page selects up to Limit items following After, in Sort order. The zero
+// Page returns every item in the repository's natural order. Only property
+// searches support sort fields other than SortCreatedAt.
+type Page struct {
+	Sort  SortField
+	Desc  bool
+	After *Cursor
+	// Limit of zero means no limit
+	Limit int64
+}
+
+// Cursor marks the last item of a page. Value is that item's sort key; it
+// is unused when sorting by creation time since the ID already orders it.
+type Cursor struct {
+	Sort  SortField          `json:"s"`
+	Desc  bool               `json:"d,omitempty"`
+	Value float64            `json:"v,omitempty"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor handed out to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" || c.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Compare orders two items by sort value, then ID, reversed when Desc is
+// set. Values are ignored when sorting by creation time.
+func (p Page) Compare(aValue float64, aID primitive.ObjectID, bValue float64, bID primitive.ObjectID) int {
+	order := 0
+	if p.Sort != SortCreatedAt {
+		order = cmp.Compare(aValue, bValue)
+	}
+	if order == 0 {
+		order = bytes.Compare(aID[:], bID[:])
+	}
+	if p.Desc {
+		return -order
+	}
+	return order
+}
+
+// ByCreation returns the sort value and ID of an item of a list that only
+// sorts by creation time
+func ByCreation(id primitive.ObjectID) (float64, primitive.ObjectID) {
+	return 0, id
+}
+
+// PropertySortValue returns the value a property search result is sorted by
+func PropertySortValue(result *models.PropertySearchResult, sort SortField) float64 {
+	switch sort {
+	case SortPrice:
+		return result.Price
+	case SortLikes:
+		return float64(len(result.LikedBy))
+	case SortRelevance:
+		return result.Score
+	case SortDistance:
+		if result.Distance != nil {
+			return *result.Distance
+		}
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	tests := []Cursor{
+		{Sort: SortCreatedAt, Desc: true, ID: id},
+		{Sort: SortPrice, Value: 1250.5, ID: id},
+		{Sort: SortDistance, Value: 0, ID: id},
+		{Sort: SortLikes, Desc: true, Value: 3, ID: id},
+	}
+	for _, want := range tests {
+		t.Run(string(want.Sort), func(t *testing.T) {
+			got, err := DecodeCursor(want.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != want {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"not JSON", encode("cursor")},
+		{"no sort", encode(`{"id":"665f1c2e8f1b2a3c4d5e6f70"}`)},
+		{"no ID", encode(`{"s":"price","v":10}`)},
+		{"bad ID", encode(`{"s":"price","id":"nope"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestPageCompare(t *testing.T) {
+	older, newer := primitive.NewObjectID(), primitive.NewObjectID()
+	tests := []struct {
+		name   string
+		page   Page
+		aValue float64
+		aID    primitive.ObjectID
+		bValue float64
+		bID    primitive.ObjectID
+		want   int
+	}{
+		{"lower price first", Page{Sort: SortPrice}, 10, newer, 20, older, -1},
+		{"higher price first when descending", Page{Sort: SortPrice, Desc: true}, 10, newer, 20, older, 1},
+		{"ties broken by ID", Page{Sort: SortPrice}, 10, older, 10, newer, -1},
+		{"ties broken by ID when descending", Page{Sort: SortPrice, Desc: true}, 10, older, 10, newer, 1},
+		{"same item", Page{Sort: SortLikes}, 2, older, 2, older, 0},
+		{"creation time ignores values", Page{Sort: SortCreatedAt}, 20, older, 10, newer, -1},
+		{"newest first", Page{Sort: SortCreatedAt, Desc: true}, 0, older, 0, newer, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.Compare(tt.aValue, tt.aID, tt.bValue, tt.bID); got != tt.want {
+				t.Errorf("Compare() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertySortValue(t *testing.T) {
+	distance := 1500.0
+	result := &models.PropertySearchResult{
+		Property: models.Property{Price: 900, LikedBy: []string{"a@example.com", "b@example.com"}},
+		Score:    4.5,
+		Distance: &distance,
+	}
+	tests := []struct {
+		sort SortField
+		want float64
+	}{
+		{SortPrice, 900},
+		{SortLikes, 2},
+		{SortRelevance, 4.5},
+		{SortDistance, 1500},
+		{SortCreatedAt, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			if got := PropertySortValue(result, tt.sort); got != tt.want {
+				t.Errorf("PropertySortValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,505 @@
+// Package repository defines the persistence interfaces used by the handlers.
+// The mongodb sub-package implements them on top of MongoDB and the memory
+// sub-package provides an in-process implementation for tests and local runs.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"dwello-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotFound is returned when the requested document does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicate is returned when a unique field (such as a user's email) is already taken
+	ErrDuplicate = errors.New("duplicate")
+	// ErrConflict is returned when a conditional update finds the document in an unexpected state
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidSearch is returned for a search combining filters that cannot be used together
+	ErrInvalidSearch = errors.New("invalid search")
+)
+
+// Store groups every repository so they can be injected together.
+type Store struct {
+	Transactor      Transactor
+	Users           UserRepository
+	Properties      PropertyRepository
+	PropertyChanges PropertyChangeRepository
+	RentalRequests  RentalRequestRepository
+	Leases          LeaseRepository
+	Invoices        InvoiceRepository
+	Payments        PaymentRepository
+	SavedSearches   SavedSearchRepository
+	Alerts          AlertRepository
+	Similarities    SimilarityRepository
+	Conversations   ConversationRepository
+	Messages        MessageRepository
+	Notifications   NotificationRepository
+	Webhooks        WebhookRepository
+	Deliveries      WebhookDeliveryRepository
+	ViewingSlots    ViewingSlotRepository
+	Viewings        ViewingRepository
+}
+
+// UserFilter narrows down UserRepository.List. Zero fields are ignored.
+type UserFilter struct {
+	Role models.Role
+}
+
+type UserRepository interface {
+	// Create stores a new user. It returns ErrDuplicate when the email is
+	// taken, also by an account created at the same time.
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	// FindByCalendarToken returns the user whose calendar token has the given hash
+	FindByCalendarToken(ctx context.Context, hash string) (*models.User, error)
+	List(ctx context.Context, filter UserFilter, page Page) ([]models.User, error)
+	Count(ctx context.Context, filter UserFilter) (int64, error)
+
+	// UpdateLocation sets the location and its coordinates, clearing them when nil
+	UpdateLocation(ctx context.Context, email, location string, coordinates *models.GeoPoint) error
+	UpdatePreferredLocations(ctx context.Context, email string, locations []string) error
+	SetRole(ctx context.Context, email string, role models.Role) error
+	// SetProfileImage stores an uploaded profile picture and makes its medium variant the ProfilePic
+	SetProfileImage(ctx context.Context, id primitive.ObjectID, image *models.Image) error
+	SetNotificationPreferences(ctx context.Context, id primitive.ObjectID, preferences models.NotificationPreferences) error
+	// AddPushToken registers a device for push notifications, taking it away
+	// from any other account it was registered with
+	AddPushToken(ctx context.Context, id primitive.ObjectID, token string) error
+	RemovePushToken(ctx context.Context, id primitive.ObjectID, token string) error
+
+	AddPostedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error
+	RemovePostedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error
+	AddLike(ctx context.Context, userID, propertyID primitive.ObjectID) error
+	RemoveLike(ctx context.Context, userID, propertyID primitive.ObjectID) error
+	AddRentedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error
+	RemoveRentedProperty(ctx context.Context, userID, propertyID primitive.ObjectID) error
+
+	// SetPassword stores a new password hash and clears any reset token and
+	// lockout. It bumps the token version, which signs the user out everywhere.
+	SetPassword(ctx context.Context, id primitive.ObjectID, hash string) error
+	SetOTP(ctx context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error
+	SetResetToken(ctx context.Context, id primitive.ObjectID, hash string, expiresAt time.Time) error
+	// SetCalendarToken replaces the user's calendar token, revoking the feed URLs of the previous one
+	SetCalendarToken(ctx context.Context, id primitive.ObjectID, hash string) error
+	// IncrementFailedAttempts records a failed login or code verification
+	IncrementFailedAttempts(ctx context.Context, id primitive.ObjectID) error
+	// Lock locks the account until the given time and resets the failure counter
+	Lock(ctx context.Context, id primitive.ObjectID, until time.Time) error
+	// ClearLockout resets the failure counter and lock after a successful login.
+	// When clearOTP is set the one-time code is consumed as well.
+	ClearLockout(ctx context.Context, id primitive.ObjectID, clearOTP bool) error
+}
+
+// PropertySearch holds the filters of property lists and searches. Zero
+// fields are ignored.
+type PropertySearch struct {
+	// IDs limits the results to the given properties
+	IDs []primitive.ObjectID
+	// Locations limits the results to properties in exactly one of them
+	Locations []string
+	RentedBy  string
+	// Available limits the results to properties that are not rented
+	Available bool
+	// NotOwnedBy leaves out the properties listed by the given owner email
+	NotOwnedBy string
+	// CreatedAfter limits the results to properties created after it
+	CreatedAfter time.Time
+
+	// Query is matched against the title, description and location. When set,
+	// results are sorted by relevance.
+	Query string
+	// Location matches any location containing it, ignoring case
+	Location string
+	MinPrice *float64
+	MaxPrice *float64
+
+	// Attribute filters. Properties must match one of Types and Furnishing,
+	// and have every one of Amenities.
+	Types        []models.PropertyType
+	MinBedrooms  *int
+	MaxBedrooms  *int
+	MinBathrooms *int
+	MinArea      *float64
+	MaxArea      *float64
+	Furnishing   []models.Furnishing
+	PetsAllowed  *bool
+	Amenities    []string
+
+	// Near limits the results to properties within MaxDistance meters of the
+	// point and sorts them by distance. It cannot be combined with Query:
+	// MongoDB runs text searches and near-point searches in separate stages
+	// that cannot follow each other.
+	Near        *models.GeoPoint
+	MaxDistance float64
+	// Box limits the results to properties inside the box
+	Box *models.GeoBox
+}
+
+// Check reports the filters of the search that cannot be used together
+func (s PropertySearch) Check() error {
+	if s.Near != nil && s.Query != "" {
+		return fmt.Errorf("%w: Near cannot be combined with Query", ErrInvalidSearch)
+	}
+	return nil
+}
+
+type PropertyRepository interface {
+	Create(ctx context.Context, property *models.Property) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Property, error)
+	// UpdateListing copies the named fields of the listing, such as title or
+	// attributes, from property to the stored one along with UpdatedAt. It
+	// returns ErrConflict when the stored property was updated since it was
+	// read, its UpdatedAt no longer being since.
+	UpdateListing(ctx context.Context, property *models.Property, fields []string, since primitive.DateTime) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// All returns every property. Used by maintenance jobs, not by request handlers.
+	All(ctx context.Context) ([]models.Property, error)
+
+	// Search returns a page of the properties matching the search. Pages can
+	// be sorted by creation time, price and likes, by relevance when
+	// searching with a query and by distance when searching near a point.
+	// Search, Count and Facets return ErrInvalidSearch when the search fails
+	// PropertySearch.Check.
+	Search(ctx context.Context, search PropertySearch, page Page) ([]models.PropertySearchResult, error)
+	Count(ctx context.Context, search PropertySearch) (int64, error)
+	// Facets counts the properties matching the search by attribute
+	Facets(ctx context.Context, search PropertySearch) (*models.PropertyFacets, error)
+
+	AddLike(ctx context.Context, propertyID primitive.ObjectID, email string) error
+	RemoveLike(ctx context.Context, propertyID primitive.ObjectID, email string) error
+	// MarkRented marks the property as rented to renterEmail. It returns
+	// ErrConflict when the property is already rented.
+	MarkRented(ctx context.Context, propertyID primitive.ObjectID, renterEmail string) error
+	MarkAvailable(ctx context.Context, propertyID primitive.ObjectID) error
+
+	// AddImage adds an uploaded image to the property, see models.Property.AddImage
+	AddImage(ctx context.Context, propertyID primitive.ObjectID, image *models.Image) error
+	// RemoveImage removes an uploaded image from the property, see
+	// models.Property.RemoveImage. It returns ErrNotFound when the property
+	// has no such image.
+	RemoveImage(ctx context.Context, propertyID, imageID primitive.ObjectID) error
+	// SetOwnerPic updates the owner picture of every property of the owner
+	SetOwnerPic(ctx context.Context, ownerEmail, pic string) error
+}
+
+// ListingFields are the fields of a property its owner writes, by their
+// BSON name. The others are set by the server.
+var ListingFields = []string{"title", "description", "price", "location", "coordinates", "attributes", "thumbnail", "pictures"}
+
+// PropertyChangeFilter narrows down PropertyChangeRepository.List. Zero fields are ignored.
+type PropertyChangeFilter struct {
+	PropertyID primitive.ObjectID
+}
+
+// PropertyChangeRepository is the audit log of listing updates
+type PropertyChangeRepository interface {
+	Create(ctx context.Context, change *models.PropertyChange) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// List returns the matching changes, newest first unless the page is sorted
+	List(ctx context.Context, filter PropertyChangeFilter, page Page) ([]models.PropertyChange, error)
+	Count(ctx context.Context, filter PropertyChangeFilter) (int64, error)
+}
+
+// RentalRequestFilter narrows down RentalRequestRepository.List. Zero fields are ignored.
+type RentalRequestFilter struct {
+	ApplicantID primitive.ObjectID
+	OwnerEmail  string
+	PropertyID  primitive.ObjectID
+	Status      models.RentalRequestStatus
+}
+
+type RentalRequestRepository interface {
+	// Create stores a new request. It returns ErrDuplicate when the applicant
+	// already has a pending request for the same property.
+	Create(ctx context.Context, request *models.RentalRequest) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.RentalRequest, error)
+	// List returns the matching requests, newest first unless the page is sorted
+	List(ctx context.Context, filter RentalRequestFilter, page Page) ([]models.RentalRequest, error)
+	Count(ctx context.Context, filter RentalRequestFilter) (int64, error)
+	// Transition moves the request from status from to change.Status and appends
+	// change to its history. It returns ErrConflict when the request is no
+	// longer in status from.
+	Transition(ctx context.Context, id primitive.ObjectID, from models.RentalRequestStatus, change models.StatusChange) error
+	// ExpirePending expires pending requests created before createdBefore or whose
+	// move-in date is before now, and returns the expired requests
+	ExpirePending(ctx context.Context, createdBefore, now time.Time) ([]models.RentalRequest, error)
+}
+
+// LeaseFilter narrows down LeaseRepository.List. Zero fields are ignored.
+type LeaseFilter struct {
+	TenantID   primitive.ObjectID
+	OwnerEmail string
+	PropertyID primitive.ObjectID
+	Status     models.LeaseStatus
+}
+
+type LeaseRepository interface {
+	// Create stores a new lease. It returns ErrDuplicate when the property
+	// already has an active lease.
+	Create(ctx context.Context, lease *models.Lease) error
+	// Delete removes a lease. It only exists to undo Create.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Lease, error)
+	// FindActiveByProperty returns the property's active lease or ErrNotFound
+	FindActiveByProperty(ctx context.Context, propertyID primitive.ObjectID) (*models.Lease, error)
+	// List returns the matching leases, newest first unless the page is sorted
+	List(ctx context.Context, filter LeaseFilter, page Page) ([]models.Lease, error)
+	Count(ctx context.Context, filter LeaseFilter) (int64, error)
+	// FindDue returns the active leases whose end date is not after now
+	FindDue(ctx context.Context, now time.Time) ([]models.Lease, error)
+	// Update overwrites the stored lease if its version still matches and
+	// increments the version. It returns ErrConflict when the lease was
+	// changed in the meantime.
+	Update(ctx context.Context, lease *models.Lease) error
+}
+
+// InvoiceFilter narrows down InvoiceRepository.List. Zero fields are ignored.
+type InvoiceFilter struct {
+	LeaseID    primitive.ObjectID
+	TenantID   primitive.ObjectID
+	OwnerEmail string
+	Status     models.InvoiceStatus
+}
+
+type InvoiceRepository interface {
+	// Create stores a new invoice. It returns ErrDuplicate when the lease
+	// already has an invoice with the same key.
+	Create(ctx context.Context, invoice *models.Invoice) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Invoice, error)
+	// List returns the matching invoices ordered by due date unless the page is sorted
+	List(ctx context.Context, filter InvoiceFilter, page Page) ([]models.Invoice, error)
+	Count(ctx context.Context, filter InvoiceFilter) (int64, error)
+	// FindOverdue returns the open rent invoices due before dueBefore that
+	// have not been charged a late fee yet
+	FindOverdue(ctx context.Context, dueBefore time.Time) ([]models.Invoice, error)
+	// MarkLateFeeApplied flags the invoice as charged a late fee
+	MarkLateFeeApplied(ctx context.Context, id primitive.ObjectID) error
+	// ApplyPayment adds amount (which may be negative to undo a payment) to
+	// the amount paid and updates the status and the given invoice. It
+	// returns ErrConflict when the stored amount paid no longer matches.
+	ApplyPayment(ctx context.Context, invoice *models.Invoice, amount float64) error
+}
+
+// PaymentFilter narrows down PaymentRepository.List. Zero fields are ignored.
+type PaymentFilter struct {
+	LeaseID    primitive.ObjectID
+	InvoiceID  primitive.ObjectID
+	TenantID   primitive.ObjectID
+	OwnerEmail string
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	// List returns the matching payments ordered by payment date unless the page is sorted
+	List(ctx context.Context, filter PaymentFilter, page Page) ([]models.Payment, error)
+	Count(ctx context.Context, filter PaymentFilter) (int64, error)
+}
+
+// SavedSearchFilter narrows down SavedSearchRepository.List. Zero fields are ignored.
+type SavedSearchFilter struct {
+	UserID primitive.ObjectID
+}
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *models.SavedSearch) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.SavedSearch, error)
+	// List returns the matching saved searches, oldest first unless the page is sorted
+	List(ctx context.Context, filter SavedSearchFilter, page Page) ([]models.SavedSearch, error)
+	Count(ctx context.Context, filter SavedSearchFilter) (int64, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// SetCheckedUntil records the creation time of the newest listing matched against the search
+	SetCheckedUntil(ctx context.Context, id primitive.ObjectID, until primitive.DateTime) error
+}
+
+// AlertFilter narrows down AlertRepository.List. Zero fields are ignored.
+type AlertFilter struct {
+	UserID primitive.ObjectID
+	Unread bool
+}
+
+type AlertRepository interface {
+	// Create stores a new alert. It returns ErrDuplicate when the saved
+	// search already alerted of the property.
+	Create(ctx context.Context, alert *models.Alert) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Alert, error)
+	// List returns the matching alerts, newest first unless the page is sorted
+	List(ctx context.Context, filter AlertFilter, page Page) ([]models.Alert, error)
+	Count(ctx context.Context, filter AlertFilter) (int64, error)
+	// MarkRead marks the alert as read at the given time unless it already is
+	MarkRead(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// MarkAllRead marks every unread alert of the user as read and returns how many were
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error)
+}
+
+// NotificationFilter narrows down NotificationRepository.List. Zero fields are ignored.
+type NotificationFilter struct {
+	UserID primitive.ObjectID
+	Unread bool
+}
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Notification, error)
+	// List returns the matching notifications, newest first unless the page is sorted
+	List(ctx context.Context, filter NotificationFilter, page Page) ([]models.Notification, error)
+	Count(ctx context.Context, filter NotificationFilter) (int64, error)
+	// MarkRead marks the notification as read at the given time unless it already is
+	MarkRead(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// MarkAllRead marks every unread notification of the user as read and returns how many were
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID, at time.Time) (int64, error)
+}
+
+// WebhookFilter narrows down WebhookRepository.List. Zero fields are ignored.
+type WebhookFilter struct {
+	// Event selects the active webhooks subscribed to the event
+	Event models.WebhookEvent
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Webhook, error)
+	// List returns the matching webhooks, oldest first unless the page is sorted
+	List(ctx context.Context, filter WebhookFilter, page Page) ([]models.Webhook, error)
+	Count(ctx context.Context, filter WebhookFilter) (int64, error)
+	// Update replaces the URL, description, events and active flag of the webhook
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// WebhookDeliveryFilter narrows down WebhookDeliveryRepository.List. Zero fields are ignored.
+type WebhookDeliveryFilter struct {
+	WebhookID primitive.ObjectID
+	Status    models.WebhookDeliveryStatus
+}
+
+// WebhookDeliveryRepository is the queue of events to POST to webhooks
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.WebhookDelivery, error)
+	// List returns the matching deliveries, newest first unless the page is sorted
+	List(ctx context.Context, filter WebhookDeliveryFilter, page Page) ([]models.WebhookDelivery, error)
+	Count(ctx context.Context, filter WebhookDeliveryFilter) (int64, error)
+	// ClaimDue returns up to limit pending deliveries due at now, earliest
+	// first, and postpones them until the given time so no other worker picks
+	// them up meanwhile
+	ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error)
+	// RecordAttempt appends the attempt and moves the delivery to the status.
+	// next is the time of the next attempt of a delivery still pending.
+	RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt models.WebhookAttempt, status models.WebhookDeliveryStatus, next *time.Time) error
+	// DeleteByWebhook removes the deliveries of a deleted webhook
+	DeleteByWebhook(ctx context.Context, webhookID primitive.ObjectID) (int64, error)
+}
+
+// ViewingSlotFilter narrows down ViewingSlotRepository.List. Zero fields are ignored.
+type ViewingSlotFilter struct {
+	PropertyID primitive.ObjectID
+	OwnerEmail string
+	// From and To select the slots overlapping the period between them
+	From time.Time
+	To   time.Time
+	// Available selects the slots nobody has booked
+	Available bool
+}
+
+type ViewingSlotRepository interface {
+	Create(ctx context.Context, slot *models.ViewingSlot) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.ViewingSlot, error)
+	// List returns the matching slots ordered by start time unless the page is sorted
+	List(ctx context.Context, filter ViewingSlotFilter, page Page) ([]models.ViewingSlot, error)
+	Count(ctx context.Context, filter ViewingSlotFilter) (int64, error)
+	// Delete removes a slot. It returns ErrConflict when the slot is booked.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Book reserves the slot for the viewing. It returns ErrConflict when the
+	// slot is already booked.
+	Book(ctx context.Context, id, viewingID primitive.ObjectID) error
+	// Release frees the slot if it is still booked for the viewing
+	Release(ctx context.Context, id, viewingID primitive.ObjectID) error
+}
+
+// ViewingFilter narrows down ViewingRepository.List. Zero fields are ignored.
+type ViewingFilter struct {
+	VisitorID  primitive.ObjectID
+	OwnerEmail string
+	PropertyID primitive.ObjectID
+	Status     models.ViewingStatus
+	// From and To select the viewings overlapping the period between them
+	From time.Time
+	To   time.Time
+}
+
+type ViewingRepository interface {
+	Create(ctx context.Context, viewing *models.Viewing) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Viewing, error)
+	// List returns the matching viewings ordered by start time unless the page is sorted
+	List(ctx context.Context, filter ViewingFilter, page Page) ([]models.Viewing, error)
+	Count(ctx context.Context, filter ViewingFilter) (int64, error)
+	// Reschedule saves the slot, times and update time of the viewing and
+	// clears its reminder. It returns ErrConflict when the viewing is no
+	// longer scheduled.
+	Reschedule(ctx context.Context, viewing *models.Viewing) error
+	// Cancel cancels a scheduled viewing. It returns ErrConflict when the
+	// viewing is no longer scheduled.
+	Cancel(ctx context.Context, id primitive.ObjectID, cancellation models.ViewingCancellation) error
+	// ClaimReminders returns the scheduled viewings starting between now and
+	// startBefore that nobody has been reminded of, and marks them reminded
+	ClaimReminders(ctx context.Context, now, startBefore time.Time) ([]models.Viewing, error)
+}
+
+// SimilarityRepository stores the precomputed similar listings of each property
+type SimilarityRepository interface {
+	// Find returns the similar listings of a property, or ErrNotFound when
+	// they have not been computed yet
+	Find(ctx context.Context, propertyID primitive.ObjectID) (*models.SimilarProperties, error)
+	// Save replaces the similar listings of a property
+	Save(ctx context.Context, similar *models.SimilarProperties) error
+	// DeleteComputedBefore removes the lists computed before t, which belong
+	// to deleted properties once every list has been computed again
+	DeleteComputedBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// ConversationFilter selects conversations. Zero fields are ignored.
+type ConversationFilter struct {
+	// UserID selects the conversations the user takes part in
+	UserID     primitive.ObjectID
+	PropertyID primitive.ObjectID
+}
+
+type ConversationRepository interface {
+	// Create stores a new conversation. It returns ErrDuplicate when the
+	// tenant already has a conversation about the property.
+	Create(ctx context.Context, conversation *models.Conversation) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Conversation, error)
+	FindByPropertyAndTenant(ctx context.Context, propertyID, tenantID primitive.ObjectID) (*models.Conversation, error)
+	// List returns the matching conversations, newest first unless the page is sorted
+	List(ctx context.Context, filter ConversationFilter, page Page) ([]models.Conversation, error)
+	Count(ctx context.Context, filter ConversationFilter) (int64, error)
+	// RecordMessage makes the message the conversation's latest, keeping
+	// preview as its start, and marks the conversation as read by the sender
+	RecordMessage(ctx context.Context, message *models.Message, preview string) error
+	// MarkRead records that the participant has read the messages sent up to
+	// the given time. Read receipts never move back.
+	MarkRead(ctx context.Context, id, userID primitive.ObjectID, at time.Time) error
+}
+
+// MessageFilter selects the messages of a conversation. Zero fields are ignored.
+type MessageFilter struct {
+	ConversationID primitive.ObjectID
+	// SentAfter and NotFrom together select the messages a participant has not read
+	SentAfter time.Time
+	NotFrom   primitive.ObjectID
+}
+
+type MessageRepository interface {
+	Create(ctx context.Context, message *models.Message) error
+	// List returns the matching messages, newest first unless the page is sorted
+	List(ctx context.Context, filter MessageFilter, page Page) ([]models.Message, error)
+	Count(ctx context.Context, filter MessageFilter) (int64, error)
+}
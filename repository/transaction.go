@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"log"
+)
+
+// Transactor runs units of work that span several repositories.
+type Transactor interface {
+	// WithTransaction runs fn so that either all of its writes are applied or
+	// none are. Repositories must be called with the ctx passed to fn.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error
+}
+
+// Tx is handed to a unit of work. Stores without multi-document transactions
+// (standalone MongoDB servers, the in-memory store) cannot roll writes back,
+// so each write registers a compensating action that undoes it. When a real
+// transaction is available the compensating actions are never run.
+type Tx struct {
+	compensations []func(ctx context.Context) error
+}
+
+// OnRollback registers fn to undo a write that has just succeeded.
+func (tx *Tx) OnRollback(fn func(ctx context.Context) error) {
+	tx.compensations = append(tx.compensations, fn)
+}
+
+// rollback runs the compensating actions in reverse order. Failures are
+// logged and left for the reconcile command to repair.
+func (tx *Tx) rollback(ctx context.Context) {
+	for i := len(tx.compensations) - 1; i >= 0; i-- {
+		if err := tx.compensations[i](ctx); err != nil {
+			log.Println("Compensating action failed, run the reconcile command to repair:", err)
+		}
+	}
+}
+
+// WithCompensation runs fn without a database transaction, undoing the
+// writes it registered with Tx.OnRollback if it fails.
+func WithCompensation(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	tx := &Tx{}
+	if err := fn(ctx, tx); err != nil {
+		tx.rollback(context.WithoutCancel(ctx))
+		return err
+	}
+	return nil
+}
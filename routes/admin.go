@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"dwello-api/auth"
+	"dwello-api/handlers"
+	"dwello-api/policy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAdminRoutes(app *fiber.App, h *handlers.AdminHandler) {
+	// Grouping the admin-only routes
+	admin := app.Group("/api/admin", auth.RequirePermission(policy.PermManageUsers))
+
+	// List users
+	admin.Get("/users", h.ListUsers)
+
+	// Change a user's role
+	admin.Put("/users/:email/role", h.UpdateUserRole)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterAuthRoutes mounts the public routes that issue tokens.
+// They must be registered before the auth middleware in Setup.
+func RegisterAuthRoutes(app *fiber.App, h *handlers.AuthHandler) {
+	// Grouping the auth-related routes
+	authGroup := app.Group("/api/auth")
+
+	// Register a new user with a password
+	authGroup.Post("/register", h.RegisterUser)
+
+	// Login with email and password
+	authGroup.Post("/login", h.LoginUser)
+
+	// Passwordless login with a one-time code sent by email
+	authGroup.Post("/otp/request", h.RequestOTP)
+	authGroup.Post("/otp/verify", h.VerifyOTP)
+
+	// Password reset
+	authGroup.Post("/password/forgot", h.ForgotPassword)
+	authGroup.Post("/password/reset", h.ResetPassword)
+
+	// Exchange a refresh token for a new token pair
+	authGroup.Post("/refresh", h.RefreshToken)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterConversationRoutes(app *fiber.App, h *handlers.ConversationHandler) {
+	// Message the owner of a property
+	app.Post("/api/properties/:id/conversations", h.StartConversation)
+
+	// Grouping the conversation routes
+	conversations := app.Group("/api/conversations")
+
+	// List and read the user's conversations
+	conversations.Get("/", h.ListConversations)
+	conversations.Get("/:id", h.GetConversation)
+	conversations.Get("/:id/messages", h.ListMessages)
+
+	// Reply and send read receipts
+	conversations.Post("/:id/messages", h.SendMessage)
+	conversations.Post("/:id/read", h.MarkConversationRead)
+
+	// Live delivery of messages and read receipts
+	app.Get("/api/ws", h.Connect)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterHealthRoutes mounts the public liveness and readiness probes
+func RegisterHealthRoutes(app *fiber.App, h *handlers.HealthHandler) {
+	app.Get("/healthz", h.Live)
+	app.Get("/readyz", h.Ready)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterImageRoutes(app *fiber.App, h *handlers.ImageHandler) {
+	// Upload and delete property pictures
+	app.Post("/api/properties/:id/pictures", h.UploadPropertyPicture)
+	app.Delete("/api/properties/:id/pictures/:imageId", h.DeletePropertyPicture)
+
+	// Replace the user's profile picture
+	app.Put("/api/users/:email/profile-pic", h.UploadProfilePicture)
+}
@@ -1,11 +1,48 @@
 package routes
 
 import (
+	"dwello-api/auth"
+	"dwello-api/blob"
+	"dwello-api/handlers"
+	"dwello-api/health"
+	"dwello-api/mailer"
+	"dwello-api/notify"
+	"dwello-api/payments"
+	"dwello-api/realtime"
+	"dwello-api/repository"
+	"dwello-api/webhook"
+
 	"github.com/gofiber/fiber/v2"
 )
 
-func Setup(app *fiber.App) {
+// Setup mounts every route. hub pushes events to the users connected over
+// WebSocket, notifier delivers the notifications raised by the handlers and
+// checker runs the readiness checks.
+func Setup(app *fiber.App, store repository.Store, m mailer.Mailer, provider payments.Provider, blobs blob.Store, hub *realtime.Hub, notifier *notify.Dispatcher, checker *health.Checker) {
+	viewings := handlers.NewViewingHandler(store.Users, store.Properties, store.ViewingSlots, store.Viewings, notifier)
+
+	// Public routes
+	RegisterHealthRoutes(app, handlers.NewHealthHandler(checker))
+	RegisterAuthRoutes(app, handlers.NewAuthHandler(store.Users, m))
+	RegisterCalendarRoutes(app, viewings)
+
+	// Every API route registered below requires a valid access token
+	app.Use("/api", auth.Middleware(store.Users))
+
+	// Queues events for partners' webhooks
+	webhooks := webhook.NewPublisher(store.Webhooks, store.Deliveries)
+
 	// Mount route groups
-	RegisterUserRoutes(app)
-	RegisterPropertyRoutes(app)
+	RegisterUserRoutes(app, handlers.NewUserHandler(store.Users, store.Properties))
+	RegisterPropertyRoutes(app, handlers.NewPropertyHandler(store.Transactor, store.Users, store.Properties, store.Leases, store.Similarities, store.PropertyChanges, store.RentalRequests, store.ViewingSlots, store.Viewings, blobs, notifier, webhooks))
+	RegisterRentalRequestRoutes(app, handlers.NewRentalRequestHandler(store.Transactor, store.Users, store.Properties, store.RentalRequests, store.Leases, store.Invoices, notifier, webhooks))
+	RegisterLeaseRoutes(app, handlers.NewLeaseHandler(store.Leases))
+	RegisterLedgerRoutes(app, handlers.NewLedgerHandler(store.Transactor, store.Leases, store.Invoices, store.Payments, provider))
+	RegisterImageRoutes(app, handlers.NewImageHandler(store.Users, store.Properties, blobs))
+	RegisterConversationRoutes(app, handlers.NewConversationHandler(store.Users, store.Properties, store.Conversations, store.Messages, hub))
+	RegisterNotificationRoutes(app, handlers.NewNotificationHandler(store.Users, store.Notifications))
+	RegisterViewingRoutes(app, viewings)
+	RegisterSavedSearchRoutes(app, handlers.NewSavedSearchHandler(store.SavedSearches, store.Alerts))
+	RegisterWebhookRoutes(app, handlers.NewWebhookHandler(store.Webhooks, store.Deliveries))
+	RegisterAdminRoutes(app, handlers.NewAdminHandler(store.Users))
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterLeaseRoutes(app *fiber.App, h *handlers.LeaseHandler) {
+	// Grouping the lease routes
+	leases := app.Group("/api/leases")
+
+	// List the user's leases as tenant or owner
+	leases.Get("/", h.ListLeases)
+
+	// Get a single lease with its history
+	leases.Get("/:id", h.GetLease)
+
+	// Owner offers a renewal, tenant accepts or declines it
+	leases.Post("/:id/renewal", h.ProposeRenewal)
+	leases.Post("/:id/renewal/accept", h.AcceptRenewal)
+	leases.Post("/:id/renewal/decline", h.DeclineRenewal)
+
+	// Either party ends the lease early
+	leases.Post("/:id/terminate", h.TerminateLease)
+
+	// Get the current lease of a property
+	app.Get("/api/properties/:id/lease", h.GetPropertyLease)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterLedgerRoutes(app *fiber.App, h *handlers.LedgerHandler) {
+	// Grouping the invoice routes
+	invoices := app.Group("/api/invoices")
+
+	// List invoices owed by the user or to them
+	invoices.Get("/", h.ListInvoices)
+
+	// Get a single invoice
+	invoices.Get("/:id", h.GetInvoice)
+
+	// Owner records a payment made outside the app
+	invoices.Post("/:id/payments", h.RecordPayment)
+
+	// Tenant pays through the payment provider
+	invoices.Post("/:id/pay", h.PayInvoice)
+
+	// List payments made or received
+	app.Get("/api/payments", h.ListPayments)
+
+	// Statements per lease and for all of the user's leases
+	app.Get("/api/leases/:id/statement", h.GetLeaseStatement)
+	app.Get("/api/statements", h.ListStatements)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterNotificationRoutes(app *fiber.App, h *handlers.NotificationHandler) {
+	// Grouping the notification routes
+	notifications := app.Group("/api/notifications")
+
+	// The user's notification feed
+	notifications.Get("/", h.ListNotifications)
+	notifications.Post("/read-all", h.MarkAllNotificationsRead)
+	notifications.Post("/:id/read", h.MarkNotificationRead)
+
+	// Which notifications are delivered on which channel
+	notifications.Get("/preferences", h.GetNotificationPreferences)
+	notifications.Put("/preferences", h.UpdateNotificationPreferences)
+
+	// Devices receiving push notifications
+	notifications.Post("/devices", h.RegisterPushToken)
+	notifications.Delete("/devices/:token", h.UnregisterPushToken)
+}
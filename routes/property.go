@@ -6,34 +6,40 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterPropertyRoutes(app *fiber.App) {
+func RegisterPropertyRoutes(app *fiber.App, h *handlers.PropertyHandler) {
 	// Grouping the property-related routes
 	property := app.Group("/api/properties")
 
 	// Create a new property
-	property.Post("/", handlers.CreateProperty)
+	property.Post("/", h.CreateProperty)
 
-	// Update an existing property
-	property.Put("/:id", handlers.UpdateProperty)
+	// Change some fields of a property with a merge patch
+	property.Patch("/:id", h.PatchProperty)
+
+	// List the changes made to a property
+	property.Get("/:id/changes", h.ListPropertyChanges)
 
 	// Delete a property
-	property.Delete("/:id", handlers.DeleteProperty)
+	property.Delete("/:id", h.DeleteProperty)
 
 	// Like a property
-	property.Post("/:id/like", handlers.LikeProperty)
+	property.Post("/:id/like", h.LikeProperty)
 
 	// Unlike a property
-	property.Post("/:id/unlike", handlers.UnlikeProperty)
+	property.Post("/:id/unlike", h.UnlikeProperty)
 
 	// Get user liked properties
-	property.Get("/liked-properties", handlers.GetLikedPropertiesByUser)
+	property.Get("/liked-properties", h.GetLikedPropertiesByUser)
 
 	// Search for properties
-	property.Get("/search", handlers.SearchProperties)
+	property.Get("/search", h.SearchProperties)
+
+	// Find properties within a radius, nearest first
+	property.Get("/nearby", h.NearbyProperties)
 
-	// Get properties for the homescreen based on preferred location
-	property.Get("/homescreen", handlers.GetHomescreenProperties)
+	// Get properties for the homescreen, ranked for the user
+	property.Get("/homescreen", h.GetHomescreenProperties)
 
-	// Rental features
-	property.Post("/:id/rent", handlers.RequestToRentProperty)
+	// Get alternatives to a property
+	property.Get("/:id/similar", h.GetSimilarProperties)
 }
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRentalRequestRoutes(app *fiber.App, h *handlers.RentalRequestHandler) {
+	// Grouping the rental request routes
+	requests := app.Group("/api/rental-requests")
+
+	// Send a rental request
+	requests.Post("/", h.CreateRentalRequest)
+
+	// List requests sent by the user or received for their properties
+	requests.Get("/", h.ListRentalRequests)
+
+	// Get a single request with its history
+	requests.Get("/:id", h.GetRentalRequest)
+
+	// Withdraw a pending request
+	requests.Post("/:id/withdraw", h.WithdrawRentalRequest)
+
+	// Accept or reject a pending request
+	requests.Post("/:id/decision", h.DecideRentalRequest)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterSavedSearchRoutes(app *fiber.App, h *handlers.SavedSearchHandler) {
+	// Grouping the saved search routes
+	searches := app.Group("/api/saved-searches")
+
+	// Save, list, get and delete the user's searches
+	searches.Post("/", h.CreateSavedSearch)
+	searches.Get("/", h.ListSavedSearches)
+	searches.Get("/:id", h.GetSavedSearch)
+	searches.Delete("/:id", h.DeleteSavedSearch)
+
+	// Alerts of new listings matching the saved searches
+	alerts := app.Group("/api/alerts")
+	alerts.Get("/", h.ListAlerts)
+	alerts.Post("/read-all", h.MarkAllAlertsRead)
+	alerts.Post("/:id/read", h.MarkAlertRead)
+}
@@ -6,35 +6,31 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterUserRoutes(app *fiber.App) {
+func RegisterUserRoutes(app *fiber.App, h *handlers.UserHandler) {
 	// Grouping the user-related routes
 	user := app.Group("/api/users")
 
-	// Register a new user or login
-	user.Post("/register", handlers.RegisterUser)
+	// Get the authenticated user
+	user.Get("/me", h.GetCurrentUser)
+
+	// Switch between the tenant and owner roles
+	user.Put("/me/role", h.UpdateCurrentUserRole)
 
 	// Get user details by email
-	user.Get("/:email", handlers.GetUserByEmail)
+	user.Get("/:email", h.GetUserByEmail)
 
 	// Update user location
-	user.Put("/:email/location", handlers.UpdateUserLocation)
+	user.Put("/:email/location", h.UpdateUserLocation)
 
 	// Update user preferred locations
-	user.Put("/:email/preferred-locations", handlers.UpdatePreferredLocations)
+	user.Put("/:email/preferred-locations", h.UpdatePreferredLocations)
 
 	// Get properties liked by the user
-	user.Get("/:email/liked-properties", handlers.GetLikedProperties)
+	user.Get("/:email/liked-properties", h.GetLikedProperties)
 
 	// Get properties posted by the user
-	user.Get("/:email/posted-properties", handlers.GetPostedProperties)
+	user.Get("/:email/posted-properties", h.GetPostedProperties)
 
 	// Get properties rented by this user
-	user.Get("/:email/rented-properties", handlers.GetRentedPropertiesByUser)
-
-	// Get rental requests received for this user's properties
-	user.Get("/:email/rental-requests", handlers.GetRentalRequestsForUserProperties)
-
-	// Handle rental request for a property
-	user.Post("/rental-requests/:id/handle", handlers.HandleRentalRequest)
-
+	user.Get("/:email/rented-properties", h.GetRentedPropertiesByUser)
 }
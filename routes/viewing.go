@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"dwello-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterCalendarRoutes mounts the calendar feeds, which authenticate with
+// the token in their URL. They must be registered before the auth
+// middleware in Setup.
+func RegisterCalendarRoutes(app *fiber.App, h *handlers.ViewingHandler) {
+	app.Get("/api/calendar/:token/viewings.ics", h.GetCalendarFeed)
+}
+
+func RegisterViewingRoutes(app *fiber.App, h *handlers.ViewingHandler) {
+	// Publish, list and remove the viewing slots of a property
+	app.Post("/api/properties/:id/viewing-slots", h.CreateViewingSlot)
+	app.Get("/api/properties/:id/viewing-slots", h.ListViewingSlots)
+	app.Delete("/api/properties/:id/viewing-slots/:slotId", h.DeleteViewingSlot)
+
+	// Grouping the viewing routes
+	viewings := app.Group("/api/viewings")
+
+	// Book a viewing in an available slot
+	viewings.Post("/", h.BookViewing)
+
+	// Issue the URLs of the user's calendar feeds
+	viewings.Post("/calendar", h.CreateCalendarFeeds)
+
+	// List viewings booked by the user or at their properties
+	viewings.Get("/", h.ListViewings)
+	viewings.Get("/:id", h.GetViewing)
+
+	// Move or call off a scheduled viewing
+	viewings.Post("/:id/reschedule", h.RescheduleViewing)
+	viewings.Post("/:id/cancel", h.CancelViewing)
+}
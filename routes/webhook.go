@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"dwello-api/auth"
+	"dwello-api/handlers"
+	"dwello-api/policy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterWebhookRoutes(app *fiber.App, h *handlers.WebhookHandler) {
+	// Grouping the admin-only webhook routes
+	webhooks := app.Group("/api/webhooks", auth.RequirePermission(policy.PermManageWebhooks))
+
+	// Manage the subscriptions
+	webhooks.Post("/", h.CreateWebhook)
+	webhooks.Get("/", h.ListWebhooks)
+	webhooks.Get("/:id", h.GetWebhook)
+	webhooks.Put("/:id", h.UpdateWebhook)
+	webhooks.Delete("/:id", h.DeleteWebhook)
+
+	// Delivery log and manual redelivery
+	webhooks.Get("/:id/deliveries", h.ListDeliveries)
+	webhooks.Get("/:id/deliveries/:deliveryId", h.GetDelivery)
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", h.RedeliverDelivery)
+}
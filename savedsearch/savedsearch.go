@@ -0,0 +1,139 @@
+// Package savedsearch matches new listings against the searches users saved
+// and alerts them of the matches. Alerts are keyed per saved search and
+// property so matching the same listing again is harmless.
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"dwello-api/mailer"
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPerUser is how many searches a user may save
+const MaxPerUser = 20
+
+// Search turns saved filters into a property search
+func Search(filters *models.SearchFilters) repository.PropertySearch {
+	return repository.PropertySearch{
+		Query:        filters.Query,
+		Location:     filters.Location,
+		MinPrice:     filters.MinPrice,
+		MaxPrice:     filters.MaxPrice,
+		Types:        filters.Types,
+		MinBedrooms:  filters.MinBedrooms,
+		MaxBedrooms:  filters.MaxBedrooms,
+		MinBathrooms: filters.MinBathrooms,
+		MinArea:      filters.MinArea,
+		MaxArea:      filters.MaxArea,
+		Furnishing:   filters.Furnishing,
+		PetsAllowed:  filters.PetsAllowed,
+		Amenities:    filters.Amenities,
+		Box:          filters.Box,
+	}
+}
+
+// Match checks the listings created since each saved search was last
+// checked against it, stores an alert for every match and emails it when
+// the user asked for email alerts. It returns how many alerts were created.
+func Match(ctx context.Context, store repository.Store, m mailer.Mailer) (int, error) {
+	searches, err := store.SavedSearches.List(ctx, repository.SavedSearchFilter{}, repository.Page{})
+	if err != nil || len(searches) == 0 {
+		return 0, err
+	}
+
+	// Load the listings new to the least recently checked search once
+	since := searches[0].CheckedUntil
+	for _, search := range searches[1:] {
+		since = min(since, search.CheckedUntil)
+	}
+	listings, err := store.Properties.Search(ctx, repository.PropertySearch{CreatedAfter: since.Time()}, repository.Page{})
+	if err != nil || len(listings) == 0 {
+		return 0, err
+	}
+
+	created := 0
+	for i := range searches {
+		n, err := match(ctx, store, m, &searches[i], listings)
+		created += n
+		if err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+// match alerts of the listings new to the saved search that match it and
+// moves its checkpoint past them
+func match(ctx context.Context, store repository.Store, m mailer.Mailer, saved *models.SavedSearch, listings []models.PropertySearchResult) (int, error) {
+	newest := saved.CheckedUntil
+	var ids []primitive.ObjectID
+	for _, listing := range listings {
+		if listing.CreatedAt <= saved.CheckedUntil {
+			continue
+		}
+		newest = max(newest, listing.CreatedAt)
+		// Nobody wants to be alerted of their own listing
+		if listing.OwnerEmail != saved.UserEmail {
+			ids = append(ids, listing.ID)
+		}
+	}
+	if newest == saved.CheckedUntil {
+		return 0, nil
+	}
+
+	created := 0
+	if len(ids) > 0 {
+		search := Search(&saved.Filters)
+		search.IDs = ids
+		matches, err := store.Properties.Search(ctx, search, repository.Page{})
+		if err != nil {
+			return 0, err
+		}
+
+		for i := range matches {
+			alert := newAlert(saved, &matches[i].Property)
+			err := store.Alerts.Create(ctx, &alert)
+			if errors.Is(err, repository.ErrDuplicate) {
+				continue
+			}
+			if err != nil {
+				return created, err
+			}
+			created++
+
+			// The alert is in the feed either way, so a failed email is only logged
+			if saved.EmailAlerts {
+				if err := m.Send(ctx, saved.UserEmail, "New listing: "+alert.PropertyTitle, emailBody(&alert)); err != nil {
+					log.Printf("Failed to email alert %s: %v", alert.ID.Hex(), err)
+				}
+			}
+		}
+	}
+	return created, store.SavedSearches.SetCheckedUntil(ctx, saved.ID, newest)
+}
+
+func newAlert(saved *models.SavedSearch, property *models.Property) models.Alert {
+	return models.Alert{
+		UserID:           saved.UserID,
+		SavedSearchID:    saved.ID,
+		SavedSearchName:  saved.Name,
+		PropertyID:       property.ID,
+		PropertyTitle:    property.Title,
+		PropertyLocation: property.Location,
+		PropertyPrice:    property.Price,
+		CreatedAt:        primitive.NewDateTimeFromTime(utils.Now()),
+	}
+}
+
+func emailBody(alert *models.Alert) string {
+	return fmt.Sprintf("A new listing matches your saved search %q:\n\n%s in %s for %.2f a month.\n\nOpen the Dwello app to see it.",
+		alert.SavedSearchName, alert.PropertyTitle, alert.PropertyLocation, alert.PropertyPrice)
+}
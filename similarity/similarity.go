@@ -0,0 +1,213 @@
+// Package similarity finds the listings most like a property, to offer as
+// alternatives when viewing it. Listings are compared by location, price,
+// attributes and co-likes: users who liked one listing and also liked the
+// other. Lists are precomputed for every property by Refresh.
+package similarity
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxSimilar is how many similar listings are kept per property
+const MaxSimilar = 20
+
+// Score weights, normalized so a score ranges from 0 to 1
+const (
+	LocationWeight   = 3
+	PriceWeight      = 2
+	AttributesWeight = 2
+	CoLikeWeight     = 3
+
+	totalWeight = LocationWeight + PriceWeight + AttributesWeight + CoLikeWeight
+)
+
+// PriceBand is how far apart, as a fraction of the higher price, two prices
+// can be and still add to the score
+const PriceBand = 0.5
+
+// liveCandidates is how many listings in the same city are compared when a
+// property's list has not been computed yet
+const liveCandidates = 200
+
+// Score returns how alike two listings are, from 0 to 1
+func Score(a, b *models.Property) float64 {
+	score := LocationWeight*locationScore(a.Location, b.Location) +
+		PriceWeight*priceScore(a.Price, b.Price) +
+		AttributesWeight*attributesScore(&a.Attributes, &b.Attributes) +
+		CoLikeWeight*coLikeScore(a.LikedBy, b.LikedBy)
+	return score / totalWeight
+}
+
+// Similar returns the candidates most like the property, best first and
+// newest first on equal scores.
+// Candidates only qualify when they are available and in the same city as
+// the property or liked by someone who liked it.
+func Similar(property *models.Property, candidates []models.Property) []models.SimilarityScore {
+	var similar []models.SimilarityScore
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ID == property.ID || candidate.IsRented {
+			continue
+		}
+		if city(candidate.Location) != city(property.Location) && coLikeScore(candidate.LikedBy, property.LikedBy) == 0 {
+			continue
+		}
+		similar = append(similar, models.SimilarityScore{PropertyID: candidate.ID, Score: Score(property, candidate)})
+	}
+
+	slices.SortFunc(similar, func(a, b models.SimilarityScore) int {
+		if order := cmp.Compare(b.Score, a.Score); order != 0 {
+			return order
+		}
+		return bytes.Compare(b.PropertyID[:], a.PropertyID[:])
+	})
+	if len(similar) > MaxSimilar {
+		similar = similar[:MaxSimilar]
+	}
+	return similar
+}
+
+// Refresh computes the similar listings of every property, replaces the
+// stored lists and drops those of deleted properties. It returns how many
+// lists were saved.
+func Refresh(ctx context.Context, store repository.Store, now time.Time) (int, error) {
+	properties, err := store.Properties.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only listings sharing a city or a liker are compared
+	byCity := map[string][]int{}
+	byLiker := map[string][]int{}
+	for i, p := range properties {
+		byCity[city(p.Location)] = append(byCity[city(p.Location)], i)
+		for _, email := range p.LikedBy {
+			byLiker[email] = append(byLiker[email], i)
+		}
+	}
+
+	computedAt := primitive.NewDateTimeFromTime(now)
+	for i := range properties {
+		property := &properties[i]
+		indexes := slices.Clone(byCity[city(property.Location)])
+		for _, email := range property.LikedBy {
+			indexes = append(indexes, byLiker[email]...)
+		}
+		slices.Sort(indexes)
+		indexes = slices.Compact(indexes)
+
+		candidates := make([]models.Property, len(indexes))
+		for j, index := range indexes {
+			candidates[j] = properties[index]
+		}
+		similar := models.SimilarProperties{PropertyID: property.ID, Similar: Similar(property, candidates), ComputedAt: computedAt}
+		if err := store.Similarities.Save(ctx, &similar); err != nil {
+			return i, err
+		}
+	}
+
+	_, err = store.Similarities.DeleteComputedBefore(ctx, now)
+	return len(properties), err
+}
+
+// Live computes the similar listings of a property that Refresh has not
+// reached yet, comparing it with the newest listings in its city
+func Live(ctx context.Context, properties repository.PropertyRepository, property *models.Property) ([]models.SimilarityScore, error) {
+	search := repository.PropertySearch{Available: true, Location: city(property.Location)}
+	results, err := properties.Search(ctx, search, repository.Page{Sort: repository.SortCreatedAt, Desc: true, Limit: liveCandidates})
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]models.Property, len(results))
+	for i := range results {
+		candidates[i] = results[i].Property
+	}
+	return Similar(property, candidates), nil
+}
+
+// city returns the last comma separated part of a location, so
+// "Baner, Pune" is in "Pune"
+func city(location string) string {
+	if i := strings.LastIndex(location, ","); i >= 0 {
+		location = location[i+1:]
+	}
+	return strings.ToLower(strings.TrimSpace(location))
+}
+
+// locationScore is 1 for the same location and 0.5 for the same city
+func locationScore(a, b string) float64 {
+	switch {
+	case strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)):
+		return 1
+	case city(a) == city(b):
+		return 0.5
+	}
+	return 0
+}
+
+// priceScore is 1 for the same price and falls to 0 at PriceBand apart
+func priceScore(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	ratio := min(a, b) / max(a, b)
+	return max(0, (ratio-(1-PriceBand))/PriceBand)
+}
+
+// attributesScore weighs the same type most, then bedrooms, amenities and
+// furnishing
+func attributesScore(a, b *models.PropertyAttributes) float64 {
+	score := 0.0
+	if a.Type != "" && a.Type == b.Type {
+		score += 0.4
+	}
+	switch diff := a.Bedrooms - b.Bedrooms; {
+	case diff == 0:
+		score += 0.3
+	case diff == 1 || diff == -1:
+		score += 0.15
+	}
+	if a.Furnishing != "" && a.Furnishing == b.Furnishing {
+		score += 0.1
+	}
+	return score + 0.2*overlap(a.Amenities, b.Amenities)
+}
+
+// coLikeScore is the cosine similarity of the users who liked each listing
+func coLikeScore(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, email := range a {
+		if slices.Contains(b, email) {
+			shared++
+		}
+	}
+	return float64(shared) / math.Sqrt(float64(len(a)*len(b)))
+}
+
+// overlap returns the share of the values in either list found in both
+func overlap(a, b []string) float64 {
+	shared := 0
+	for _, v := range a {
+		if slices.Contains(b, v) {
+			shared++
+		}
+	}
+	if union := len(a) + len(b) - shared; union > 0 {
+		return float64(shared) / float64(union)
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+// Package textsearch holds the text matching shared by the property search
+// implementations: splitting a query into terms, scoring and highlighting.
+package textsearch
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Field weights for relevance scoring, mirrored by the MongoDB text index
+const (
+	TitleWeight       = 10
+	LocationWeight    = 5
+	DescriptionWeight = 1
+)
+
+// Terms splits a query into lowercase words, dropping duplicates
+func Terms(query string) []string {
+	var terms []string
+	for _, word := range words(query) {
+		if term := strings.ToLower(word); !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Score returns the weighted number of terms found in each field. Zero means
+// the property does not match.
+func Score(terms []string, title, location, description string) float64 {
+	score := 0.0
+	for _, term := range terms {
+		if contains(title, term) {
+			score += TitleWeight
+		}
+		if contains(location, term) {
+			score += LocationWeight
+		}
+		if contains(description, term) {
+			score += DescriptionWeight
+		}
+	}
+	return score
+}
+
+// Highlight wraps every word of text that matches one of the terms in <em>
+// tags. The rest of the text is HTML-escaped, so the result is safe to render
+// as HTML. It returns an empty string when nothing matched.
+func Highlight(text string, terms []string) string {
+	var b strings.Builder
+	found := false
+	rest := text
+	for len(rest) > 0 {
+		start := strings.IndexFunc(rest, isWordRune)
+		if start < 0 {
+			b.WriteString(html.EscapeString(rest))
+			break
+		}
+		end := strings.IndexFunc(rest[start:], func(r rune) bool { return !isWordRune(r) })
+		if end < 0 {
+			end = len(rest)
+		} else {
+			end += start
+		}
+
+		word := rest[start:end]
+		b.WriteString(html.EscapeString(rest[:start]))
+		if matchesAny(word, terms) {
+			b.WriteString("<em>" + html.EscapeString(word) + "</em>")
+			found = true
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		rest = rest[end:]
+	}
+	if !found {
+		return ""
+	}
+	return b.String()
+}
+
+// contains reports whether any word of text matches the term
+func contains(text, term string) bool {
+	for _, word := range words(text) {
+		if matches(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(word string, terms []string) bool {
+	return slices.ContainsFunc(terms, func(term string) bool { return matches(word, term) })
+}
+
+// matches reports whether the word starts with the term, ignoring case and a
+// plural "s" so that "flats" finds "flat" the way the text index does
+func matches(word, term string) bool {
+	word = strings.ToLower(word)
+	if len(term) > 3 {
+		term = strings.TrimSuffix(term, "s")
+	}
+	return strings.HasPrefix(word, term)
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) })
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
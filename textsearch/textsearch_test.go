@@ -0,0 +1,79 @@
+package textsearch
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"lowercased", "Garden Flat", []string{"garden", "flat"}},
+		{"punctuation splits words", "2-bed, near-station!", []string{"2", "bed", "near", "station"}},
+		{"duplicates dropped", "flat FLAT Flat pune", []string{"flat", "pune"}},
+		{"letters beyond ASCII", "Café Zürich", []string{"café", "zürich"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terms(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("Terms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	const title, location, description = "Garden flat", "Pune", "Sunny flat with a garden and parking"
+
+	tests := []struct {
+		name  string
+		query string
+		want  float64
+	}{
+		{"no match", "villa", 0},
+		{"title and description", "garden", TitleWeight + DescriptionWeight},
+		{"location only", "pune", LocationWeight},
+		{"description only", "parking", DescriptionWeight},
+		{"terms add up", "flat pune", TitleWeight + DescriptionWeight + LocationWeight},
+		{"prefix", "gard", TitleWeight + DescriptionWeight},
+		{"plural", "flats", TitleWeight + DescriptionWeight},
+		{"short words keep their s", "sus", 0},
+		{"middle of a word", "arden", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(Terms(tt.query), title, location, description); got != tt.want {
+				t.Errorf("Score(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"nothing matched", "Garden flat", "villa", ""},
+		{"every match wrapped", "Flat with a flat roof", "flat", "<em>Flat</em> with a <em>flat</em> roof"},
+		{"whole word wrapped on a prefix", "Gardens galore", "gard", "<em>Gardens</em> galore"},
+		{
+			"text escaped", `<b>Flat</b> & "garden"`, "flat",
+			`&lt;b&gt;<em>Flat</em>&lt;/b&gt; &amp; &#34;garden&#34;`,
+		},
+		{"markup in the query is not a word", "Flat <script>", "<script>", "Flat &lt;<em>script</em>&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, Terms(tt.query)); got != tt.want {
+				t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -15,8 +15,12 @@ func CustomTimeout(seconds int) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), time.Duration(seconds)*time.Second)
 }
 
-// DatabaseContext creates a context with a timeout of 10 seconds for database operations.
+// DatabaseTimeout bounds the database operations of a request. It is set
+// from the configuration at startup.
+var DatabaseTimeout = 10 * time.Second
+
+// DatabaseContext creates a context with a timeout of DatabaseTimeout for database operations.
 // It returns the context and a cancel function to release resources when done.
 func DatabaseContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+	return context.WithTimeout(context.Background(), DatabaseTimeout)
 }
@@ -1,7 +1,49 @@
 package utils
 
 import (
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var Validate = validator.New()
+var Validate = newValidator()
+
+// newValidator returns a validator that reports fields by their JSON names,
+// as clients know them, and knows the formats of the API:
+//
+//	objectid  a hex document ID
+//	rfc3339   a timestamp such as 2025-06-01T10:00:00Z
+//	date      a day such as 2025-06-01, or an RFC 3339 timestamp
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("objectid", func(fl validator.FieldLevel) bool {
+		return primitive.IsValidObjectID(fl.Field().String())
+	})
+	v.RegisterValidation("rfc3339", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(time.RFC3339, fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("date", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if _, err := time.Parse(time.DateOnly, value); err == nil {
+			return true
+		}
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	})
+	return v
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it fails
+	MaxAttempts = 10
+	// FirstRetryDelay doubles after each failed attempt up to MaxRetryDelay,
+	// so a delivery is retried for about six hours
+	FirstRetryDelay = time.Minute
+	MaxRetryDelay   = 2 * time.Hour
+	// RequestTimeout bounds each POST
+	RequestTimeout = 10 * time.Second
+
+	// Deliveries are claimed in batches, each kept from other workers long
+	// enough for every POST of the batch to time out
+	batchSize     = 10
+	claimDuration = 5 * time.Minute
+)
+
+// NewClient returns the HTTP client deliveries are sent with. Redirects are
+// not followed: a webhook must answer at its registered URL.
+func NewClient() *http.Client {
+	return &http.Client{
+		Timeout: RequestTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// RetryDelay returns how long to wait after the given number of failed attempts
+func RetryDelay(attempts int) time.Duration {
+	delay := FirstRetryDelay
+	for i := 1; i < attempts && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRetryDelay)
+}
+
+// Deliver sends the deliveries that are due until none are left, and
+// returns how many succeeded
+func Deliver(ctx context.Context, webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, client *http.Client) (int, error) {
+	succeeded := 0
+	for ctx.Err() == nil {
+		now := utils.Now()
+		due, err := deliveries.ClaimDue(ctx, now, now.Add(claimDuration), batchSize)
+		if err != nil {
+			return succeeded, err
+		}
+		if len(due) == 0 {
+			break
+		}
+
+		for _, delivery := range due {
+			ok, err := attempt(ctx, webhooks, deliveries, client, &delivery)
+			if err != nil {
+				return succeeded, err
+			}
+			if ok {
+				succeeded++
+			}
+		}
+	}
+	return succeeded, nil
+}
+
+// attempt POSTs the delivery once and records the outcome. It reports
+// whether the webhook accepted it.
+func attempt(ctx context.Context, webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, client *http.Client, delivery *models.WebhookDelivery) (bool, error) {
+	start := utils.Now()
+	record := models.WebhookAttempt{At: primitive.NewDateTimeFromTime(start)}
+
+	webhook, err := webhooks.FindByID(ctx, delivery.WebhookID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		record.Error = "webhook was deleted"
+		return false, deliveries.RecordAttempt(ctx, delivery.ID, record, models.DeliveryFailed, nil)
+	case err != nil:
+		return false, err
+	case !webhook.Active:
+		record.Error = "webhook is inactive"
+		return false, deliveries.RecordAttempt(ctx, delivery.ID, record, models.DeliveryFailed, nil)
+	}
+
+	record.StatusCode, err = post(ctx, client, webhook, delivery, start)
+	record.DurationMS = utils.Now().Sub(start).Milliseconds()
+	if err == nil {
+		return true, deliveries.RecordAttempt(ctx, delivery.ID, record, models.DeliverySucceeded, nil)
+	}
+
+	record.Error = err.Error()
+	attempts := len(delivery.Attempts) + 1
+	if attempts >= MaxAttempts {
+		return false, deliveries.RecordAttempt(ctx, delivery.ID, record, models.DeliveryFailed, nil)
+	}
+	next := start.Add(RetryDelay(attempts))
+	return false, deliveries.RecordAttempt(ctx, delivery.ID, record, models.DeliveryPending, &next)
+}
+
+// post sends the signed payload and returns the response status. Any status
+// other than 2xx is an error.
+func post(ctx context.Context, client *http.Client, webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Dwello-Webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, now, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,196 @@
+// Package webhook queues events for partners' webhooks and delivers them as
+// signed POST requests, retrying failures with exponential backoff.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex signature>", see Sign
+	SignatureHeader = "X-Dwello-Signature"
+	// EventHeader carries the event type
+	EventHeader = "X-Dwello-Event"
+	// DeliveryHeader carries the delivery ID, which differs on redelivery
+	DeliveryHeader = "X-Dwello-Delivery"
+)
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	// ID identifies the event and is kept on redelivery
+	ID        primitive.ObjectID  `json:"id"`
+	Type      models.WebhookEvent `json:"type"`
+	CreatedAt primitive.DateTime  `json:"created_at"`
+	Data      any                 `json:"data"`
+}
+
+// Property is the data of property events. It leaves out who liked or rented
+// the property: their emails are not shared with partners.
+type Property struct {
+	ID          primitive.ObjectID        `json:"id"`
+	Title       string                    `json:"title"`
+	Description string                    `json:"description"`
+	Price       float64                   `json:"price"`
+	Location    string                    `json:"location"`
+	Coordinates *models.GeoPoint          `json:"coordinates,omitempty"`
+	Attributes  models.PropertyAttributes `json:"attributes"`
+	OwnerEmail  string                    `json:"owner_email"`
+	OwnerName   string                    `json:"owner_name"`
+	IsRented    bool                      `json:"is_rented"`
+	Thumbnail   string                    `json:"thumbnail,omitempty"`
+	Pictures    []string                  `json:"pictures,omitempty"`
+	CreatedAt   primitive.DateTime        `json:"created_at,omitempty"`
+	UpdatedAt   primitive.DateTime        `json:"updated_at,omitempty"`
+}
+
+// NewProperty returns the data of an event about p
+func NewProperty(p *models.Property) Property {
+	return Property{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Price:       p.Price,
+		Location:    p.Location,
+		Coordinates: p.Coordinates,
+		Attributes:  p.Attributes,
+		OwnerEmail:  p.OwnerEmail,
+		OwnerName:   p.OwnerName,
+		IsRented:    p.IsRented,
+		Thumbnail:   p.Thumbnail,
+		Pictures:    p.Pictures,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// RentalRequest is the data of rental request events. It leaves out the
+// applicant's contact details, their message and who changed the request.
+type RentalRequest struct {
+	ID          primitive.ObjectID         `json:"id"`
+	PropertyID  primitive.ObjectID         `json:"property_id"`
+	OwnerEmail  string                     `json:"owner_email"`
+	ApplicantID primitive.ObjectID         `json:"applicant_id"`
+	MoveInDate  primitive.DateTime         `json:"move_in_date,omitempty"`
+	Status      models.RentalRequestStatus `json:"status"`
+	CreatedAt   primitive.DateTime         `json:"created_at,omitempty"`
+	UpdatedAt   primitive.DateTime         `json:"updated_at,omitempty"`
+}
+
+// NewRentalRequest returns the data of an event about r
+func NewRentalRequest(r *models.RentalRequest) RentalRequest {
+	return RentalRequest{
+		ID:          r.ID,
+		PropertyID:  r.PropertyID,
+		OwnerEmail:  r.OwnerEmail,
+		ApplicantID: r.ApplicantID,
+		MoveInDate:  r.MoveInDate,
+		Status:      r.Status,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// Publisher queues events for the webhooks subscribed to them
+type Publisher struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+}
+
+func NewPublisher(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) *Publisher {
+	return &Publisher{webhooks: webhooks, deliveries: deliveries}
+}
+
+// Publish queues a delivery of the event to every active webhook subscribed
+// to it. data is sent to third parties, so properties and rental requests go
+// through NewProperty and NewRentalRequest to leave out the personal data of
+// other users. Failures are logged rather than returned: the action that
+// raised the event has already succeeded.
+func (p *Publisher) Publish(ctx context.Context, event models.WebhookEvent, data any) {
+	webhooks, err := p.webhooks.List(ctx, repository.WebhookFilter{Event: event}, repository.Page{})
+	if err != nil {
+		log.Printf("Failed to list webhooks for %s: %v", event, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	payload := Payload{ID: primitive.NewObjectID(), Type: event, CreatedAt: now, Data: data}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		delivery := models.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     webhook.ID,
+			EventID:       payload.ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        models.DeliveryPending,
+			Attempts:      []models.WebhookAttempt{},
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := p.deliveries.Create(ctx, &delivery); err != nil {
+			log.Printf("Failed to queue %s event for webhook %s: %v", event, webhook.ID.Hex(), err)
+		}
+	}
+}
+
+// Redelivery returns a new delivery of the same payload, due right away
+func Redelivery(original *models.WebhookDelivery, now time.Time) models.WebhookDelivery {
+	at := primitive.NewDateTimeFromTime(now)
+	return models.WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		RedeliveryOf:  &original.ID,
+		Status:        models.DeliveryPending,
+		Attempts:      []models.WebhookAttempt{},
+		NextAttemptAt: &at,
+		CreatedAt:     at,
+		UpdatedAt:     at,
+	}
+}
+
+// Sign returns the signature header of a payload sent at t. Receivers
+// compute the hex HMAC-SHA256 of "<t>.<payload>" keyed with the webhook's
+// secret, compare it with v1 and reject old timestamps to prevent replays.
+func Sign(secret string, t time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
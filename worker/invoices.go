@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"dwello-api/ledger"
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+)
+
+// GenerateInvoices issues the deposit and monthly rent invoices of active
+// leases as their periods start.
+func GenerateInvoices(store repository.Store) Job {
+	return Job{
+		Name:     "generate invoices",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			leases, err := store.Leases.List(ctx, repository.LeaseFilter{Status: models.LeaseActive}, repository.Page{})
+			if err != nil {
+				return err
+			}
+
+			now := utils.Now()
+			total := 0
+			var errs []error
+			for i := range leases {
+				created, err := ledger.Generate(ctx, store.Invoices, &leases[i], now)
+				total += created
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if total > 0 {
+				log.Printf("Issued %d invoices", total)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// ApplyLateFees charges a late fee once for every rent invoice still open
+// ledger.LateFeeGracePeriod after its due date.
+func ApplyLateFees(invoices repository.InvoiceRepository) Job {
+	return Job{
+		Name:     "apply late fees",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			now := utils.Now()
+			overdue, err := invoices.FindOverdue(ctx, now.Add(-ledger.LateFeeGracePeriod))
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for i := range overdue {
+				// A duplicate means the fee was charged but not yet flagged
+				fee := ledger.LateFee(&overdue[i], now)
+				if err := invoices.Create(ctx, &fee); err != nil && !errors.Is(err, repository.ErrDuplicate) {
+					errs = append(errs, err)
+					continue
+				}
+				if err := invoices.MarkLateFeeApplied(ctx, overdue[i].ID); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if charged := len(overdue) - len(errs); charged > 0 {
+				log.Printf("Charged %d late fees", charged)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
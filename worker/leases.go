@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EndLeases closes active leases whose end date has passed and makes their
+// properties available again.
+func EndLeases(store repository.Store) Job {
+	return Job{
+		Name:     "end leases",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			due, err := store.Leases.FindDue(ctx, utils.Now())
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for i := range due {
+				if err := endLease(ctx, store, &due[i]); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if ended := len(due) - len(errs); ended > 0 {
+				log.Printf("Ended %d leases", ended)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func endLease(ctx context.Context, store repository.Store, lease *models.Lease) error {
+	lease.Status = models.LeaseEnded
+	if lease.Termination != nil {
+		lease.Status = models.LeaseTerminated
+	}
+	now := primitive.NewDateTimeFromTime(utils.Now())
+	lease.History = append(lease.History, models.LeaseEvent{Type: models.LeaseClosed, At: now})
+	lease.UpdatedAt = now
+
+	return store.Transactor.WithTransaction(ctx, func(ctx context.Context, tx *repository.Tx) error {
+		// The property or tenant may have been deleted in the meantime
+		err := store.Properties.MarkAvailable(ctx, lease.PropertyID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			tx.OnRollback(func(ctx context.Context) error {
+				return store.Properties.MarkRented(ctx, lease.PropertyID, lease.TenantEmail)
+			})
+		}
+
+		err = store.Users.RemoveRentedProperty(ctx, lease.TenantID, lease.PropertyID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			tx.OnRollback(func(ctx context.Context) error {
+				return store.Users.AddRentedProperty(ctx, lease.TenantID, lease.PropertyID)
+			})
+		}
+
+		return store.Leases.Update(ctx, lease)
+	})
+}
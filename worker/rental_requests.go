@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dwello-api/models"
+	"dwello-api/repository"
+	"dwello-api/utils"
+	"dwello-api/webhook"
+)
+
+// RentalRequestTTL is how long a rental request may stay pending before it expires
+const RentalRequestTTL = 30 * 24 * time.Hour
+
+// ExpireRentalRequests expires pending rental requests that are older than
+// RentalRequestTTL or whose desired move-in date has passed.
+func ExpireRentalRequests(requests repository.RentalRequestRepository, webhooks *webhook.Publisher) Job {
+	return Job{
+		Name:     "expire rental requests",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			now := utils.Now()
+			expired, err := requests.ExpirePending(ctx, now.Add(-RentalRequestTTL), now)
+			for _, request := range expired {
+				webhooks.Publish(ctx, models.EventRentalRequestExpired, webhook.NewRentalRequest(&request))
+			}
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				log.Printf("Expired %d rental requests", len(expired))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dwello-api/mailer"
+	"dwello-api/repository"
+	"dwello-api/savedsearch"
+)
+
+// MatchSavedSearches alerts users of new listings matching their saved searches.
+func MatchSavedSearches(store repository.Store, m mailer.Mailer) Job {
+	return Job{
+		Name:     "match saved searches",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			created, err := savedsearch.Match(ctx, store, m)
+			if created > 0 {
+				log.Printf("Sent %d saved search alerts", created)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"dwello-api/repository"
+	"dwello-api/similarity"
+	"dwello-api/utils"
+)
+
+// ComputeSimilarProperties precomputes the similar listings of every property.
+func ComputeSimilarProperties(store repository.Store) Job {
+	return Job{
+		Name:     "compute similar properties",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+			defer cancel()
+
+			_, err := similarity.Refresh(ctx, store, utils.Now())
+			return err
+		},
+	}
+}
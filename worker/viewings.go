@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dwello-api/notify"
+	"dwello-api/repository"
+	"dwello-api/utils"
+)
+
+// ViewingReminderLead is how long before a viewing both parties are reminded of it
+const ViewingReminderLead = 24 * time.Hour
+
+// RemindViewings reminds visitors and owners of the viewings starting within
+// the next day. Viewings booked less than a day ahead are reminded of on
+// the next run.
+func RemindViewings(viewings repository.ViewingRepository, notifier *notify.Dispatcher) Job {
+	return Job{
+		Name:     "remind viewings",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+
+			now := utils.Now()
+			due, err := viewings.ClaimReminders(ctx, now, now.Add(ViewingReminderLead))
+			for i := range due {
+				viewing := &due[i]
+				notifier.NotifyID(ctx, viewing.VisitorID, notify.ViewingReminder(viewing, false))
+				notifier.NotifyEmail(ctx, viewing.OwnerEmail, notify.ViewingReminder(viewing, true))
+			}
+			if len(due) > 0 {
+				log.Printf("Sent reminders of %d viewings", len(due))
+			}
+			return err
+		},
+	}
+}
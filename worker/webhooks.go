@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dwello-api/repository"
+	"dwello-api/webhook"
+)
+
+// DeliverWebhooks sends the queued webhook deliveries that are due
+func DeliverWebhooks(store repository.Store) Job {
+	client := webhook.NewClient()
+	return Job{
+		Name:     "deliver webhooks",
+		Interval: 10 * time.Second,
+		Run: func(ctx context.Context) error {
+			delivered, err := webhook.Deliver(ctx, store.Webhooks, store.Deliveries, client)
+			if delivered > 0 {
+				log.Printf("Delivered %d webhook events", delivered)
+			}
+			return err
+		},
+	}
+}
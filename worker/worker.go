@@ -0,0 +1,169 @@
+// Package worker runs periodic background jobs such as expiring stale
+// rental requests.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// staleAfter is how many intervals a job may go without a successful run
+// before the pool reports it unhealthy
+const staleAfter = 3
+
+// Pool runs a set of jobs
+type Pool struct {
+	jobs []Job
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	started time.Time
+	stopped bool
+	cancel  context.CancelFunc
+	states  map[string]*state
+}
+
+// state is the outcome of the runs of a job
+type state struct {
+	interval    time.Duration
+	lastSuccess time.Time
+	lastErr     error
+}
+
+func NewPool(jobs ...Job) *Pool {
+	states := map[string]*state{}
+	for _, job := range jobs {
+		states[job.Name] = &state{interval: job.Interval}
+	}
+	return &Pool{jobs: jobs, states: states}
+}
+
+// Start runs every job once right away and then on its interval, each in its
+// own goroutine, until ctx is cancelled or Stop is called. Errors are logged
+// and the job is tried again on the next tick. Starting a stopped pool does
+// nothing.
+func (p *Pool) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped || p.cancel != nil {
+		return
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.started = time.Now()
+	for _, job := range p.jobs {
+		p.wg.Add(1)
+		go p.run(ctx, job)
+	}
+}
+
+// Stop keeps the jobs from running again. Runs in progress are not
+// cancelled so that they finish their work; Wait blocks until they did.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = true
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+func (p *Pool) run(ctx context.Context, job Job) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	runCtx := context.WithoutCancel(ctx)
+	for {
+		err := job.Run(runCtx)
+		if err != nil {
+			log.Printf("Job %q failed: %v", job.Name, err)
+		}
+		p.record(job.Name, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pool) record(name string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.states[name]
+	s.lastErr = err
+	if err == nil {
+		s.lastSuccess = time.Now()
+	}
+}
+
+// Wait blocks until every job returned after Stop, letting runs in progress
+// finish. It gives up when ctx is done.
+func (p *Pool) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errNotStarted is reported by Check before the pool is started
+var errNotStarted = errors.New("not started")
+
+// Check reports the jobs that have not run successfully for several of their
+// intervals, because they keep failing or a run is stuck. A single failure
+// is not reported so that the API does not flap on a transient error.
+func (p *Pool) Check(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started.IsZero() {
+		return errNotStarted
+	}
+
+	now := time.Now()
+	var stale []string
+	for name, s := range p.states {
+		since := s.lastSuccess
+		if since.IsZero() {
+			since = p.started
+		}
+		if now.Sub(since) < staleAfter*s.interval {
+			continue
+		}
+		reason := "no run finished"
+		if s.lastErr != nil {
+			reason = s.lastErr.Error()
+		}
+		stale = append(stale, fmt.Sprintf("%s: %s", name, reason))
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	slices.Sort(stale)
+	return fmt.Errorf("jobs not succeeding: %s", strings.Join(stale, "; "))
+}